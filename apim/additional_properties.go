@@ -0,0 +1,55 @@
+package apim
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// APIAdditionalProperty represents a custom property of an API.
+type APIAdditionalProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Whether the property is displayed in the Developer Portal
+	Display bool `json:"display"`
+}
+
+// APIAdditionalProperties represents the custom properties of an API, sent as a list of properties.
+type APIAdditionalProperties []APIAdditionalProperty
+
+// UnmarshalJSON decodes the custom properties of an API, either a list of properties
+// or the map of property values of API-M 3.2, sorted by name.
+func (p *APIAdditionalProperties) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return json.Unmarshal(data, (*[]APIAdditionalProperty)(p))
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*p = make(APIAdditionalProperties, 0, len(values))
+	for name, value := range values {
+		*p = append(*p, APIAdditionalProperty{Name: name, Value: value})
+	}
+	sort.Slice(*p, func(i, j int) bool { return (*p)[i].Name < (*p)[j].Name })
+	return nil
+}
+
+// legacyAPIReqBody represents the API spec of API-M 3.2, whose custom properties are a map of property values.
+type legacyAPIReqBody struct {
+	*APIReqBody
+	AdditionalProperties map[string]string `json:"additionalProperties"`
+}
+
+// apiSpec returns the given API spec in the form of the API-M version of the client.
+func (c *Client) apiSpec(reqBody *APIReqBody) any {
+	if c.version != "3.2" {
+		return reqBody
+	}
+	values := make(map[string]string, len(reqBody.AdditionalProperties))
+	for _, property := range reqBody.AdditionalProperties {
+		values[property.Name] = property.Value
+	}
+	return &legacyAPIReqBody{APIReqBody: reqBody, AdditionalProperties: values}
+}
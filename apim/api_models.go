@@ -20,6 +20,7 @@ package apim
 
 // APIM represents the information required to interact with the APIM.
 type APIM struct {
+	Version                          string `mapstructure:"version"`
 	Username                         string `mapstructure:"username"`
 	Password                         string `mapstructure:"password"`
 	TokenEndpoint                    string `mapstructure:"tokenEndpoint"`
@@ -27,6 +28,8 @@ type APIM struct {
 	DynamicClientRegistrationContext string `mapstructure:"dynamicClientRegistrationContext"`
 	PublisherEndpoint                string `mapstructure:"publisherEndpoint"`
 	PublisherAPIContext              string `mapstructure:"publisherAPIContext"`
+	PublisherAPIProductContext       string `mapstructure:"publisherAPIProductContext"`
+	PublisherScopeContext            string `mapstructure:"publisherScopeContext"`
 	StoreApplicationContext          string `mapstructure:"storeApplicationContext"`
 	StoreKeyManagerContext           string `mapstructure:"storeKeyManagerContext"`
 	StoreSubscriptionContext         string `mapstructure:"storeSubscriptionContext"`
@@ -66,17 +69,156 @@ type APIOperation struct {
 	ID     string `json:"id,omitempty"`
 	Target string `json:"target,omitempty"`
 	Verb   string `json:"verb,omitempty"`
+	// Names of the scopes the operation is restricted to
+	Scopes []string `json:"scopes,omitempty"`
+	// Security of the operation, e.g. "Application & Application User" or "None"
+	AuthType string `json:"authType,omitempty"`
+	// Throttling tier of the operation
+	ThrottlingPolicy  string                `json:"throttlingPolicy,omitempty"`
+	OperationPolicies *APIOperationPolicies `json:"operationPolicies,omitempty"`
 	// TODO: Add the rest of the fields
 }
 
-type APIEndpointAdvancedConfig struct {
-	URL string `json:"url"`
+// APIOperationPolicies represents the policies applied to the request, response and fault flows of an API operation.
+type APIOperationPolicies struct {
+	Request  []OperationPolicy `json:"request"`
+	Response []OperationPolicy `json:"response"`
+	Fault    []OperationPolicy `json:"fault"`
+}
+
+// OperationPolicy represents an operation policy applied with its parameters.
+type OperationPolicy struct {
+	PolicyName    string         `json:"policyName"`
+	PolicyVersion string         `json:"policyVersion,omitempty"`
+	PolicyID      string         `json:"policyId,omitempty"`
+	Parameters    map[string]any `json:"parameters,omitempty"`
+}
+
+// Scope represents an OAuth scope, bound to the roles allowed to request it.
+type Scope struct {
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Bindings    []string `json:"bindings"`
+	UsageCount  int      `json:"usageCount,omitempty"`
+}
+
+// APIScope represents a scope of an API, either local to the API or a shared scope.
+type APIScope struct {
+	Scope  Scope `json:"scope"`
+	Shared bool  `json:"shared"`
 }
 
+type APIEndpointAdvancedConfig struct {
+	URL string `json:"url"`
+	// Timeout, retry and suspension settings of the endpoint
+	Config *EndpointSettings `json:"config,omitempty"`
+}
+
+// EndpointSettings represents the timeout, retry and suspension settings of an endpoint, the durations in milliseconds.
+// WSO2 API Manager keeps the values as they are sent, strings or numbers.
+type EndpointSettings struct {
+	ActionDuration string `json:"actionDuration,omitempty"`
+	// Action on timeout, fault or discard
+	ActionSelect       string   `json:"actionSelect,omitempty"`
+	RetryTimeOut       string   `json:"retryTimeOut,omitempty"`
+	RetryDelay         string   `json:"retryDelay,omitempty"`
+	RetryErrorCodes    []string `json:"retryErroCode,omitempty"`
+	Factor             string   `json:"factor,omitempty"`
+	SuspendDuration    string   `json:"suspendDuration,omitempty"`
+	SuspendMaxDuration string   `json:"suspendMaxDuration,omitempty"`
+	SuspendErrorCodes  []string `json:"suspendErrorCode,omitempty"`
+}
+
+// APIEndpointConfig represents the endpoints of an API, one of the endpoint types http, address, ws, failover,
+// load_balance, default for dynamic endpoints, awslambda or INLINE for prototyped APIs.
 type APIEndpointConfig struct {
 	EndpointType        string                     `json:"endpoint_type"`
 	SandboxEndpoints    *APIEndpointAdvancedConfig `json:"sandbox_endpoints,omitempty"`
 	ProductionEndpoints *APIEndpointAdvancedConfig `json:"production_endpoints,omitempty"`
+	EndpointSecurity    *APIEndpointSecurityConfig `json:"endpoint_security,omitempty"`
+	// Endpoints of load_balance endpoints, sent as the list of endpoints of an environment
+	SandboxLoadBalanceEndpoints    []APIEndpointAdvancedConfig `json:"-"`
+	ProductionLoadBalanceEndpoints []APIEndpointAdvancedConfig `json:"-"`
+	// Load balancing algorithm and session affinity of load_balance endpoints
+	AlgoClassName     string `json:"algoClassName,omitempty"`
+	AlgoCombo         string `json:"algoCombo,omitempty"`
+	SessionManagement string `json:"sessionManagement,omitempty"`
+	SessionTimeOut    string `json:"sessionTimeOut,omitempty"`
+	// Endpoints tried in order when the endpoint of an environment of failover endpoints fails
+	SandboxFailovers    []APIEndpointAdvancedConfig `json:"sandbox_failovers,omitempty"`
+	ProductionFailovers []APIEndpointAdvancedConfig `json:"production_failovers,omitempty"`
+	// Implementation status of INLINE endpoints
+	ImplementationStatus string `json:"implementation_status,omitempty"`
+	// Credentials of awslambda endpoints, role-supplied or stored
+	AccessMethod        string `json:"access_method,omitempty"`
+	AssumeRole          bool   `json:"assume_role,omitempty"`
+	AmznAccessKey       string `json:"amznAccessKey,omitempty"`
+	AmznSecretKey       string `json:"amznSecretKey,omitempty"`
+	AmznRegion          string `json:"amznRegion,omitempty"`
+	AmznRoleArn         string `json:"amznRoleArn,omitempty"`
+	AmznRoleSessionName string `json:"amznRoleSessionName,omitempty"`
+	AmznRoleRegion      string `json:"amznRoleRegion,omitempty"`
+}
+
+// APIEndpointSecurityConfig represents the security of the production and sandbox endpoints of an API.
+type APIEndpointSecurityConfig struct {
+	Production *EnvironmentEndpointSecurity `json:"production,omitempty"`
+	Sandbox    *EnvironmentEndpointSecurity `json:"sandbox,omitempty"`
+}
+
+// EnvironmentEndpointSecurity represents the credentials the gateway calls the endpoints of an environment with.
+// WSO2 API Manager does not return the secrets, or returns them masked.
+type EnvironmentEndpointSecurity struct {
+	Enabled bool `json:"enabled"`
+	// One of BASIC, DIGEST, OAUTH or APIKEY
+	Type     string `json:"type"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// One of CLIENT_CREDENTIALS or PASSWORD for OAUTH
+	GrantType    string `json:"grantType,omitempty"`
+	TokenURL     string `json:"tokenUrl,omitempty"`
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	// Additional parameters of the token request, an object or its JSON encoding depending on the version
+	CustomParameters any    `json:"customParameters,omitempty"`
+	APIKeyIdentifier string `json:"apiKeyIdentifier,omitempty"`
+	APIKeyValue      string `json:"apiKeyValue,omitempty"`
+	// One of HEADER or QUERY_PARAMETER
+	APIKeyIdentifierType string `json:"apiKeyIdentifierType,omitempty"`
+}
+
+// APIProduct represents an API product, bundling operations of several APIs into one subscribable product.
+type APIProduct struct {
+	ID           string          `json:"id,omitempty"`
+	Name         string          `json:"name"`
+	Context      string          `json:"context"`
+	Description  string          `json:"description,omitempty"`
+	Provider     string          `json:"provider,omitempty"`
+	Visibility   string          `json:"visibility,omitempty"`
+	VisibleRoles []string        `json:"visibleRoles"`
+	Policies     []string        `json:"policies"`
+	APIs         []APIProductAPI `json:"apis"`
+	// The lifecycle state reported by API-M versions without the product lifecycle
+	State           string `json:"state,omitempty"`
+	LifeCycleStatus string `json:"lifeCycleStatus,omitempty"`
+}
+
+// APIProductAPI represents an API bundled by an API product, with the operations of the API the product exposes.
+type APIProductAPI struct {
+	APIID      string         `json:"apiId"`
+	Name       string         `json:"name,omitempty"`
+	Version    string         `json:"version,omitempty"`
+	Operations []APIOperation `json:"operations"`
+}
+
+// APIWebsubSubscriptionConfiguration represents the verification of the subscriptions to a WebSub API.
+type APIWebsubSubscriptionConfiguration struct {
+	Enable           bool   `json:"enable"`
+	Secret           string `json:"secret,omitempty"`
+	SigningAlgorithm string `json:"signingAlgorithm,omitempty"`
+	SignatureHeader  string `json:"signatureHeader,omitempty"`
 }
 
 // APIBusinessInformation represents the  API business information.
@@ -89,11 +231,11 @@ type APIBusinessInformation struct {
 
 // APICorsConfiguration represents the CORS configuration for the API.
 type APICorsConfiguration struct {
-	CorsConfigurationEnabled      bool     `json:"corsConfigurationEnabled,omitempty"`
-	AccessControlAllowOrigins     []string `json:"accessControlAllowOrigins,omitempty" hash:"set"`
-	AccessControlAllowCredentials bool     `json:"accessControlAllowCredentials,omitempty"`
-	AccessControlAllowHeaders     []string `json:"accessControlAllowHeaders,omitempty" hash:"set"`
-	AccessControlAllowMethods     []string `json:"accessControlAllowMethods,omitempty" hash:"set"`
+	CorsConfigurationEnabled      bool     `json:"corsConfigurationEnabled"`
+	AccessControlAllowOrigins     []string `json:"accessControlAllowOrigins" hash:"set"`
+	AccessControlAllowCredentials bool     `json:"accessControlAllowCredentials"`
+	AccessControlAllowHeaders     []string `json:"accessControlAllowHeaders" hash:"set"`
+	AccessControlAllowMethods     []string `json:"accessControlAllowMethods" hash:"set"`
 }
 
 // APIReqBody represents the request of create "API" API call.
@@ -117,7 +259,8 @@ type APIReqBody struct {
 	// ResponseCaching         string `json:"responseCaching,omitempty"`
 	// CacheTimeout            int32  `json:"cacheTimeout,omitempty"`
 	// DestinationStatsEnabled bool   `json:"destinationStatsEnabled,omitempty"`
-	// IsDefaultVersion        bool   `json:"isDefaultVersion"`
+	// Whether the API is invoked when its context is called without a version
+	IsDefaultVersion bool `json:"isDefaultVersion"`
 	// The transport to be set. Accepted values are HTTP, WS
 	Type            string         `json:"type,omitempty"`
 	LifeCycleStatus string         `json:"lifeCycleStatus,omitempty"`
@@ -125,55 +268,90 @@ type APIReqBody struct {
 	Operations      []APIOperation `json:"operations,omitempty" hash:"set"`
 	// // Supported transports for the API (http and/or https).
 	// Transport []string `json:"transport" hash:"set"`
-	// // Search keywords related to the API
-	// Tags []string `json:"tags,omitempty" hash:"set"`
+	// Search keywords related to the API
+	Tags []string `json:"tags" hash:"set"`
+	// Names of the API categories the API belongs to, managed with the Admin REST API
+	Categories []string `json:"categories" hash:"set"`
 	// // The subscription tiers selected for the particular API
 	// Tiers []string `json:"tiers" hash:"set"`
 	// // The policy selected for the particular API
 	// APILevelPolicy string `json:"apiLevelPolicy,omitempty"`
-	// // Name of the Authorization header used for invoking the API. If it is not set, Authorization header name specified in tenant or system level will be used.
-	// AuthorizationHeader string     `json:"authorizationHeader,omitempty"`
+	// Name of the Authorization header used for invoking the API. If it is not set, Authorization header name specified in tenant or system level will be used.
+	AuthorizationHeader string `json:"authorizationHeader,omitempty"`
 	// MaxTps              *APIMaxTps `json:"maxTps,omitempty"`
-	// // The visibility level of the API. Accepts one of the following. PUBLIC, PRIVATE, RESTRICTED OR CONTROLLED.
-	// Visibility string `json:"visibility"`
-	// // The user roles that are able to access the API
-	// VisibleRoles     []string             `json:"visibleRoles,omitempty" hash:"set"`
-	// VisibleTenants   []string             `json:"visibleTenants,omitempty" hash:"set"`
+	// Security schemes of the API, e.g. oauth2, api_key, basic_auth, mutualssl and whether they are mandatory
+	SecurityScheme []string `json:"securityScheme,omitempty"`
+	// Audiences of the access tokens accepted by the API
+	Audiences []string `json:"audiences"`
+	// Key managers the access tokens accepted by the API are issued by, "all" for any key manager
+	KeyManagers []string `json:"keyManagers,omitempty"`
+	// The visibility level of the API. Accepts one of the following. PUBLIC, PRIVATE, RESTRICTED OR CONTROLLED.
+	Visibility string `json:"visibility,omitempty"`
+	// The user roles that are able to access the API
+	VisibleRoles   []string           `json:"visibleRoles" hash:"set"`
+	VisibleTenants []string           `json:"visibleTenants" hash:"set"`
 	EndpointConfig *APIEndpointConfig `json:"endpointConfig,omitempty"`
+	// Verification of the subscriptions to a WebSub API
+	WebsubSubscriptionConfiguration *APIWebsubSubscriptionConfiguration `json:"websubSubscriptionConfiguration,omitempty"`
+	// OAuth scopes of the API, local to the API or shared
+	Scopes []APIScope `json:"scopes,omitempty"`
 	// EndpointSecurity *APIEndpointSecurity `json:"endpointSecurity,omitempty"`
 	// // Comma separated list of gateway environments.
 	// GatewayEnvironments string `json:"gatewayEnvironments,omitempty"`
 	// // Labels of micro-gateway environments attached to the API.
 	// Labels    []Label    `json:"labels,omitempty" hash:"set"`
 	// Sequences []Sequence `json:"sequences,omitempty" hash:"set"`
-	// // The subscription availability. Accepts one of the following. current_tenant, all_tenants or specific_tenants.
-	// SubscriptionAvailability     string   `json:"subscriptionAvailability,omitempty"`
-	// SubscriptionAvailableTenants []string `json:"subscriptionAvailableTenants,omitempty"`
-	// // Map of custom properties of API
-	// AdditionalProperties map[string]string `json:"additionalProperties,omitempty" hash:"set"`
-	// // Is the API is restricted to certain set of publishers or creators or is it visible to all the publishers and creators. If the accessControl restriction is none, this API can be modified by all the publishers and creators, if not it can only be viewable/modifiable by certain set of publishers and creators,  based on the restriction.
-	// AccessControl string `json:"accessControl,omitempty"`
-	// // The user roles that are able to view/modify as API publisher or creator.
-	// AccessControlRoles  []string                `json:"accessControlRoles,omitempty"`
-	// BusinessInformation *APIBusinessInformation `json:"businessInformation,omitempty"`
-	// CorsConfiguration   *APICorsConfiguration   `json:"corsConfiguration,omitempty"`
+	// The subscription availability. Accepts one of the following. current_tenant, all_tenants or specific_tenants.
+	SubscriptionAvailability     string   `json:"subscriptionAvailability,omitempty"`
+	SubscriptionAvailableTenants []string `json:"subscriptionAvailableTenants"`
+	// Custom properties of the API
+	AdditionalProperties APIAdditionalProperties `json:"additionalProperties" hash:"set"`
+	// Is the API is restricted to certain set of publishers or creators or is it visible to all the publishers and creators. If the accessControl restriction is none, this API can be modified by all the publishers and creators, if not it can only be viewable/modifiable by certain set of publishers and creators,  based on the restriction.
+	AccessControl string `json:"accessControl,omitempty"`
+	// The user roles that are able to view/modify as API publisher or creator.
+	AccessControlRoles []string `json:"accessControlRoles"`
+	// Business and technical owners of the API
+	BusinessInformation *APIBusinessInformation `json:"businessInformation,omitempty"`
+	CorsConfiguration   *APICorsConfiguration   `json:"corsConfiguration,omitempty"`
 }
 
 // APICreateResp represents the response of create "API" API call.
 type APICreateResp struct {
 	// UUID of the api registry artifact
-	ID              string             `json:"id,omitempty"`
-	Name            string             `json:"name"`
-	Description     string             `json:"description"`
-	Context         string             `json:"context"`
-	Version         string             `json:"version"`
-	Provider        string             `json:"provider,omitempty"`
-	Type            string             `json:"type"`
-	LifeCycleStatus string             `json:"lifeCycleStatus"`
-	HasThumbnail    bool               `json:"hasThumbnail"`
-	Policies        []string           `json:"policies,omitempty" hash:"set"`
-	EndpointConfig  *APIEndpointConfig `json:"endpointConfig,omitempty"`
-	Operations      []APIOperation     `json:"operations,omitempty" hash:"set"`
+	ID                              string                              `json:"id,omitempty"`
+	Name                            string                              `json:"name"`
+	Description                     string                              `json:"description"`
+	Context                         string                              `json:"context"`
+	Version                         string                              `json:"version"`
+	Provider                        string                              `json:"provider,omitempty"`
+	Type                            string                              `json:"type"`
+	LifeCycleStatus                 string                              `json:"lifeCycleStatus"`
+	HasThumbnail                    bool                                `json:"hasThumbnail"`
+	Policies                        []string                            `json:"policies,omitempty" hash:"set"`
+	EndpointConfig                  *APIEndpointConfig                  `json:"endpointConfig,omitempty"`
+	Operations                      []APIOperation                      `json:"operations,omitempty" hash:"set"`
+	WebsubSubscriptionConfiguration *APIWebsubSubscriptionConfiguration `json:"websubSubscriptionConfiguration,omitempty"`
+	Scopes                          []APIScope                          `json:"scopes"`
+	WorkflowStatus                  string                              `json:"workflowStatus,omitempty"`
+	IsDefaultVersion                bool                                `json:"isDefaultVersion"`
+	Visibility                      string                              `json:"visibility"`
+	VisibleRoles                    []string                            `json:"visibleRoles"`
+	VisibleTenants                  []string                            `json:"visibleTenants"`
+	AccessControl                   string                              `json:"accessControl"`
+	AccessControlRoles              []string                            `json:"accessControlRoles"`
+	SubscriptionAvailability        string                              `json:"subscriptionAvailability"`
+	SubscriptionAvailableTenants    []string                            `json:"subscriptionAvailableTenants"`
+	AuthorizationHeader             string                              `json:"authorizationHeader"`
+	SecurityScheme                  []string                            `json:"securityScheme"`
+	Audiences                       []string                            `json:"audiences"`
+	KeyManagers                     []string                            `json:"keyManagers"`
+	CorsConfiguration               *APICorsConfiguration               `json:"corsConfiguration,omitempty"`
+	BusinessInformation             *APIBusinessInformation             `json:"businessInformation,omitempty"`
+	Tags                            []string                            `json:"tags"`
+	Categories                      []string                            `json:"categories"`
+	AdditionalProperties            APIAdditionalProperties             `json:"additionalProperties"`
+	// Custom properties of the API keyed by their name, reported by API-M 4.x
+	AdditionalPropertiesMap map[string]APIAdditionalProperty `json:"additionalPropertiesMap,omitempty"`
 }
 
 // ApplicationMetadata represents name, id and key of the generated application
@@ -302,35 +480,62 @@ type SubscriptionResp struct {
 	Status string `json:"status"`
 }
 
+// APIProductType is the type reported in the API info of a subscription to an API product.
+const APIProductType = "APIPRODUCT"
+
 // SubscriptionRespApiInfo represents the API info of response of create Subscription API call.
 type SubscriptionRespApiInfo struct {
 	Name     string `json:"name"`
 	Version  string `json:"version"`
 	Provider string `json:"provider"`
+	Type     string `json:"type,omitempty"`
 }
 
 // APISearchInfo represents the API search information.
 type APISearchInfo struct {
-	ID              string             `json:"id"`
-	Name            string             `json:"name"`
-	Description     string             `json:"description"`
-	Context         string             `json:"context"`
-	Version         string             `json:"version"`
-	Provider        string             `json:"provider"`
-	Type            string             `json:"type"`
-	LifeCycleStatus string             `json:"lifeCycleStatus"`
-	HasThumbnail    bool               `json:"hasThumbnail"`
-	Policies        []string           `json:"policies" hash:"set"`
-	EndpointConfig  *APIEndpointConfig `json:"endpointConfig,omitempty"`
-	Operations      []APIOperation     `json:"operations" hash:"set"`
+	ID                              string                              `json:"id"`
+	Name                            string                              `json:"name"`
+	Description                     string                              `json:"description"`
+	Context                         string                              `json:"context"`
+	Version                         string                              `json:"version"`
+	Provider                        string                              `json:"provider"`
+	Type                            string                              `json:"type"`
+	LifeCycleStatus                 string                              `json:"lifeCycleStatus"`
+	HasThumbnail                    bool                                `json:"hasThumbnail"`
+	Policies                        []string                            `json:"policies" hash:"set"`
+	EndpointConfig                  *APIEndpointConfig                  `json:"endpointConfig,omitempty"`
+	Operations                      []APIOperation                      `json:"operations" hash:"set"`
+	WebsubSubscriptionConfiguration *APIWebsubSubscriptionConfiguration `json:"websubSubscriptionConfiguration,omitempty"`
+	Scopes                          []APIScope                          `json:"scopes"`
+	WorkflowStatus                  string                              `json:"workflowStatus,omitempty"`
+	IsDefaultVersion                bool                                `json:"isDefaultVersion"`
+	Visibility                      string                              `json:"visibility"`
+	VisibleRoles                    []string                            `json:"visibleRoles"`
+	VisibleTenants                  []string                            `json:"visibleTenants"`
+	AccessControl                   string                              `json:"accessControl"`
+	AccessControlRoles              []string                            `json:"accessControlRoles"`
+	SubscriptionAvailability        string                              `json:"subscriptionAvailability"`
+	SubscriptionAvailableTenants    []string                            `json:"subscriptionAvailableTenants"`
+	AuthorizationHeader             string                              `json:"authorizationHeader"`
+	SecurityScheme                  []string                            `json:"securityScheme"`
+	Audiences                       []string                            `json:"audiences"`
+	KeyManagers                     []string                            `json:"keyManagers"`
+	CorsConfiguration               *APICorsConfiguration               `json:"corsConfiguration,omitempty"`
+	BusinessInformation             *APIBusinessInformation             `json:"businessInformation,omitempty"`
+	Tags                            []string                            `json:"tags"`
+	Categories                      []string                            `json:"categories"`
+	AdditionalProperties            APIAdditionalProperties             `json:"additionalProperties"`
+	// Custom properties of the API keyed by their name, reported by API-M 4.x
+	AdditionalPropertiesMap map[string]APIAdditionalProperty `json:"additionalPropertiesMap,omitempty"`
 }
 
 // APISearchResp represents the response of search "API" by name API call.
 type APISearchResp struct {
-	Previous string          `json:"previous"`
-	List     []APISearchInfo `json:"list"`
-	Count    int             `json:"count"`
-	Next     string          `json:"next"`
+	Previous   string          `json:"previous"`
+	List       []APISearchInfo `json:"list"`
+	Count      int             `json:"count"`
+	Next       string          `json:"next"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
 }
 
 type APILifecycleInfo struct {
@@ -357,10 +562,11 @@ type ApplicationSearchInfo struct {
 
 // ApplicationSearchResp represents the response of search Application by name API call.
 type ApplicationSearchResp struct {
-	Previous string                  `json:"previous"`
-	List     []ApplicationSearchInfo `json:"list"`
-	Count    int                     `json:"count"`
-	Next     string                  `json:"next"`
+	Previous   string                  `json:"previous"`
+	List       []ApplicationSearchInfo `json:"list"`
+	Count      int                     `json:"count"`
+	Next       string                  `json:"next"`
+	Pagination *Pagination             `json:"pagination,omitempty"`
 }
 
 type KeyManagerSearchInfo struct {
@@ -428,3 +634,61 @@ var AppPlanInputParameterSchemaRaw = `{
     "apis"
   ]
 }`
+
+// GraphQLValidationResp represents the response of the validate GraphQL schema API call.
+type GraphQLValidationResp struct {
+	IsValid      bool   `json:"isValid"`
+	ErrorMessage string `json:"errorMessage"`
+	GraphQLInfo  *struct {
+		Operations []APIOperation `json:"operations"`
+	} `json:"graphQLInfo"`
+}
+
+// GraphQLSchema represents the GraphQL schema of an API.
+type GraphQLSchema struct {
+	Name             string `json:"name"`
+	SchemaDefinition string `json:"schemaDefinition"`
+}
+
+// GraphQLComplexity represents the query complexity value of a field of a GraphQL type.
+type GraphQLComplexity struct {
+	Type            string `json:"type"`
+	Field           string `json:"field"`
+	ComplexityValue int64  `json:"complexityValue"`
+}
+
+// GraphQLComplexityList represents the query complexity values of a GraphQL API.
+type GraphQLComplexityList struct {
+	List []GraphQLComplexity `json:"list"`
+}
+
+// APIRevision represents a revision of an API, a snapshot of the API which can be deployed to gateways.
+type APIRevision struct {
+	ID             string                  `json:"id,omitempty"`
+	DisplayName    string                  `json:"displayName,omitempty"`
+	Description    string                  `json:"description,omitempty"`
+	DeploymentInfo []APIRevisionDeployment `json:"deploymentInfo,omitempty"`
+}
+
+// APIRevisionList represents the response of list API revisions API call.
+type APIRevisionList struct {
+	Count int           `json:"count"`
+	List  []APIRevision `json:"list"`
+}
+
+// APIRevisionDeployment represents the deployment of an API revision to a gateway environment.
+type APIRevisionDeployment struct {
+	RevisionUUID       string `json:"revisionUuid,omitempty"`
+	Name               string `json:"name"`
+	Vhost              string `json:"vhost,omitempty"`
+	DisplayOnDevportal bool   `json:"displayOnDevportal"`
+	Status             string `json:"status,omitempty"`
+	// SuccessDeployedTime is set once the gateways report the deployment as successful, nil if not supported by API-M.
+	SuccessDeployedTime *string `json:"successDeployedTime,omitempty"`
+}
+
+// APIRevisionDeploymentList represents the response of list API revision deployments API call.
+type APIRevisionDeploymentList struct {
+	Count int                     `json:"count"`
+	List  []APIRevisionDeployment `json:"list"`
+}
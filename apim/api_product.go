@@ -0,0 +1,93 @@
+package apim
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+)
+
+// ForAPIProducts returns a copy of the client whose revision, deployment, gateway environments and lifecycle functions
+// apply to API products instead of APIs, as API products share these sub-resources of the Publisher REST API.
+func (c *Client) ForAPIProducts() *Client {
+	products := *c
+	products.publisherAPIEndpoint = c.publisherAPIProductEndpoint
+	products.lifecycleIDParam = "apiProductId"
+	return &products
+}
+
+// CreateAPIProduct creates an API product with the provided API product spec.
+// Returns the created API product and any error encountered.
+func (c *Client) CreateAPIProduct(ctx context.Context, product *APIProduct) (*APIProduct, error) {
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, c.publisherAPIProductEndpoint, product)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APIProduct
+	err = c.send(ctx, CreateAPIProductContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetAPIProduct returns the API product with the given ID and any error encountered.
+func (c *Client) GetAPIProduct(ctx context.Context, productID string) (*APIProduct, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIProductEndpoint, productID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APIProduct
+	err = c.send(ctx, APIProductSearchContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// UpdateAPIProduct replaces the API product with the given ID with the provided API product spec.
+// Returns the updated API product and any error encountered.
+func (c *Client) UpdateAPIProduct(ctx context.Context, productID string, product *APIProduct) (*APIProduct, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIProductEndpoint, productID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, product)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APIProduct
+	err = c.send(ctx, UpdateAPIProductContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// DeleteAPIProduct deletes the API product with the given ID.
+// Returns any error encountered.
+func (c *Client) DeleteAPIProduct(ctx context.Context, productID string) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIProductEndpoint, productID)
+	if err != nil {
+		return err
+	}
+	req, err := c.creatHTTPDELETEAPIRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, APIProductDeleteContext, req, nil, http.StatusOK)
+}
+
+// LifecycleState returns the lifecycle state of the API product,
+// reported as its state by API-M versions without the API product lifecycle.
+func (p *APIProduct) LifecycleState() string {
+	if p.LifeCycleStatus != "" {
+		return strings.ToUpper(p.LifeCycleStatus)
+	}
+	return strings.ToUpper(p.State)
+}
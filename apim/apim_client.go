@@ -20,16 +20,19 @@
 package apim
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
-	"sync"
+	"strconv"
 
+	"github.com/floydspace/terraform-provider-wso2apim/client"
+	"github.com/floydspace/terraform-provider-wso2apim/token"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pkg/errors"
-	"github.com/wso2/openservicebroker-apim/pkg/client"
-	"github.com/wso2/openservicebroker-apim/pkg/log"
-	"github.com/wso2/openservicebroker-apim/pkg/token"
 	"github.com/wso2/openservicebroker-apim/pkg/utils"
 )
 
@@ -53,51 +56,119 @@ const (
 	KeyManagerSearchContext           = "search key manager"
 	SubscriptionSearchContext         = "search Subscription"
 	ApplicationKeySearchContext       = "search application keys"
+	VersionDetectContext              = "detect API-M version"
+	ImportOpenAPIContext              = "import OpenAPI definition"
+	GetOpenAPIContext                 = "get OpenAPI definition"
+	UpdateOpenAPIContext              = "update OpenAPI definition"
+	ValidateGraphQLContext            = "validate GraphQL schema"
+	ImportGraphQLContext              = "import GraphQL schema"
+	GetGraphQLContext                 = "get GraphQL schema"
+	UpdateGraphQLContext              = "update GraphQL schema"
+	GetGraphQLComplexityContext       = "get GraphQL query complexity"
+	UpdateGraphQLComplexityContext    = "update GraphQL query complexity"
+	ImportWSDLContext                 = "import WSDL definition"
+	GetWSDLContext                    = "get WSDL definition"
+	UpdateWSDLContext                 = "update WSDL definition"
+	ImportAsyncAPIContext             = "import AsyncAPI definition"
+	GetAsyncAPIContext                = "get AsyncAPI definition"
+	UpdateAsyncAPIContext             = "update AsyncAPI definition"
+	CopyAPIContext                    = "copy API"
+	CreateAPIProductContext           = "create API product"
+	UpdateAPIProductContext           = "update API product"
+	APIProductSearchContext           = "search API product"
+	APIProductDeleteContext           = "delete API product"
+	CreateScopeContext                = "create shared scope"
+	UpdateScopeContext                = "update shared scope"
+	ScopeSearchContext                = "search shared scope"
+	ScopeDeleteContext                = "delete shared scope"
+	CreateRevisionContext             = "create API revision"
+	GetRevisionContext                = "get API revision"
+	DeleteRevisionContext             = "delete API revision"
+	DeployRevisionContext             = "deploy API revision"
+	UndeployRevisionContext           = "undeploy API revision"
+	GetDeploymentsContext             = "get API revision deployments"
+	GetGatewayEnvironmentsContext     = "get API gateway environments"
+	UpdateGatewayEnvironmentsContext  = "update API gateway environments"
 	ErrMsgAPPIDEmpty                  = "application id is empty"
 )
 
-var (
+// Client is an API-M REST client bound to a single API-M deployment.
+// It owns the resolved endpoints, the token manager and the HTTP client used to reach them.
+type Client struct {
+	httpClient                        *client.Client
+	tokenManager                      token.Manager
+	version                           string
 	publisherAPIEndpoint              string
+	publisherAPIProductEndpoint       string
+	publisherScopeEndpoint            string
+	lifecycleIDParam                  string
 	storeApplicationEndpoint          string
 	storeKeyManagerEndpoint           string
 	storeSubscriptionEndpoint         string
 	storeMultipleSubscriptionEndpoint string
 	applicationDashBoardURLBase       string
-	tokenManager                      token.Manager
-	once                              sync.Once
-)
+}
 
-// Init function initialize the API-M client. If there is an error, process exists with a panic.
-func Init(manager token.Manager, conf APIM) {
-	once.Do(func() {
-		tokenManager = manager
-		publisherAPIEndpoint = createEndpoint(conf.PublisherEndpoint, conf.PublisherAPIContext)
-		storeApplicationEndpoint = createEndpoint(conf.StoreEndpoint, conf.StoreApplicationContext)
-		storeKeyManagerEndpoint = createEndpoint(conf.StoreEndpoint, conf.StoreKeyManagerContext)
-		storeSubscriptionEndpoint = createEndpoint(conf.StoreEndpoint, conf.StoreSubscriptionContext)
-		storeMultipleSubscriptionEndpoint = createEndpoint(conf.StoreEndpoint, conf.StoreMultipleSubscriptionContext)
-		applicationDashBoardURLBase = createEndpoint(conf.StoreEndpoint, "/devportal/applications/")
-	})
-}
-
-// createEndpoint returns a endpoint from the given paths.
-func createEndpoint(paths ...string) string {
+// New returns an API-M client for the given configuration and any error encountered.
+func New(manager token.Manager, httpClient *client.Client, conf APIM) (*Client, error) {
+	c := &Client{
+		httpClient:       httpClient,
+		tokenManager:     manager,
+		version:          conf.Version,
+		lifecycleIDParam: "apiId",
+	}
+	var err error
+	if c.publisherAPIEndpoint, err = createEndpoint(conf.PublisherEndpoint, conf.PublisherAPIContext); err != nil {
+		return nil, err
+	}
+	if c.publisherAPIProductEndpoint, err = createEndpoint(conf.PublisherEndpoint, conf.PublisherAPIProductContext); err != nil {
+		return nil, err
+	}
+	if c.publisherScopeEndpoint, err = createEndpoint(conf.PublisherEndpoint, conf.PublisherScopeContext); err != nil {
+		return nil, err
+	}
+	if c.storeApplicationEndpoint, err = createEndpoint(conf.StoreEndpoint, conf.StoreApplicationContext); err != nil {
+		return nil, err
+	}
+	if c.storeKeyManagerEndpoint, err = createEndpoint(conf.StoreEndpoint, conf.StoreKeyManagerContext); err != nil {
+		return nil, err
+	}
+	if c.storeSubscriptionEndpoint, err = createEndpoint(conf.StoreEndpoint, conf.StoreSubscriptionContext); err != nil {
+		return nil, err
+	}
+	if c.storeMultipleSubscriptionEndpoint, err = createEndpoint(conf.StoreEndpoint, conf.StoreMultipleSubscriptionContext); err != nil {
+		return nil, err
+	}
+	if c.applicationDashBoardURLBase, err = createEndpoint(conf.StoreEndpoint, "/devportal/applications/"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// createEndpoint returns a endpoint from the given paths and any error encountered.
+func createEndpoint(paths ...string) (string, error) {
 	endpoint, err := utils.ConstructURL(paths...)
 	if err != nil {
-		log.HandleErrorAndExit("cannot construct endpoint", err)
+		return "", errors.Wrapf(err, "cannot construct endpoint from %v", paths)
 	}
-	return endpoint
+	return endpoint, nil
+}
+
+// Version returns the API-M version profile the client is configured for.
+// An empty version means the client is configured with explicit contexts.
+func (c *Client) Version() string {
+	return c.version
 }
 
 // CreateAPI function creates an API with the provided API spec.
 // Returns the API ID and any error encountered.
-func CreateAPI(reqBody *APIReqBody) (*APICreateResp, error) {
-	req, err := creatHTTPPOSTAPIRequest(publisherAPIEndpoint, reqBody)
+func (c *Client) CreateAPI(ctx context.Context, reqBody *APIReqBody) (*APICreateResp, error) {
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, c.publisherAPIEndpoint, c.apiSpec(reqBody))
 	if err != nil {
 		return nil, err
 	}
 	var resBody APICreateResp
-	err = send(CreateAPIContext, req, &resBody, http.StatusCreated)
+	err = c.send(ctx, CreateAPIContext, req, &resBody, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -106,38 +177,65 @@ func CreateAPI(reqBody *APIReqBody) (*APICreateResp, error) {
 
 // UpdateAPI updates an existing API under the given ID with the provided API spec.
 // Returns the updated API and any error encountered.
-func UpdateAPI(id string, reqBody *APIReqBody) (*APICreateResp, error) {
-	endpoint, err := utils.ConstructURL(publisherAPIEndpoint, id)
+func (c *Client) UpdateAPI(ctx context.Context, id string, reqBody *APIReqBody) (*APICreateResp, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, id)
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPPUTAPIRequest(endpoint, reqBody)
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, c.apiSpec(reqBody))
 	if err != nil {
 		return nil, err
 	}
 	var resBody APICreateResp
-	err = send(UpdateAPIContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, UpdateAPIContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return &resBody, nil
 }
 
-func ChangeLifeCycleStatus(apiID, action string) (*APIChangeLifeCycleResp, error) {
-	endpoint, err := utils.ConstructURL(publisherAPIEndpoint, "change-lifecycle")
+// CopyAPI creates a new version of the given API as a copy of it, the default version of the API if defaultVersion is true.
+// Returns the created API and any error encountered.
+func (c *Client) CopyAPI(ctx context.Context, apiID, newVersion string, defaultVersion bool) (*APICreateResp, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, "copy-api")
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPPOSTAPIRequest(endpoint, nil)
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	q := url.Values{}
 	q.Add("apiId", apiID)
+	q.Add("newVersion", newVersion)
+	q.Add("defaultVersion", strconv.FormatBool(defaultVersion))
+	req.HTTPRequest().URL.RawQuery = q.Encode()
+	var resBody APICreateResp
+	err = c.send(ctx, CopyAPIContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+func (c *Client) ChangeLifeCycleStatus(ctx context.Context, apiID, action string, checklist map[string]bool) (*APIChangeLifeCycleResp, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, "change-lifecycle")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add(c.lifecycleIDParam, apiID)
 	q.Add("action", action)
+	if len(checklist) > 0 {
+		q.Add("lifecycleChecklist", lifecycleChecklist(checklist))
+	}
 	req.HTTPRequest().URL.RawQuery = q.Encode()
 	var resBody APIChangeLifeCycleResp
-	err = send(ChangeAPILifeCycleContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, ChangeAPILifeCycleContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -145,19 +243,19 @@ func ChangeLifeCycleStatus(apiID, action string) (*APIChangeLifeCycleResp, error
 }
 
 // GetAppDashboardURL returns DashBoard URL for the given Application.
-func GetAppDashboardURL(appID string) string {
-	return applicationDashBoardURLBase + "/" + appID + "/overview"
+func (c *Client) GetAppDashboardURL(appID string) string {
+	return c.applicationDashBoardURLBase + "/" + appID + "/overview"
 }
 
 // CreateApplication creates an application with provided Application spec.
 // Returns the Application ID and any error encountered.
-func CreateApplication(reqBody *ApplicationCreateReq) (*ApplicationSearchInfo, error) {
-	req, err := creatHTTPPOSTAPIRequest(storeApplicationEndpoint, reqBody)
+func (c *Client) CreateApplication(ctx context.Context, reqBody *ApplicationCreateReq) (*ApplicationSearchInfo, error) {
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, c.storeApplicationEndpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
 	var resBody ApplicationSearchInfo
-	err = send(CreateApplicationContext, req, &resBody, http.StatusCreated)
+	err = c.send(ctx, CreateApplicationContext, req, &resBody, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -166,17 +264,17 @@ func CreateApplication(reqBody *ApplicationCreateReq) (*ApplicationSearchInfo, e
 
 // UpdateApplication updates an existing Application under the given ID with the provided Application spec.
 // Returns any error encountered.
-func UpdateApplication(id string, reqBody *ApplicationCreateReq) (*ApplicationSearchInfo, error) {
-	endpoint, err := utils.ConstructURL(storeApplicationEndpoint, id)
+func (c *Client) UpdateApplication(ctx context.Context, id string, reqBody *ApplicationCreateReq) (*ApplicationSearchInfo, error) {
+	endpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, id)
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPPUTAPIRequest(endpoint, reqBody)
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
 	var resBody ApplicationSearchInfo
-	err = send(UpdateApplicationContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, UpdateApplicationContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -185,109 +283,109 @@ func UpdateApplication(id string, reqBody *ApplicationCreateReq) (*ApplicationSe
 
 // GenerateKeys generates keys for the given application.
 // Returns generated keys and any error encountered.
-func GenerateKeys(appID string, reqBody *ApplicationKeyGenerateRequest) (*ApplicationKeyResp, error) {
+func (c *Client) GenerateKeys(ctx context.Context, appID string, reqBody *ApplicationKeyGenerateRequest) (*ApplicationKeyResp, error) {
 	if appID == "" {
 		return nil, errors.New(ErrMsgAPPIDEmpty)
 	}
-	generateApplicationKeyEndpoint, err := utils.ConstructURL(storeApplicationEndpoint, appID, "/generate-keys")
+	generateApplicationKeyEndpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, appID, "/generate-keys")
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot construct endpoint")
 	}
-	req, err := creatHTTPPOSTAPIRequest(generateApplicationKeyEndpoint, reqBody)
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, generateApplicationKeyEndpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
 	var resBody ApplicationKeyResp
-	err = send(GenerateKeyContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, GenerateKeyContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return &resBody, nil
 }
 
-func UpdateApplicationKeys(appID string, keyMappingID string, reqBody *ApplicationKeyGenerateRequest) (*ApplicationKeyResp, error) {
+func (c *Client) UpdateApplicationKeys(ctx context.Context, appID string, keyMappingID string, reqBody *ApplicationKeyGenerateRequest) (*ApplicationKeyResp, error) {
 	if appID == "" {
 		return nil, errors.New(ErrMsgAPPIDEmpty)
 	}
-	endpoint, err := utils.ConstructURL(storeApplicationEndpoint, appID, "/oauth-keys", keyMappingID)
+	endpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, appID, "/oauth-keys", keyMappingID)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot construct endpoint")
 	}
-	req, err := creatHTTPPUTAPIRequest(endpoint, reqBody)
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
 	var resBody ApplicationKeyResp
-	err = send(GenerateKeyContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, GenerateKeyContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return &resBody, nil
 }
 
-func RegenerateKeys(appID string, keyMappingID string) (*ApplicationKeyResp, error) {
+func (c *Client) RegenerateKeys(ctx context.Context, appID string, keyMappingID string) (*ApplicationKeyResp, error) {
 	if appID == "" {
 		return nil, errors.New(ErrMsgAPPIDEmpty)
 	}
-	regenerateApplicationKeyEndpoint, err := utils.ConstructURL(storeApplicationEndpoint, appID, "/oauth-keys", keyMappingID, "/regenerate-secret")
+	regenerateApplicationKeyEndpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, appID, "/oauth-keys", keyMappingID, "/regenerate-secret")
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot construct endpoint")
 	}
-	req, err := creatHTTPPOSTAPIRequest(regenerateApplicationKeyEndpoint, nil)
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, regenerateApplicationKeyEndpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	var resBody ApplicationKeyResp
-	err = send(RegenerateKeyContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, RegenerateKeyContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return &resBody, nil
 }
 
-func CleanupKeys(appID string, keyMappingID string) error {
+func (c *Client) CleanupKeys(ctx context.Context, appID string, keyMappingID string) error {
 	if appID == "" {
 		return errors.New(ErrMsgAPPIDEmpty)
 	}
-	endpoint, err := utils.ConstructURL(storeApplicationEndpoint, appID, "/oauth-keys", keyMappingID, "/clean-up")
+	endpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, appID, "/oauth-keys", keyMappingID, "/clean-up")
 	if err != nil {
 		return err
 	}
-	req, err := creatHTTPPOSTAPIRequest(endpoint, nil)
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, endpoint, nil)
 	if err != nil {
 		return err
 	}
-	err = send(CleanupKeysContext, req, nil, http.StatusOK)
+	err = c.send(ctx, CleanupKeysContext, req, nil, http.StatusOK)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func CreateSubscription(sub *SubscriptionReq) (*SubscriptionSearchInfo, error) {
-	req, err := creatHTTPPOSTAPIRequest(storeSubscriptionEndpoint, sub)
+func (c *Client) CreateSubscription(ctx context.Context, sub *SubscriptionReq) (*SubscriptionSearchInfo, error) {
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, c.storeSubscriptionEndpoint, sub)
 	if err != nil {
 		return nil, err
 	}
 	var resBody SubscriptionSearchInfo
-	err = send(CreateSubscriptionContext, req, &resBody, http.StatusCreated)
+	err = c.send(ctx, CreateSubscriptionContext, req, &resBody, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
 	return &resBody, nil
 }
 
-func UpdateSubscription(id string, reqBody *SubscriptionReq) (*SubscriptionSearchInfo, error) {
-	endpoint, err := utils.ConstructURL(storeSubscriptionEndpoint, id)
+func (c *Client) UpdateSubscription(ctx context.Context, id string, reqBody *SubscriptionReq) (*SubscriptionSearchInfo, error) {
+	endpoint, err := utils.ConstructURL(c.storeSubscriptionEndpoint, id)
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPPUTAPIRequest(endpoint, reqBody)
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
 	var resBody SubscriptionSearchInfo
-	err = send(UpdateSubscriptionContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, UpdateSubscriptionContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -296,13 +394,13 @@ func UpdateSubscription(id string, reqBody *SubscriptionReq) (*SubscriptionSearc
 
 // CreateMultipleSubscriptions creates the given subscriptions.
 // Returns list of SubscriptionResp and any error encountered.
-func CreateMultipleSubscriptions(subs []SubscriptionReq) ([]SubscriptionResp, error) {
-	req, err := creatHTTPPOSTAPIRequest(storeMultipleSubscriptionEndpoint, subs)
+func (c *Client) CreateMultipleSubscriptions(ctx context.Context, subs []SubscriptionReq) ([]SubscriptionResp, error) {
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, c.storeMultipleSubscriptionEndpoint, subs)
 	if err != nil {
 		return nil, err
 	}
 	resBody := make([]SubscriptionResp, 0)
-	err = send(CreateMultipleSubscriptionContext, req, &resBody, http.StatusOK)
+	err = c.send(ctx, CreateMultipleSubscriptionContext, req, &resBody, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -311,16 +409,16 @@ func CreateMultipleSubscriptions(subs []SubscriptionReq) ([]SubscriptionResp, er
 
 // UnSubscribe method removes the given subscription.
 // Returns any error encountered.
-func UnSubscribe(subscriptionID string) error {
-	endpoint, err := utils.ConstructURL(storeSubscriptionEndpoint, subscriptionID)
+func (c *Client) UnSubscribe(ctx context.Context, subscriptionID string) error {
+	endpoint, err := utils.ConstructURL(c.storeSubscriptionEndpoint, subscriptionID)
 	if err != nil {
 		return err
 	}
-	req, err := creatHTTPDELETEAPIRequest(endpoint)
+	req, err := c.creatHTTPDELETEAPIRequest(ctx, endpoint)
 	if err != nil {
 		return err
 	}
-	err = send(UnSubscribeContext, req, nil, http.StatusOK)
+	err = c.send(ctx, UnSubscribeContext, req, nil, http.StatusOK)
 	if err != nil {
 		return err
 	}
@@ -329,16 +427,16 @@ func UnSubscribe(subscriptionID string) error {
 
 // DeleteApplication method deletes the given application.
 // Returns any error encountered.
-func DeleteApplication(applicationID string) error {
-	endpoint, err := utils.ConstructURL(storeApplicationEndpoint, applicationID)
+func (c *Client) DeleteApplication(ctx context.Context, applicationID string) error {
+	endpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, applicationID)
 	if err != nil {
 		return err
 	}
-	req, err := creatHTTPDELETEAPIRequest(endpoint)
+	req, err := c.creatHTTPDELETEAPIRequest(ctx, endpoint)
 	if err != nil {
 		return err
 	}
-	err = send(ApplicationDeleteContext, req, nil, http.StatusOK)
+	err = c.send(ctx, ApplicationDeleteContext, req, nil, http.StatusOK)
 	if err != nil {
 		return err
 	}
@@ -347,16 +445,16 @@ func DeleteApplication(applicationID string) error {
 
 // DeleteAPI method deletes the given API.
 // Returns any error encountered.
-func DeleteAPI(apiID string) error {
-	endpoint, err := utils.ConstructURL(publisherAPIEndpoint, apiID)
+func (c *Client) DeleteAPI(ctx context.Context, apiID string) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID)
 	if err != nil {
 		return err
 	}
-	req, err := creatHTTPDELETEAPIRequest(endpoint)
+	req, err := c.creatHTTPDELETEAPIRequest(ctx, endpoint)
 	if err != nil {
 		return err
 	}
-	err = client.Invoke(APIDeleteContext, req, nil, http.StatusOK)
+	err = c.send(ctx, APIDeleteContext, req, nil, http.StatusOK)
 	if err != nil {
 		return err
 	}
@@ -364,18 +462,29 @@ func DeleteAPI(apiID string) error {
 }
 
 // send sends the given HTTP request, initialize the given response body if it is expected response code.
+// Unsuccessful API calls are returned as *APIError. A request rejected with 401 is retried once with a refreshed token.
 // Returns any error encountered.
-func send(context string, req *client.HTTPRequest, resBody interface{}, expectedRespCode int) error {
-	err := client.Invoke(context, req, resBody, expectedRespCode)
-	if err != nil {
+func (c *Client) send(ctx context.Context, reqContext string, req *client.HTTPRequest, resBody interface{}, expectedRespCode int) error {
+	err := wrapInvokeError(reqContext, c.httpClient.Invoke(ctx, reqContext, req, resBody, expectedRespCode))
+	if !IsUnauthorized(err) {
 		return err
 	}
-	return nil
+	// The access token may have been revoked or expired earlier than expected,
+	// hence force a single refresh and retry the request with the new token.
+	tflog.Debug(ctx, "Access token is rejected, refreshing and retrying the request", map[string]any{"wso2apim_request": reqContext})
+	aT, refreshErr := c.tokenManager.Refresh(ctx, req.Token())
+	if refreshErr != nil {
+		return errors.Wrapf(refreshErr, "unable to refresh the access token rejected by %s", reqContext)
+	}
+	if err := req.SetToken(aT); err != nil {
+		return err
+	}
+	return wrapInvokeError(reqContext, c.httpClient.Invoke(ctx, reqContext, req, resBody, expectedRespCode))
 }
 
 // getBodyReaderAndToken returns a token, a Reader for the given HTTP request body and any error encountered.
-func getBodyReaderAndToken(reqBody interface{}) (string, io.ReadSeeker, error) {
-	aT, err := tokenManager.Token()
+func (c *Client) getBodyReaderAndToken(ctx context.Context, reqBody interface{}) (string, io.ReadSeeker, error) {
+	aT, err := c.tokenManager.Token(ctx)
 	if err != nil {
 		return "", nil, err
 	}
@@ -389,64 +498,90 @@ func getBodyReaderAndToken(reqBody interface{}) (string, io.ReadSeeker, error) {
 	return aT, bodyReader, nil
 }
 
-func creatHTTPGETAPIRequest(endpoint string) (*client.HTTPRequest, error) {
-	aT, err := tokenManager.Token()
+// formField represents a field of a multipart/form-data request body.
+// A field with a file name is sent as a file part.
+type formField struct {
+	name     string
+	fileName string
+	value    string
+}
+
+// creatHTTPMultipartAPIRequest returns a multipart/form-data request with the given fields and any error encountered.
+func (c *Client) creatHTTPMultipartAPIRequest(ctx context.Context, method, endpoint string, fields ...formField) (*client.HTTPRequest, error) {
+	aT, err := c.tokenManager.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
-	req, err := client.CreateHTTPGETRequest(aT, endpoint)
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, f := range fields {
+		var part io.Writer
+		if f.fileName != "" {
+			part, err = w.CreateFormFile(f.name, f.fileName)
+		} else {
+			part, err = w.CreateFormField(f.name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(part, f.value); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	req, err := client.CreateHTTPRequest(ctx, method, endpoint, bytes.NewReader(buf.Bytes()))
 	if err != nil {
 		return nil, err
 	}
-	return req, err
+	req.SetHeader(client.HeaderAuth, client.HeaderBear+aT)
+	req.SetHeader(client.HTTPContentType, w.FormDataContentType())
+	return req, nil
 }
 
-func creatHTTPPOSTAPIRequest(endpoint string, reqBody interface{}) (*client.HTTPRequest, error) {
-	aT, bodyReader, err := getBodyReaderAndToken(reqBody)
+func (c *Client) creatHTTPGETAPIRequest(ctx context.Context, endpoint string) (*client.HTTPRequest, error) {
+	aT, err := c.tokenManager.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
-	req, err := client.CreateHTTPPOSTRequest(aT, endpoint, bodyReader)
+	req, err := client.CreateHTTPGETRequest(ctx, aT, endpoint)
 	if err != nil {
 		return nil, err
 	}
 	return req, err
 }
 
-func creatHTTPDELETEAPIRequest(endpoint string) (*client.HTTPRequest, error) {
-	aT, err := tokenManager.Token()
+func (c *Client) creatHTTPPOSTAPIRequest(ctx context.Context, endpoint string, reqBody interface{}) (*client.HTTPRequest, error) {
+	aT, bodyReader, err := c.getBodyReaderAndToken(ctx, reqBody)
 	if err != nil {
 		return nil, err
 	}
-	req, err := client.CreateHTTPDELETERequest(aT, endpoint)
+	req, err := client.CreateHTTPPOSTRequest(ctx, aT, endpoint, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 	return req, err
 }
 
-// creatAPIMSearchHTTPRequest returns a API-M resource search request and any error encountered.
-func creatAPIMSearchHTTPRequest(endpoint, query string) (*client.HTTPRequest, error) {
-	aT, err := tokenManager.Token()
+func (c *Client) creatHTTPDELETEAPIRequest(ctx context.Context, endpoint string) (*client.HTTPRequest, error) {
+	aT, err := c.tokenManager.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
-	req, err := client.CreateHTTPGETRequest(aT, endpoint)
+	req, err := client.CreateHTTPDELETERequest(ctx, aT, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	q := url.Values{}
-	q.Add("query", query)
-	req.HTTPRequest().URL.RawQuery = q.Encode()
 	return req, err
 }
 
-func creatHTTPPUTAPIRequest(endpoint string, reqBody interface{}) (*client.HTTPRequest, error) {
-	aT, bodyReader, err := getBodyReaderAndToken(reqBody)
+func (c *Client) creatHTTPPUTAPIRequest(ctx context.Context, endpoint string, reqBody interface{}) (*client.HTTPRequest, error) {
+	aT, bodyReader, err := c.getBodyReaderAndToken(ctx, reqBody)
 	if err != nil {
 		return nil, err
 	}
-	req, err := client.CreateHTTPPUTRequest(aT, endpoint, bodyReader)
+	req, err := client.CreateHTTPPUTRequest(ctx, aT, endpoint, bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -454,39 +589,41 @@ func creatHTTPPUTAPIRequest(endpoint string, reqBody interface{}) (*client.HTTPR
 }
 
 // SearchAPIByNameVersion method returns API ID of the Given API.
-// An error is returned if the number of result for the search is not equal to 1.
+// All the pages of the search result are scanned for an API with exactly the given name and version.
+// An error is returned if the number of matching APIs is not equal to 1.
 // Returns API ID and any error encountered.
-func SearchAPIByNameVersion(apiName, version string) (string, error) {
-	query := "name:" + apiName + " version:" + version
-	req, err := creatAPIMSearchHTTPRequest(publisherAPIEndpoint, query)
-	if err != nil {
-		return "", err
+func (c *Client) SearchAPIByNameVersion(ctx context.Context, apiName, version string) (string, error) {
+	it := c.ListAPIs("name:" + apiName + " version:" + version)
+	var matches []APISearchInfo
+	for it.Next(ctx) {
+		api := it.Item()
+		if api.Name == apiName && api.Version == version {
+			matches = append(matches, api)
+		}
 	}
-	var resp APISearchResp
-	err = send(APISearchContext, req, &resp, http.StatusOK)
-	if err != nil {
+	if err := it.Err(); err != nil {
 		return "", err
 	}
-	if resp.Count == 0 {
+	if len(matches) == 0 {
 		return "", errors.New(fmt.Sprintf("couldn't find the API %s", apiName))
 	}
-	if resp.Count > 1 {
+	if len(matches) > 1 {
 		return "", errors.New(fmt.Sprintf("returned more than one API for API %s", apiName))
 	}
-	return resp.List[0].ID, nil
+	return matches[0].ID, nil
 }
 
-func GetAPI(apiID string) (*APISearchInfo, error) {
-	endpoint, err := utils.ConstructURL(publisherAPIEndpoint, apiID)
+func (c *Client) GetAPI(ctx context.Context, apiID string) (*APISearchInfo, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID)
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPGETAPIRequest(endpoint)
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 	var resp APISearchInfo
-	err = send(APISearchContext, req, &resp, http.StatusOK)
+	err = c.send(ctx, APISearchContext, req, &resp, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -494,124 +631,105 @@ func GetAPI(apiID string) (*APISearchInfo, error) {
 }
 
 // SearchApplication method returns Application ID of the Given Application.
-// An error is returned if the number of result for the search is not equal to 1.
+// All the pages of the search result are scanned for an Application with exactly the given name.
+// An error is returned if the number of matching Applications is not equal to 1.
 // Returns Application ID and any error encountered.
-func SearchApplication(appName string) (string, error) {
-	req, err := creatAPIMSearchHTTPRequest(storeApplicationEndpoint, appName)
-	if err != nil {
-		return "", err
+func (c *Client) SearchApplication(ctx context.Context, appName string) (string, error) {
+	it := c.ListApplications(appName)
+	var matches []ApplicationSearchInfo
+	for it.Next(ctx) {
+		app := it.Item()
+		if app.Name == appName {
+			matches = append(matches, app)
+		}
 	}
-	var resp ApplicationSearchResp
-	err = send(ApplicationSearchContext, req, &resp, http.StatusOK)
-	if err != nil {
+	if err := it.Err(); err != nil {
 		return "", err
 	}
-	if resp.Count == 0 {
+	if len(matches) == 0 {
 		return "", errors.New(fmt.Sprintf("couldn't find the Application %s", appName))
 	}
-	if resp.Count > 1 {
+	if len(matches) > 1 {
 		return "", errors.New(fmt.Sprintf("returned more than one Application for %s", appName))
 	}
-	return resp.List[0].ApplicationID, nil
+	return matches[0].ApplicationID, nil
 }
 
-func GetApplication(applicationID string) (*ApplicationSearchInfo, error) {
-	endpoint, err := utils.ConstructURL(storeApplicationEndpoint, applicationID)
+func (c *Client) GetApplication(ctx context.Context, applicationID string) (*ApplicationSearchInfo, error) {
+	endpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, applicationID)
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPGETAPIRequest(endpoint)
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 	var resp ApplicationSearchInfo
-	err = send(ApplicationSearchContext, req, &resp, http.StatusOK)
+	err = c.send(ctx, ApplicationSearchContext, req, &resp, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-func GetSubscription(subID string) (*SubscriptionSearchInfo, error) {
-	endpoint, err := utils.ConstructURL(storeSubscriptionEndpoint, subID)
+func (c *Client) GetSubscription(ctx context.Context, subID string) (*SubscriptionSearchInfo, error) {
+	endpoint, err := utils.ConstructURL(c.storeSubscriptionEndpoint, subID)
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPGETAPIRequest(endpoint)
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 	var resp SubscriptionSearchInfo
-	err = send(SubscriptionSearchContext, req, &resp, http.StatusOK)
+	err = c.send(ctx, SubscriptionSearchContext, req, &resp, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-func SearchKeyManager(keyManagerName string) (*KeyManagerSearchInfo, error) {
-	req, err := creatHTTPGETAPIRequest(storeKeyManagerEndpoint)
-	if err != nil {
-		return nil, err
-	}
-	var resp KeyManagerSearchResp
-	err = send(KeyManagerSearchContext, req, &resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Count == 0 {
-		return nil, errors.New(fmt.Sprintf("couldn't find the KeyManager %s", keyManagerName))
-	}
-	var keyManager KeyManagerSearchInfo
-	for _, km := range resp.List {
-		if km.Name == keyManagerName {
-			keyManager = km
-			break
+// SearchKeyManager method returns the Key Manager with the given name.
+// Returns the Key Manager and any error encountered.
+func (c *Client) SearchKeyManager(ctx context.Context, keyManagerName string) (*KeyManagerSearchInfo, error) {
+	it := c.ListKeyManagers()
+	for it.Next(ctx) {
+		if km := it.Item(); km.Name == keyManagerName {
+			return &km, nil
 		}
 	}
-	if keyManager.ID == "" {
-		return nil, errors.New(fmt.Sprintf("couldn't find the KeyManager %s", keyManagerName))
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
-	return &keyManager, nil
+	return nil, errors.New(fmt.Sprintf("couldn't find the KeyManager %s", keyManagerName))
 }
 
-func GetKeyManager(keyManagerID string) (*KeyManagerSearchInfo, error) {
-	req, err := creatHTTPGETAPIRequest(storeKeyManagerEndpoint)
-	if err != nil {
-		return nil, err
-	}
-	var resp KeyManagerSearchResp
-	err = send(KeyManagerSearchContext, req, &resp, http.StatusOK)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Count == 0 {
-		return nil, errors.New(fmt.Sprintf("couldn't find the KeyManager %s", keyManagerID))
-	}
-	var keyManager KeyManagerSearchInfo
-	for _, km := range resp.List {
-		if km.ID == keyManagerID {
-			keyManager = km
-			break
+// GetKeyManager method returns the Key Manager with the given ID.
+// Returns the Key Manager and any error encountered.
+func (c *Client) GetKeyManager(ctx context.Context, keyManagerID string) (*KeyManagerSearchInfo, error) {
+	it := c.ListKeyManagers()
+	for it.Next(ctx) {
+		if km := it.Item(); km.ID == keyManagerID {
+			return &km, nil
 		}
 	}
-	if keyManager.ID == "" {
-		return nil, errors.New(fmt.Sprintf("couldn't find the KeyManager %s", keyManagerID))
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
-	return &keyManager, nil
+	return nil, errors.New(fmt.Sprintf("couldn't find the KeyManager %s", keyManagerID))
 }
 
-func GetApplicationKeys(applicationID string, keyMappingID string) (*ApplicationKeyResp, error) {
-	endpoint, err := utils.ConstructURL(storeApplicationEndpoint, applicationID, "/oauth-keys", keyMappingID)
+func (c *Client) GetApplicationKeys(ctx context.Context, applicationID string, keyMappingID string) (*ApplicationKeyResp, error) {
+	endpoint, err := utils.ConstructURL(c.storeApplicationEndpoint, applicationID, "/oauth-keys", keyMappingID)
 	if err != nil {
 		return nil, err
 	}
-	req, err := creatHTTPGETAPIRequest(endpoint)
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 	var resp ApplicationKeyResp
-	err = send(ApplicationKeySearchContext, req, &resp, http.StatusOK)
+	err = c.send(ctx, ApplicationKeySearchContext, req, &resp, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -19,11 +19,21 @@
 package apim
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/floydspace/terraform-provider-wso2apim/client"
 	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -33,6 +43,8 @@ const (
 	StoreSubscriptionContext    = "/api/am/store/v1/subscriptions"
 	MultipleSubscriptionContext = StoreSubscriptionContext + "/multiple"
 	PublisherAPIContext         = "/api/am/publisher/v1/apis"
+	PublisherAPIProductContext  = "/api/am/publisher/v1/api-products"
+	PublisherScopeContext       = "/api/am/publisher/v1/scopes"
 	successTestCase             = "success test case"
 	failureTestCase             = "failure test case"
 	ErrMsgTestIncorrectResult   = "expected value: %v but then returned value: %v"
@@ -41,24 +53,35 @@ const (
 type MockTokenManager struct {
 }
 
-func (m *MockTokenManager) Token() (string, error) {
+func (m *MockTokenManager) Token(ctx context.Context) (string, error) {
 	return "token", nil
 }
 
-func (m *MockTokenManager) Init(scopes []string) {
+func (m *MockTokenManager) Init(ctx context.Context, scopes []string) error {
+	return nil
+}
 
+func (m *MockTokenManager) Refresh(ctx context.Context, rejected string) (string, error) {
+	return "refreshed", nil
 }
 
-func init() {
-	Init(&MockTokenManager{}, APIM{
-		StoreEndpoint:                    StoreTestEndpoint,
-		StoreApplicationContext:          StoreApplicationContext,
-		StoreSubscriptionContext:         StoreSubscriptionContext,
-		StoreMultipleSubscriptionContext: MultipleSubscriptionContext,
-		PublisherAPIContext:              PublisherAPIContext,
-		PublisherEndpoint:                publisherTestEndpoint,
-	})
+var testClient = newTestClient(APIM{
+	StoreEndpoint:                    StoreTestEndpoint,
+	StoreApplicationContext:          StoreApplicationContext,
+	StoreSubscriptionContext:         StoreSubscriptionContext,
+	StoreMultipleSubscriptionContext: MultipleSubscriptionContext,
+	PublisherAPIContext:              PublisherAPIContext,
+	PublisherAPIProductContext:       PublisherAPIProductContext,
+	PublisherScopeContext:            PublisherScopeContext,
+	PublisherEndpoint:                publisherTestEndpoint,
+})
 
+func newTestClient(conf APIM) *Client {
+	c, err := New(&MockTokenManager{}, client.Default(), conf)
+	if err != nil {
+		panic(err)
+	}
+	return c
 }
 
 func TestCreateApplication(t *testing.T) {
@@ -76,7 +99,7 @@ func testCreateApplicationFailFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPost, StoreTestEndpoint+StoreApplicationContext, responder)
 
-		_, err = CreateApplication(&ApplicationCreateReq{})
+		_, err = testClient.CreateApplication(context.Background(), &ApplicationCreateReq{})
 		if err == nil {
 			t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusInternalServerError))
 		}
@@ -93,7 +116,7 @@ func testCreateApplicationSuccessFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPost, StoreTestEndpoint+StoreApplicationContext, responder)
 
-		app, err := CreateApplication(&ApplicationCreateReq{
+		app, err := testClient.CreateApplication(context.Background(), &ApplicationCreateReq{
 			Name: "test",
 		})
 		if app.ApplicationID != "1" {
@@ -117,7 +140,7 @@ func testUpdateApplicationFailFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPut, StoreTestEndpoint+StoreApplicationContext+"/id", responder)
 
-		_, err = UpdateApplication("id", &ApplicationCreateReq{
+		_, err = testClient.UpdateApplication(context.Background(), "id", &ApplicationCreateReq{
 			Name: "test",
 		})
 		if err == nil {
@@ -136,7 +159,7 @@ func testUpdateApplicationSuccessFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPut, StoreTestEndpoint+StoreApplicationContext+"/id", responder)
 
-		_, err = UpdateApplication("id", &ApplicationCreateReq{
+		_, err = testClient.UpdateApplication(context.Background(), "id", &ApplicationCreateReq{
 			Name: "test",
 		})
 		if err != nil {
@@ -152,7 +175,7 @@ func TestGenerateKeys(t *testing.T) {
 
 func testGenerateKeysFailFunc() func(t *testing.T) {
 	return func(t *testing.T) {
-		_, err := GenerateKeys("", nil)
+		_, err := testClient.GenerateKeys(context.Background(), "", nil)
 		if err.Error() != ErrMsgAPPIDEmpty {
 			t.Error("Expecting an error : " + ErrMsgAPPIDEmpty + " got: " + err.Error())
 		}
@@ -172,7 +195,7 @@ func testGenerateKeysSuccessFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPost, StoreTestEndpoint+StoreApplicationContext+"/123/generate-keys", responder)
 
-		got, err := GenerateKeys("123", &ApplicationKeyGenerateRequest{
+		got, err := testClient.GenerateKeys(context.Background(), "123", &ApplicationKeyGenerateRequest{
 			ValidityTime:            3600,
 			KeyType:                 "PRODUCTION",
 			Scopes:                  []string{"am_application_scope", "default"},
@@ -202,7 +225,7 @@ func testCreateMultipleSubscriptionFailFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPost, StoreTestEndpoint+MultipleSubscriptionContext, responder)
 
-		_, err = CreateMultipleSubscriptions([]SubscriptionReq{
+		_, err = testClient.CreateMultipleSubscriptions(context.Background(), []SubscriptionReq{
 			{
 				ApiID:            "a",
 				ApplicationID:    "b",
@@ -231,7 +254,7 @@ func testCreateMultipleSubscriptionSuccessFunc() func(t *testing.T) {
 			t.Error(err)
 		}
 		httpmock.RegisterResponder(http.MethodPost, StoreTestEndpoint+MultipleSubscriptionContext, responder)
-		got, err := CreateMultipleSubscriptions([]SubscriptionReq{
+		got, err := testClient.CreateMultipleSubscriptions(context.Background(), []SubscriptionReq{
 			{
 				ApiID:            "a",
 				ApplicationID:    "b",
@@ -270,7 +293,7 @@ func testUnSubscribeFailFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodDelete, StoreTestEndpoint+StoreSubscriptionContext+"/abc", responder)
 
-		err = UnSubscribe("abc")
+		err = testClient.UnSubscribe(context.Background(), "abc")
 		if err == nil {
 			t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusInternalServerError))
 		}
@@ -287,7 +310,7 @@ func testUnSubscribeSuccessFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodDelete, StoreTestEndpoint+StoreSubscriptionContext+"/abc", responder)
 
-		err = UnSubscribe("abc")
+		err = testClient.UnSubscribe(context.Background(), "abc")
 		if err != nil {
 			t.Error(err)
 		}
@@ -309,7 +332,7 @@ func testDeleteApplicationFailFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodDelete, StoreTestEndpoint+StoreApplicationContext+"/abc", responder)
 
-		err = DeleteApplication("abc")
+		err = testClient.DeleteApplication(context.Background(), "abc")
 		if err == nil {
 			t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusInternalServerError))
 		}
@@ -326,7 +349,7 @@ func testDeleteApplicationSuccessFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodDelete, StoreTestEndpoint+StoreApplicationContext+"/abc", responder)
 
-		err = DeleteApplication("abc")
+		err = testClient.DeleteApplication(context.Background(), "abc")
 		if err != nil {
 			t.Error(err)
 		}
@@ -348,7 +371,7 @@ func testDeleteAPIFailFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodDelete, publisherTestEndpoint+PublisherAPIContext+"/abc", responder)
 
-		err = DeleteAPI("abc")
+		err = testClient.DeleteAPI(context.Background(), "abc")
 		if err == nil {
 			t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusInternalServerError))
 		}
@@ -365,13 +388,33 @@ func testDeleteAPISuccessFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodDelete, publisherTestEndpoint+PublisherAPIContext+"/abc", responder)
 
-		err = DeleteAPI("abc")
+		err = testClient.DeleteAPI(context.Background(), "abc")
 		if err != nil {
 			t.Error(err)
 		}
 	}
 }
 
+func TestCopyAPI(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/copy-api", func(req *http.Request) (*http.Response, error) {
+		q := req.URL.Query()
+		if q.Get("apiId") != "abc" || q.Get("newVersion") != "v2" || q.Get("defaultVersion") != "true" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusCreated, &APICreateResp{ID: "def", Version: "v2", IsDefaultVersion: true})
+	})
+
+	api, err := testClient.CopyAPI(context.Background(), "abc", "v2", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.ID != "def" || api.Version != "v2" || !api.IsDefaultVersion {
+		t.Errorf(ErrMsgTestIncorrectResult, "def", api)
+	}
+}
+
 func TestSearchAPIByNameVersion(t *testing.T) {
 	t.Run(successTestCase, testSearchAPIByNameVersionSuccessFunc())
 	t.Run("failure test case 1", testSearchAPIByNameVersionFail1Func())
@@ -383,16 +426,22 @@ func testSearchAPIByNameVersionSuccessFunc() func(t *testing.T) {
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
 		responder, err := httpmock.NewJsonResponder(http.StatusOK, &APISearchResp{
-			Count: 1,
+			Count: 2,
 			List: []APISearchInfo{{
-				ID: "111-111",
+				ID:      "111-111",
+				Name:    "Test",
+				Version: "v1",
+			}, {
+				ID:      "222-222",
+				Name:    "TestV2",
+				Version: "v1",
 			}},
 		})
 		if err != nil {
 			t.Error(err)
 		}
-		httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?query=name%3ATest+version%3Av1", responder)
-		apiID, err := SearchAPIByNameVersion("Test", "v1")
+		httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?limit=100&offset=0&query=name%3ATest+version%3Av1", responder)
+		apiID, err := testClient.SearchAPIByNameVersion(context.Background(), "Test", "v1")
 		if err != nil {
 			t.Error(err)
 		}
@@ -412,8 +461,8 @@ func testSearchAPIByNameVersionFail1Func() func(t *testing.T) {
 		if err != nil {
 			t.Error(err)
 		}
-		httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?query=name%3ATest+version%3Av1", responder)
-		_, err = SearchAPIByNameVersion("Test", "v1")
+		httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?limit=100&offset=0&query=name%3ATest+version%3Av1", responder)
+		_, err = testClient.SearchAPIByNameVersion(context.Background(), "Test", "v1")
 		if err == nil {
 			t.Error("Expecting an error")
 		}
@@ -428,12 +477,16 @@ func testSearchAPIByNameVersionFail2Func() func(t *testing.T) {
 		defer httpmock.DeactivateAndReset()
 		responder, err := httpmock.NewJsonResponder(http.StatusOK, &APISearchResp{
 			Count: 2,
+			List: []APISearchInfo{
+				{ID: "111-111", Name: "Test", Version: "v1"},
+				{ID: "222-222", Name: "Test", Version: "v1"},
+			},
 		})
 		if err != nil {
 			t.Error(err)
 		}
-		httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?query=name%3ATest+version%3Av1", responder)
-		_, err = SearchAPIByNameVersion("Test", "v1")
+		httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?limit=100&offset=0&query=name%3ATest+version%3Av1", responder)
+		_, err = testClient.SearchAPIByNameVersion(context.Background(), "Test", "v1")
 		if err == nil {
 			t.Error("Expecting an error")
 		}
@@ -457,13 +510,14 @@ func testSearchApplicationSuccessFunc() func(t *testing.T) {
 			Count: 1,
 			List: []ApplicationSearchInfo{{
 				ApplicationID: "111-111",
+				Name:          "Test",
 			}},
 		})
 		if err != nil {
 			t.Error(err)
 		}
-		httpmock.RegisterResponder(http.MethodGet, StoreTestEndpoint+StoreApplicationContext+"?query=Test", responder)
-		apiID, err := SearchApplication("Test")
+		httpmock.RegisterResponder(http.MethodGet, StoreTestEndpoint+StoreApplicationContext+"?limit=100&offset=0&query=Test", responder)
+		apiID, err := testClient.SearchApplication(context.Background(), "Test")
 		if err != nil {
 			t.Error(err)
 		}
@@ -483,8 +537,8 @@ func testSearchApplicationFail1Func() func(t *testing.T) {
 		if err != nil {
 			t.Error(err)
 		}
-		httpmock.RegisterResponder(http.MethodGet, StoreTestEndpoint+StoreApplicationContext+"?query=Test", responder)
-		_, err = SearchApplication("Test")
+		httpmock.RegisterResponder(http.MethodGet, StoreTestEndpoint+StoreApplicationContext+"?limit=100&offset=0&query=Test", responder)
+		_, err = testClient.SearchApplication(context.Background(), "Test")
 		if err == nil {
 			t.Error("Expecting an error")
 		}
@@ -500,12 +554,16 @@ func testSearchApplicationFail2Func() func(t *testing.T) {
 		defer httpmock.DeactivateAndReset()
 		responder, err := httpmock.NewJsonResponder(http.StatusOK, &ApplicationSearchResp{
 			Count: 2,
+			List: []ApplicationSearchInfo{
+				{ApplicationID: "111-111", Name: "Test"},
+				{ApplicationID: "222-222", Name: "Test"},
+			},
 		})
 		if err != nil {
 			t.Error(err)
 		}
-		httpmock.RegisterResponder(http.MethodGet, StoreTestEndpoint+StoreApplicationContext+"?query=Test", responder)
-		_, err = SearchApplication("Test")
+		httpmock.RegisterResponder(http.MethodGet, StoreTestEndpoint+StoreApplicationContext+"?limit=100&offset=0&query=Test", responder)
+		_, err = testClient.SearchApplication(context.Background(), "Test")
 		if err == nil {
 			t.Error("Expecting an error")
 		}
@@ -514,3 +572,1059 @@ func testSearchApplicationFail2Func() func(t *testing.T) {
 		}
 	}
 }
+
+func TestClientsAreIndependent(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	otherEndpoint := "https://prod.example.com:9443"
+	httpmock.RegisterResponder(http.MethodDelete, otherEndpoint+StoreApplicationContext+"/abc", responder)
+
+	other := newTestClient(APIM{
+		StoreEndpoint:           otherEndpoint,
+		StoreApplicationContext: StoreApplicationContext,
+	})
+	err = other.DeleteApplication(context.Background(), "abc")
+	if err != nil {
+		t.Error(err)
+	}
+	info := httpmock.GetCallCountInfo()
+	if calls := info[http.MethodDelete+" "+otherEndpoint+StoreApplicationContext+"/abc"]; calls != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, 1, calls)
+	}
+	if testClient.storeApplicationEndpoint == other.storeApplicationEndpoint {
+		t.Errorf("expected clients to have different endpoints but both use %s", other.storeApplicationEndpoint)
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	body := []byte(`{
+		"code": 400,
+		"message": "Bad Request",
+		"description": "Invalid request or validation error",
+		"moreInfo": "",
+		"error": [{"code": "900313", "message": "context", "description": "context cannot be empty"}]
+	}`)
+	err := wrapInvokeError(CreateAPIContext, newAPIError(CreateAPIContext, http.StatusBadRequest, body))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError but got %T", err)
+	}
+	if apiErr.Code != 400 || apiErr.Message != "Bad Request" || len(apiErr.Errors) != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, "parsed error payload", apiErr)
+	}
+	if apiErr.Errors[0].Description != "context cannot be empty" {
+		t.Errorf(ErrMsgTestIncorrectResult, "context cannot be empty", apiErr.Errors[0].Description)
+	}
+	if !IsBadRequest(errors.Wrap(err, "wrapped")) {
+		t.Error("expected IsBadRequest to be true for a wrapped 400 error")
+	}
+	if IsNotFound(err) || IsConflict(err) {
+		t.Error("expected IsNotFound and IsConflict to be false for a 400 error")
+	}
+	if !strings.Contains(err.Error(), "context cannot be empty") {
+		t.Errorf("expected the error message to contain the field error but got %q", err.Error())
+	}
+}
+
+func TestNewAPIErrorWithoutPayload(t *testing.T) {
+	err := newAPIError(APISearchContext, http.StatusNotFound, []byte("<html>not found</html>"))
+	if !IsNotFound(err) {
+		t.Error("expected IsNotFound to be true")
+	}
+	if err.Message != http.StatusText(http.StatusNotFound) {
+		t.Errorf(ErrMsgTestIncorrectResult, http.StatusText(http.StatusNotFound), err.Message)
+	}
+	if IsNotFound(errors.New("plain error")) {
+		t.Error("expected IsNotFound to be false for a plain error")
+	}
+}
+
+func TestIsNotFoundWrapped(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"/deleted",
+		httpmock.NewStringResponder(http.StatusNotFound, `{"code": 404, "message": "Not Found"}`))
+
+	_, err := testClient.GetAPI(context.Background(), "deleted")
+	if !IsNotFound(err) {
+		t.Errorf("expected IsNotFound to be true for the error of a 404 response but got %v", err)
+	}
+	if !IsNotFound(errors.Wrap(err, "wrapped")) {
+		t.Error("expected IsNotFound to be true for an APIError wrapped with errors.Wrap")
+	}
+	if !IsNotFound(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("expected IsNotFound to be true for an APIError wrapped with fmt.Errorf")
+	}
+	if IsNotFound(errors.New(err.Error())) {
+		t.Error("expected IsNotFound to be false for a plain error with the same message")
+	}
+}
+
+func TestListAPIsPagination(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	page := make([]APISearchInfo, DefaultPageLimit)
+	for i := range page {
+		page[i] = APISearchInfo{ID: strconv.Itoa(i), Name: "Other", Version: "v1"}
+	}
+	first, err := httpmock.NewJsonResponder(http.StatusOK, &APISearchResp{
+		Count: DefaultPageLimit,
+		List:  page,
+		Pagination: &Pagination{
+			Offset: 0,
+			Limit:  DefaultPageLimit,
+			Total:  DefaultPageLimit + 1,
+			Next:   "/apis?limit=100&offset=100",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	second, err := httpmock.NewJsonResponder(http.StatusOK, &APISearchResp{
+		Count: 1,
+		List:  []APISearchInfo{{ID: "last", Name: "Test", Version: "v1"}},
+		Pagination: &Pagination{
+			Offset: DefaultPageLimit,
+			Limit:  DefaultPageLimit,
+			Total:  DefaultPageLimit + 1,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?limit=100&offset=0&query=name%3ATest+version%3Av1", first)
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"?limit=100&offset=100&query=name%3ATest+version%3Av1", second)
+
+	apiID, err := testClient.SearchAPIByNameVersion(context.Background(), "Test", "v1")
+	if err != nil {
+		t.Error(err)
+	}
+	if apiID != "last" {
+		t.Errorf(ErrMsgTestIncorrectResult, "last", apiID)
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 2 {
+		t.Errorf(ErrMsgTestIncorrectResult, 2, calls)
+	}
+}
+
+func TestDetectVersionProfile(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusNotFound, ""))
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+"/api/am/publisher/v3/apis", httpmock.NewStringResponder(http.StatusUnauthorized, ""))
+
+	profile, err := DetectVersionProfile(context.Background(), client.Default(), publisherTestEndpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Version != "4.1" {
+		t.Errorf(ErrMsgTestIncorrectResult, "4.1", profile.Version)
+	}
+	// publisher/v4 of 4.2 and 4.3 is probed before publisher/v3.
+	if calls := httpmock.GetTotalCallCount(); calls != 2 {
+		t.Errorf(ErrMsgTestIncorrectResult, 2, calls)
+	}
+
+	httpmock.Reset()
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusNotFound, ""))
+	if _, err = DetectVersionProfile(context.Background(), client.Default(), publisherTestEndpoint); err == nil {
+		t.Error("Expecting an error as no Publisher REST API context exists")
+	}
+
+	// An unexpected response is reported rather than matched as the newest version.
+	httpmock.Reset()
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusBadRequest, ""))
+	if profile, err = DetectVersionProfile(context.Background(), client.Default(), publisherTestEndpoint); err == nil {
+		t.Errorf(ErrMsgTestIncorrectResult, "an error", profile.Version)
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, 1, calls)
+	}
+
+	httpmock.Reset()
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusNotFound, ""))
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+"/api/am/publisher/v4/apis", httpmock.NewStringResponder(http.StatusForbidden, ""))
+	if profile, err = DetectVersionProfile(context.Background(), client.Default(), publisherTestEndpoint); err != nil || profile.Version != "4.2" {
+		t.Errorf(ErrMsgTestIncorrectResult, "4.2", profile.Version)
+	}
+}
+
+func TestVersionProfileConfig(t *testing.T) {
+	if _, err := GetVersionProfile("2.6"); err == nil {
+		t.Error("Expecting an error for an unsupported version")
+	}
+	profile, err := GetVersionProfile("4.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := profile.Config(publisherTestEndpoint, "admin", "admin")
+	if exp := "/api/am/publisher/v4/apis"; conf.PublisherAPIContext != exp {
+		t.Errorf(ErrMsgTestIncorrectResult, exp, conf.PublisherAPIContext)
+	}
+	if exp := "/api/am/publisher/v4/api-products"; conf.PublisherAPIProductContext != exp {
+		t.Errorf(ErrMsgTestIncorrectResult, exp, conf.PublisherAPIProductContext)
+	}
+	if exp := "/api/am/publisher/v4/scopes"; conf.PublisherScopeContext != exp {
+		t.Errorf(ErrMsgTestIncorrectResult, exp, conf.PublisherScopeContext)
+	}
+	if exp := "/api/am/devportal/v3/subscriptions/multiple"; conf.StoreMultipleSubscriptionContext != exp {
+		t.Errorf(ErrMsgTestIncorrectResult, exp, conf.StoreMultipleSubscriptionContext)
+	}
+	c, err := New(&MockTokenManager{}, client.Default(), conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Version() != "4.2" {
+		t.Errorf(ErrMsgTestIncorrectResult, "4.2", c.Version())
+	}
+	if alias, err := GetVersionProfile("4.3"); err != nil || alias.Version != "4.2" {
+		t.Errorf(ErrMsgTestIncorrectResult, "the 4.2 profile", alias.Version)
+	}
+}
+
+func TestSendRetriesUnauthorized(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, StoreTestEndpoint+StoreApplicationContext, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get(client.HeaderAuth) != client.HeaderBear+"refreshed" {
+			return httpmock.NewStringResponse(http.StatusUnauthorized, ""), nil
+		}
+		var body ApplicationCreateReq
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Name != "retried" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusCreated, &ApplicationSearchInfo{ApplicationID: "1"})
+	})
+
+	app, err := testClient.CreateApplication(context.Background(), &ApplicationCreateReq{Name: "retried"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.ApplicationID != "1" {
+		t.Errorf(ErrMsgTestIncorrectResult, "1", app.ApplicationID)
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 2 {
+		t.Errorf(ErrMsgTestIncorrectResult, 2, calls)
+	}
+}
+
+func TestNewWithMalformedHost(t *testing.T) {
+	_, err := New(&MockTokenManager{}, client.Default(), APIM{
+		PublisherEndpoint:   "https://local host:9443%",
+		PublisherAPIContext: PublisherAPIContext,
+	})
+	if err == nil {
+		t.Error("Expecting an error for a malformed host")
+	}
+}
+
+func TestNormalizeOpenAPI(t *testing.T) {
+	userDef := `openapi: 3.0.1
+info:
+  title: Pets
+  version: v1
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: OK
+`
+	serverDef := `{
+  "openapi" : "3.0.1",
+  "info" : { "title" : "Pets", "version" : "v1" },
+  "security" : [ { "default" : [ ] } ],
+  "paths" : {
+    "/pets" : {
+      "get" : {
+        "responses" : { "200" : { "description" : "OK" } },
+        "security" : [ { "default" : [ ] } ],
+        "x-auth-type" : "Application & Application User",
+        "x-throttling-tier" : "Unlimited"
+      }
+    }
+  },
+  "components" : {
+    "securitySchemes" : {
+      "default" : { "type" : "oauth2", "flows" : { "implicit" : { "authorizationUrl" : "https://test.com", "scopes" : { } } } }
+    }
+  },
+  "x-wso2-auth-header" : "Authorization",
+  "x-wso2-basePath" : "/pets/v1"
+}`
+	user, err := NormalizeOpenAPI(userDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NormalizeOpenAPI(serverDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != server {
+		t.Errorf(ErrMsgTestIncorrectResult, user, server)
+	}
+
+	changed, err := NormalizeOpenAPI(strings.Replace(userDef, "/pets:", "/cats:", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == server {
+		t.Error("expected a changed path to be detected")
+	}
+}
+
+func TestImportOpenAPI(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/import-openapi", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			return nil, err
+		}
+		file, header, err := req.FormFile("file")
+		if err != nil {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		defer file.Close()
+		var props APIReqBody
+		if err := json.Unmarshal([]byte(req.FormValue("additionalProperties")), &props); err != nil || props.Name != "Pets" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusCreated, &APICreateResp{ID: "1", Name: props.Name, Description: header.Filename})
+	})
+
+	api, err := testClient.ImportOpenAPI(context.Background(), "openapi: 3.0.1", &APIReqBody{Name: "Pets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.ID != "1" {
+		t.Errorf(ErrMsgTestIncorrectResult, "1", api.ID)
+	}
+	if api.Description != "openapi.yaml" {
+		t.Errorf(ErrMsgTestIncorrectResult, "openapi.yaml", api.Description)
+	}
+}
+
+func TestIsDefinitionURL(t *testing.T) {
+	if !IsDefinitionURL("https://petstore3.swagger.io/api/v3/openapi.json\n") {
+		t.Error("expected a URL to be detected")
+	}
+	if IsDefinitionURL("openapi: 3.0.1\ninfo:\n  title: https://example.com") {
+		t.Error("expected definition content not to be detected as a URL")
+	}
+}
+
+func TestNormalizeGraphQLSchema(t *testing.T) {
+	a := `# Queries
+type Query {
+  hero(episode: Episode): Character
+  droid(id: ID!): Droid
+}`
+	b := "type Query{hero(episode:Episode):Character, droid(id:ID!):Droid}"
+	if NormalizeGraphQLSchema(a) != NormalizeGraphQLSchema(b) {
+		t.Errorf(ErrMsgTestIncorrectResult, NormalizeGraphQLSchema(a), NormalizeGraphQLSchema(b))
+	}
+	if NormalizeGraphQLSchema(a) == NormalizeGraphQLSchema(strings.Replace(b, "Droid}", "Human}", 1)) {
+		t.Error("expected a changed field type to be detected")
+	}
+}
+
+func TestImportGraphQLSchema(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	validation, err := httpmock.NewJsonResponder(http.StatusOK, map[string]interface{}{
+		"isValid": true,
+		"graphQLInfo": map[string]interface{}{
+			"operations": []APIOperation{{Target: "hero", Verb: "QUERY"}},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/validate-graphql-schema", validation)
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/import-graphql-schema", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			return nil, err
+		}
+		var props APIReqBody
+		if err := json.Unmarshal([]byte(req.FormValue("additionalProperties")), &props); err != nil {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusCreated, &APICreateResp{ID: "1", Type: props.Type, Operations: props.Operations})
+	})
+
+	api, err := testClient.ImportGraphQLSchema(context.Background(), "type Query { hero: String }", &APIReqBody{Name: "StarWars"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Type != "GRAPHQL" {
+		t.Errorf(ErrMsgTestIncorrectResult, "GRAPHQL", api.Type)
+	}
+	if len(api.Operations) != 1 || api.Operations[0].Verb != "QUERY" {
+		t.Errorf(ErrMsgTestIncorrectResult, "[hero QUERY]", api.Operations)
+	}
+}
+
+func TestValidateGraphQLSchemaInvalid(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, &GraphQLValidationResp{ErrorMessage: "syntax error"})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/validate-graphql-schema", responder)
+
+	_, err = testClient.ValidateGraphQLSchema(context.Background(), "type Query {")
+	if err == nil || !strings.Contains(err.Error(), "syntax error") {
+		t.Errorf(ErrMsgTestIncorrectResult, "syntax error", err)
+	}
+}
+
+const testWSDL = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Legacy calculator -->
+<wsdl:definitions name="Calculator" targetNamespace="http://example.com/calc" xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/">
+  <wsdl:service name="CalculatorService"/>
+</wsdl:definitions>`
+
+func testWSDLArchive(t *testing.T, wsdl string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("calculator.wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Write([]byte(wsdl)); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNormalizeWSDL(t *testing.T) {
+	a, err := NormalizeWSDL([]byte(testWSDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NormalizeWSDL([]byte(`<wsdl:definitions xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/" targetNamespace="http://example.com/calc" name="Calculator"><wsdl:service name="CalculatorService"></wsdl:service></wsdl:definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf(ErrMsgTestIncorrectResult, a, b)
+	}
+	c, err := NormalizeWSDL([]byte(strings.Replace(testWSDL, "CalculatorService", "CalcService", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == c {
+		t.Error("expected a changed service name to be detected")
+	}
+
+	archive, err := NormalizeWSDL(testWSDLArchive(t, testWSDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive != "calculator.wsdl\n"+a+"\n" {
+		t.Errorf(ErrMsgTestIncorrectResult, "calculator.wsdl\n"+a+"\n", archive)
+	}
+}
+
+func TestDecodeWSDL(t *testing.T) {
+	archive := testWSDLArchive(t, testWSDL)
+	content, err := DecodeWSDL(base64.StdEncoding.EncodeToString(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsWSDLArchive(content) {
+		t.Error("expected a base64 encoded zip archive to be decoded")
+	}
+	if content, err = DecodeWSDL(testWSDL); err != nil || string(content) != testWSDL {
+		t.Errorf(ErrMsgTestIncorrectResult, testWSDL, string(content))
+	}
+	if _, err = DecodeWSDL("not a wsdl"); err == nil || err.Error() != ErrMsgInvalidWSDL {
+		t.Errorf(ErrMsgTestIncorrectResult, ErrMsgInvalidWSDL, err)
+	}
+}
+
+func TestImportWSDL(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/import-wsdl", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			return nil, err
+		}
+		file, header, err := req.FormFile("file")
+		if err != nil || header.Filename != wsdlArchiveFileName {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		defer file.Close()
+		var props APIReqBody
+		if err := json.Unmarshal([]byte(req.FormValue("additionalProperties")), &props); err != nil {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusCreated, &APICreateResp{ID: "1", Type: req.FormValue("implementationType")})
+	})
+
+	definition := base64.StdEncoding.EncodeToString(testWSDLArchive(t, testWSDL))
+	api, err := testClient.ImportWSDL(context.Background(), definition, "SOAPTOREST", &APIReqBody{Name: "Calculator"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Type != "SOAPTOREST" {
+		t.Errorf(ErrMsgTestIncorrectResult, "SOAPTOREST", api.Type)
+	}
+}
+
+func TestGetWSDL(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"/1/wsdl", httpmock.NewStringResponder(http.StatusOK, testWSDL))
+
+	content, err := testClient.GetWSDL(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != testWSDL {
+		t.Errorf(ErrMsgTestIncorrectResult, testWSDL, string(content))
+	}
+}
+
+func TestNormalizeAsyncAPI(t *testing.T) {
+	a := `asyncapi: 2.0.0
+info:
+  title: Notifications
+  version: v1
+channels:
+  /notifications:
+    subscribe: {}
+`
+	b := `{"asyncapi":"2.0.0","info":{"title":"Notifications","version":"v1"},
+"channels":{"/notifications":{"subscribe":{"x-auth-type":"Application & Application User"}}},
+"x-wso2-disable-security":false}`
+	na, err := NormalizeAsyncAPI(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb, err := NormalizeAsyncAPI(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if na != nb {
+		t.Errorf(ErrMsgTestIncorrectResult, na, nb)
+	}
+}
+
+func TestImportAsyncAPI(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/import-asyncapi", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			return nil, err
+		}
+		if req.FormValue("url") != "https://example.com/asyncapi.yaml" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		var props APIReqBody
+		if err := json.Unmarshal([]byte(req.FormValue("additionalProperties")), &props); err != nil {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusCreated, &APICreateResp{
+			ID:         "1",
+			Type:       props.Type,
+			Operations: []APIOperation{{Target: "/notifications", Verb: "SUBSCRIBE"}},
+		})
+	})
+
+	api, err := testClient.ImportAsyncAPI(context.Background(), "https://example.com/asyncapi.yaml", &APIReqBody{
+		Name:       "Notifications",
+		Type:       "WS",
+		Operations: []APIOperation{{Target: "/ignored", Verb: "PUBLISH"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Type != "WS" {
+		t.Errorf(ErrMsgTestIncorrectResult, "WS", api.Type)
+	}
+	if len(api.Operations) != 1 || api.Operations[0].Verb != "SUBSCRIBE" {
+		t.Errorf(ErrMsgTestIncorrectResult, "[/notifications SUBSCRIBE]", api.Operations)
+	}
+}
+
+func TestLifecycleActions(t *testing.T) {
+	tests := []struct {
+		from, to string
+		actions  []string
+	}{
+		{LifecyclePublished, LifecyclePublished, []string{}},
+		{LifecycleCreated, LifecyclePublished, []string{"Publish"}},
+		{LifecycleCreated, LifecycleRetired, []string{"Publish", "Deprecate", "Retire"}},
+		{LifecycleBlocked, LifecycleCreated, []string{"Re-Publish", "Demote to Created"}},
+		{"published", LifecyclePrototyped, []string{"Deploy as a Prototype"}},
+	}
+	for _, test := range tests {
+		transitions, err := LifecycleActions(test.from, test.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actions := []string{}
+		for _, tr := range transitions {
+			actions = append(actions, tr.Action)
+		}
+		if strings.Join(actions, ",") != strings.Join(test.actions, ",") {
+			t.Errorf(ErrMsgTestIncorrectResult, test.actions, actions)
+		}
+	}
+	if _, err := LifecycleActions(LifecycleRetired, LifecyclePublished); err == nil {
+		t.Error("expected no transition out of RETIRED")
+	}
+}
+
+func TestChangeLifecycleState(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var actions []string
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/change-lifecycle", func(req *http.Request) (*http.Response, error) {
+		action := req.URL.Query().Get("action")
+		checklist := req.URL.Query().Get("lifecycleChecklist")
+		if (action == "Publish") != (checklist == ChecklistDeprecateOldVersions+":true") {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		actions = append(actions, action)
+		return httpmock.NewJsonResponse(http.StatusOK, &APIChangeLifeCycleResp{WorkflowStatus: "APPROVED"})
+	})
+
+	lifecycle, err := testClient.ChangeLifecycleState(context.Background(), "1", LifecycleCreated, LifecycleRetired,
+		map[string]bool{ChecklistDeprecateOldVersions: true}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lifecycle.State != LifecycleRetired || lifecycle.Pending {
+		t.Errorf(ErrMsgTestIncorrectResult, LifecycleRetired, lifecycle)
+	}
+	if strings.Join(actions, ",") != "Publish,Deprecate,Retire" {
+		t.Errorf(ErrMsgTestIncorrectResult, "Publish,Deprecate,Retire", actions)
+	}
+}
+
+func TestChangeLifecycleStatePending(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, &APIChangeLifeCycleResp{WorkflowStatus: WorkflowStatusCreated})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/change-lifecycle", responder)
+	apiResponder, err := httpmock.NewJsonResponder(http.StatusOK, &APISearchInfo{ID: "1", LifeCycleStatus: LifecycleCreated, WorkflowStatus: WorkflowStatusCreated})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"/1", apiResponder)
+
+	interval := LifecyclePollInterval
+	LifecyclePollInterval = time.Millisecond
+	defer func() { LifecyclePollInterval = interval }()
+
+	lifecycle, err := testClient.ChangeLifecycleState(context.Background(), "1", LifecycleCreated, LifecycleDeprecated, nil, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lifecycle.State != LifecycleCreated || !lifecycle.Pending {
+		t.Errorf(ErrMsgTestIncorrectResult, "pending CREATED", lifecycle)
+	}
+	if calls := httpmock.GetCallCountInfo()["POST "+publisherTestEndpoint+PublisherAPIContext+"/change-lifecycle"]; calls != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, 1, calls)
+	}
+}
+
+func TestPruneRevisions(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	revisions := &APIRevisionList{Count: 5, List: []APIRevision{
+		{ID: "r1", DeploymentInfo: []APIRevisionDeployment{{Name: "Default"}}},
+		{ID: "r2"},
+		{ID: "r3"},
+		{ID: "r4"},
+		{ID: "r5"},
+	}}
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, revisions)
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"/1/revisions", responder)
+	httpmock.RegisterResponder(http.MethodDelete, publisherTestEndpoint+PublisherAPIContext+"/1/revisions/r2", httpmock.NewStringResponder(http.StatusOK, "{}"))
+
+	deleted, err := testClient.PruneRevisions(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != "r2" {
+		t.Errorf(ErrMsgTestIncorrectResult, "[r2]", deleted)
+	}
+}
+
+func TestDeployRevision(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var deployments []APIRevisionDeployment
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIContext+"/1/deploy-revision", func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&deployments); err != nil {
+			return nil, err
+		}
+		for i := range deployments {
+			deployments[i].RevisionUUID = req.URL.Query().Get("revisionId")
+			deployments[i].Status = "APPROVED"
+		}
+		return httpmock.NewJsonResponse(http.StatusCreated, deployments)
+	})
+	polls := 0
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"/1/deploy-revision", func(req *http.Request) (*http.Response, error) {
+		// The gateways report the deployment on the second poll.
+		polls++
+		deployed := ""
+		if polls > 1 {
+			deployed = "2024-01-01T00:00:00Z"
+		}
+		for i := range deployments {
+			deployments[i].SuccessDeployedTime = &deployed
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, &APIRevisionDeploymentList{Count: len(deployments), List: deployments})
+	})
+
+	interval := DeploymentPollInterval
+	DeploymentPollInterval = time.Millisecond
+	defer func() { DeploymentPollInterval = interval }()
+
+	err := testClient.DeployRevision(context.Background(), "1", "r1", []APIRevisionDeployment{{Name: "Default", Vhost: "localhost", DisplayOnDevportal: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = testClient.WaitForDeployments(context.Background(), "1", "r1", []string{"Default"}, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 2 {
+		t.Errorf(ErrMsgTestIncorrectResult, 2, polls)
+	}
+	err = testClient.WaitForDeployments(context.Background(), "1", "r1", []string{"Default", "Internal"}, 0)
+	if err == nil || !strings.Contains(err.Error(), "Internal") {
+		t.Errorf(ErrMsgTestIncorrectResult, "not deployed to Internal", err)
+	}
+}
+
+func TestPendingDeploymentsRejected(t *testing.T) {
+	_, err := pendingDeployments([]APIRevisionDeployment{{RevisionUUID: "r1", Name: "Default", Status: DeploymentStatusRejected}}, "r1", []string{"Default"})
+	if err == nil {
+		t.Error("expected a rejected deployment to fail")
+	}
+	pending, err := pendingDeployments([]APIRevisionDeployment{{RevisionUUID: "r0", Name: "Default", Status: "APPROVED"}}, "r1", []string{"Default"})
+	if err != nil || len(pending) != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, "[Default]", pending)
+	}
+}
+
+func TestUpdateGatewayEnvironments(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, map[string]interface{}{
+		"id":                  "1",
+		"name":                "PizzaShackAPI",
+		"gatewayEnvironments": []string{"Production and Sandbox"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, publisherTestEndpoint+PublisherAPIContext+"/1", responder)
+	httpmock.RegisterResponder(http.MethodPut, publisherTestEndpoint+PublisherAPIContext+"/1", func(req *http.Request) (*http.Response, error) {
+		var api map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&api); err != nil {
+			return nil, err
+		}
+		envs, _ := api["gatewayEnvironments"].([]interface{})
+		if api["name"] != "PizzaShackAPI" || len(envs) != 1 || envs[0] != "Internal" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, api)
+	})
+
+	if err = testClient.UpdateGatewayEnvironments(context.Background(), "1", []string{"Internal"}); err != nil {
+		t.Fatal(err)
+	}
+	environments, err := testClient.GetGatewayEnvironments(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(environments) != 1 || environments[0] != "Production and Sandbox" {
+		t.Errorf(ErrMsgTestIncorrectResult, "[Production and Sandbox]", environments)
+	}
+}
+
+func TestCreateAPIProduct(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIProductContext, func(req *http.Request) (*http.Response, error) {
+		var body APIProduct
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.APIs) != 1 || len(body.APIs[0].Operations) != 1 {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		body.ID = "product"
+		body.State = "published"
+		return httpmock.NewJsonResponse(http.StatusCreated, &body)
+	})
+
+	product, err := testClient.CreateAPIProduct(context.Background(), &APIProduct{
+		Name:    "partner",
+		Context: "/partner",
+		APIs:    []APIProductAPI{{APIID: "abc", Operations: []APIOperation{{Target: "/items", Verb: "GET"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.ID != "product" {
+		t.Errorf(ErrMsgTestIncorrectResult, "product", product.ID)
+	}
+	if product.LifecycleState() != LifecyclePublished {
+		t.Errorf(ErrMsgTestIncorrectResult, LifecyclePublished, product.LifecycleState())
+	}
+}
+
+func TestForAPIProducts(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIProductContext+"/change-lifecycle", func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("apiProductId") != "product" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, &APIChangeLifeCycleResp{WorkflowStatus: "APPROVED"})
+	})
+	responder, err := httpmock.NewJsonResponder(http.StatusCreated, &APIRevision{ID: "revision"})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherAPIProductContext+"/product/revisions", responder)
+
+	products := testClient.ForAPIProducts()
+	if _, err := products.ChangeLifeCycleStatus(context.Background(), "product", "Publish", nil); err != nil {
+		t.Error(err)
+	}
+	revision, err := products.CreateRevision(context.Background(), "product", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.ID != "revision" {
+		t.Errorf(ErrMsgTestIncorrectResult, "revision", revision.ID)
+	}
+	// The client the copy is made from still applies to APIs.
+	if testClient.lifecycleIDParam != "apiId" {
+		t.Errorf(ErrMsgTestIncorrectResult, "apiId", testClient.lifecycleIDParam)
+	}
+}
+
+func TestCreateScope(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, publisherTestEndpoint+PublisherScopeContext, func(req *http.Request) (*http.Response, error) {
+		var body Scope
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Name != "read" || len(body.Bindings) != 1 {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		body.ID = "scope"
+		return httpmock.NewJsonResponse(http.StatusCreated, &body)
+	})
+
+	scope, err := testClient.CreateScope(context.Background(), &Scope{Name: "read", DisplayName: "read", Bindings: []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.ID != "scope" {
+		t.Errorf(ErrMsgTestIncorrectResult, "scope", scope.ID)
+	}
+}
+
+func TestAPIScopesRequest(t *testing.T) {
+	body, err := json.Marshal(&APIReqBody{
+		Name:       "Test",
+		Scopes:     []APIScope{{Scope: Scope{Name: "read", Bindings: []string{}}}},
+		Operations: []APIOperation{{Target: "/items", Verb: "GET", Scopes: []string{"read"}}, {Target: "/items", Verb: "POST"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Scopes     []map[string]any `json:"scopes"`
+		Operations []map[string]any `json:"operations"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if len(raw.Scopes) != 1 || raw.Scopes[0]["shared"] != false {
+		t.Errorf(ErrMsgTestIncorrectResult, "one local scope", raw.Scopes)
+	}
+	if _, ok := raw.Operations[1]["scopes"]; ok {
+		t.Errorf(ErrMsgTestIncorrectResult, "operation without scopes", raw.Operations[1])
+	}
+}
+
+func TestDecodeAPIOperation(t *testing.T) {
+	var operation APIOperation
+	err := json.Unmarshal([]byte(`{
+		"target": "/items", "verb": "POST", "authType": "None", "throttlingPolicy": "10KPerMin",
+		"operationPolicies": {
+			"request": [{"policyName": "addHeader", "policyVersion": "v1", "parameters": {"headerName": "X-Source"}}],
+			"response": [], "fault": []
+		}
+	}`), &operation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operation.AuthType != "None" || operation.ThrottlingPolicy != "10KPerMin" {
+		t.Errorf(ErrMsgTestIncorrectResult, "None 10KPerMin", operation.AuthType+" "+operation.ThrottlingPolicy)
+	}
+	if policies := operation.OperationPolicies; policies == nil || len(policies.Request) != 1 || policies.Request[0].Parameters["headerName"] != "X-Source" {
+		t.Errorf(ErrMsgTestIncorrectResult, "addHeader request policy", operation.OperationPolicies)
+	}
+}
+
+func TestAPIAccessRequest(t *testing.T) {
+	body, err := json.Marshal(&APIReqBody{Name: "Test", Visibility: "PUBLIC", VisibleRoles: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		t.Fatal(err)
+	}
+	// The roles are sent even when empty, so that restricting an api can be reverted.
+	if roles, ok := raw["visibleRoles"].([]any); !ok || len(roles) != 0 {
+		t.Errorf(ErrMsgTestIncorrectResult, "[]", raw["visibleRoles"])
+	}
+	if _, ok := raw["accessControl"]; ok {
+		t.Errorf(ErrMsgTestIncorrectResult, "no access control", raw["accessControl"])
+	}
+}
+
+func TestAPICorsConfigurationRequest(t *testing.T) {
+	body, err := json.Marshal(&APICorsConfiguration{AccessControlAllowOrigins: []string{"*"}, AccessControlAllowHeaders: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A disabled configuration and empty lists are sent, so that the CORS configuration of an api can be reverted.
+	exp := `{"corsConfigurationEnabled":false,"accessControlAllowOrigins":["*"],"accessControlAllowCredentials":false,"accessControlAllowHeaders":[],"accessControlAllowMethods":null}`
+	if string(body) != exp {
+		t.Errorf(ErrMsgTestIncorrectResult, exp, string(body))
+	}
+}
+
+func TestDecodeEndpointSecurity(t *testing.T) {
+	for _, customParameters := range []string{`{"scope": "read"}`, `"{\"scope\": \"read\"}"`} {
+		var config APIEndpointConfig
+		err := json.Unmarshal([]byte(`{
+			"endpoint_type": "http",
+			"production_endpoints": {"url": "https://backend.example.com"},
+			"endpoint_security": {
+				"production": {"enabled": true, "type": "OAUTH", "grantType": "CLIENT_CREDENTIALS", "clientId": "client", "clientSecret": "", "customParameters": `+customParameters+`}
+			}
+		}`), &config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		security := config.EndpointSecurity
+		if security == nil || security.Production == nil || security.Production.ClientID != "client" || security.Sandbox != nil {
+			t.Errorf(ErrMsgTestIncorrectResult, "production OAUTH endpoint security", security)
+		}
+	}
+}
+
+func TestEncodeLoadBalanceEndpointConfig(t *testing.T) {
+	data, err := json.Marshal(APIEndpointConfig{
+		EndpointType:  EndpointTypeLoadBalance,
+		AlgoClassName: "org.apache.synapse.endpoints.algorithms.RoundRobin",
+		ProductionLoadBalanceEndpoints: []APIEndpointAdvancedConfig{
+			{URL: "https://backend-1.example.com"},
+			{URL: "https://backend-2.example.com", Config: &EndpointSettings{ActionDuration: "30000", RetryErrorCodes: []string{"101504"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	endpoints, ok := config["production_endpoints"].([]any)
+	if !ok || len(endpoints) != 2 || config["sandbox_endpoints"] != nil {
+		t.Errorf(ErrMsgTestIncorrectResult, "a list of production endpoints", string(data))
+	}
+
+	var decoded APIEndpointConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ProductionEndpoints != nil || len(decoded.ProductionLoadBalanceEndpoints) != 2 ||
+		decoded.ProductionLoadBalanceEndpoints[1].Config.RetryErrorCodes[0] != "101504" {
+		t.Errorf(ErrMsgTestIncorrectResult, "the load balanced endpoints", decoded)
+	}
+}
+
+func TestDecodeFailoverEndpointConfig(t *testing.T) {
+	var config APIEndpointConfig
+	err := json.Unmarshal([]byte(`{
+		"endpoint_type": "failover",
+		"production_endpoints": {"url": "https://active.example.com", "config": {"actionDuration": 30000, "actionSelect": "fault", "factor": 1.5, "suspendErrorCode": "101505"}},
+		"production_failovers": [{"url": "https://standby.example.com", "config": null}]
+	}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := config.ProductionEndpoints.Config
+	if settings == nil || settings.ActionDuration != "30000" || settings.Factor != "1.5" || len(settings.SuspendErrorCodes) != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, "the settings of the production endpoint", settings)
+	}
+	if len(config.ProductionFailovers) != 1 || config.ProductionFailovers[0].URL != "https://standby.example.com" {
+		t.Errorf(ErrMsgTestIncorrectResult, "the production failover endpoint", config.ProductionFailovers)
+	}
+}
+
+func TestDecodeAdditionalProperties(t *testing.T) {
+	for _, properties := range []string{`[{"name": "owner", "value": "payments", "display": true}]`, `{"owner": "payments"}`} {
+		var api APISearchInfo
+		if err := json.Unmarshal([]byte(`{"name": "Test", "additionalProperties": `+properties+`}`), &api); err != nil {
+			t.Fatal(err)
+		}
+		if len(api.AdditionalProperties) != 1 || api.AdditionalProperties[0].Name != "owner" || api.AdditionalProperties[0].Value != "payments" {
+			t.Errorf(ErrMsgTestIncorrectResult, "the owner property", api.AdditionalProperties)
+		}
+	}
+}
+
+func TestAPISpecAdditionalProperties(t *testing.T) {
+	reqBody := &APIReqBody{Name: "Test", AdditionalProperties: APIAdditionalProperties{{Name: "owner", Value: "payments", Display: true}}}
+	for version, exp := range map[string]string{
+		"4.2": `[{"name":"owner","value":"payments","display":true}]`,
+		"3.2": `{"owner":"payments"}`,
+	} {
+		profile, err := GetVersionProfile(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := New(&MockTokenManager{}, client.Default(), profile.Config(publisherTestEndpoint, "admin", "admin"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := json.Marshal(c.apiSpec(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(body, &raw); err != nil {
+			t.Fatal(err)
+		}
+		if string(raw["additionalProperties"]) != exp || string(raw["name"]) != `"Test"` {
+			t.Errorf(ErrMsgTestIncorrectResult, exp, string(body))
+		}
+	}
+}
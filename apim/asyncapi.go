@@ -0,0 +1,96 @@
+package apim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ImportAsyncAPI creates a WS, WEBSUB, SSE or ASYNC API from the given AsyncAPI definition, either its content or a URL.
+// The given API spec is sent as the additional properties of the API, operations are derived from the channels of the definition.
+// Returns the created API and any error encountered.
+func (c *Client) ImportAsyncAPI(ctx context.Context, definition string, reqBody *APIReqBody) (*APICreateResp, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, "import-asyncapi")
+	if err != nil {
+		return nil, err
+	}
+	props := *reqBody
+	props.Operations = nil
+	b, err := json.Marshal(c.apiSpec(&props))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse additional properties")
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPost, endpoint,
+		definitionField("url", "file", definitionFileName("asyncapi", definition), definition),
+		formField{name: "additionalProperties", value: string(b)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APICreateResp
+	err = c.send(ctx, ImportAsyncAPIContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetAsyncAPI returns the AsyncAPI definition of the given API as stored by API-M and any error encountered.
+func (c *Client) GetAsyncAPI(ctx context.Context, apiID string) (string, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "asyncapi")
+	if err != nil {
+		return "", err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	var resBody []byte
+	err = c.send(ctx, GetAsyncAPIContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(resBody), nil
+}
+
+// UpdateAsyncAPI replaces the AsyncAPI definition of the given API with the given definition, either its content or a URL.
+// API-M regenerates the operations of the API from the channels of the definition.
+// Returns any error encountered.
+func (c *Client) UpdateAsyncAPI(ctx context.Context, apiID, definition string) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "asyncapi")
+	if err != nil {
+		return err
+	}
+	field := formField{name: "apiDefinition", value: definition}
+	if IsDefinitionURL(definition) {
+		field = formField{name: "url", value: strings.TrimSpace(definition)}
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPut, endpoint, field)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, UpdateAsyncAPIContext, req, nil, http.StatusOK)
+}
+
+// NormalizeAsyncAPI returns the canonical JSON form of the given AsyncAPI definition, in JSON or YAML,
+// so that definitions differing only in formatting compare equal.
+// The vendor extensions added by API-M on import are removed, as well as empty objects and arrays.
+func NormalizeAsyncAPI(definition string) (string, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(definition), &doc); err != nil {
+		return "", errors.Wrap(err, "unable to parse the AsyncAPI definition")
+	}
+	doc = normalizeDefinitionNode(doc, func(k string, _ interface{}) bool {
+		return strings.HasPrefix(k, "x-wso2-") || k == "x-auth-type" || k == "x-throttling-tier" || k == "x-scopes"
+	})
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to serialize the AsyncAPI definition")
+	}
+	return string(b), nil
+}
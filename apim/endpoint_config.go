@@ -0,0 +1,108 @@
+package apim
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+const (
+	// EndpointTypeLoadBalance is the endpoint type balancing the requests between several endpoints per environment.
+	EndpointTypeLoadBalance = "load_balance"
+	// EndpointTypeFailover is the endpoint type falling back to other endpoints when the endpoint of an environment fails.
+	EndpointTypeFailover = "failover"
+	// EndpointTypeAWSLambda is the endpoint type invoking AWS Lambda functions.
+	EndpointTypeAWSLambda = "awslambda"
+	// EndpointTypeInline is the endpoint type of prototyped APIs, mocking the responses.
+	EndpointTypeInline = "INLINE"
+)
+
+// MarshalJSON encodes the endpoints of load_balance endpoints as the list of endpoints of each environment.
+func (c APIEndpointConfig) MarshalJSON() ([]byte, error) {
+	type config APIEndpointConfig
+	if c.EndpointType != EndpointTypeLoadBalance {
+		return json.Marshal(config(c))
+	}
+	return json.Marshal(struct {
+		config
+		SandboxEndpoints    []APIEndpointAdvancedConfig `json:"sandbox_endpoints,omitempty"`
+		ProductionEndpoints []APIEndpointAdvancedConfig `json:"production_endpoints,omitempty"`
+	}{config(c), c.SandboxLoadBalanceEndpoints, c.ProductionLoadBalanceEndpoints})
+}
+
+// UnmarshalJSON decodes the endpoints of an environment, a list of endpoints for load_balance endpoints.
+func (c *APIEndpointConfig) UnmarshalJSON(data []byte) error {
+	type config APIEndpointConfig
+	var raw struct {
+		config
+		SandboxEndpoints    json.RawMessage `json:"sandbox_endpoints"`
+		ProductionEndpoints json.RawMessage `json:"production_endpoints"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = APIEndpointConfig(raw.config)
+	if err := decodeEndpoints(raw.SandboxEndpoints, &c.SandboxEndpoints, &c.SandboxLoadBalanceEndpoints); err != nil {
+		return err
+	}
+	return decodeEndpoints(raw.ProductionEndpoints, &c.ProductionEndpoints, &c.ProductionLoadBalanceEndpoints)
+}
+
+// decodeEndpoints decodes the given endpoints of an environment, either an endpoint or a list of endpoints.
+func decodeEndpoints(data json.RawMessage, endpoint **APIEndpointAdvancedConfig, endpoints *[]APIEndpointAdvancedConfig) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	if data[0] == '[' {
+		return json.Unmarshal(data, endpoints)
+	}
+	return json.Unmarshal(data, endpoint)
+}
+
+// UnmarshalJSON decodes the settings of an endpoint, their values being either strings or numbers.
+func (s *EndpointSettings) UnmarshalJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	str := func(key string) string {
+		switch v := raw[key].(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return ""
+		}
+	}
+	list := func(key string) []string {
+		var values []string
+		switch v := raw[key].(type) {
+		case []any:
+			for _, value := range v {
+				if s, ok := value.(string); ok {
+					values = append(values, s)
+				}
+			}
+		case string:
+			// A single error code, or none
+			if v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	*s = EndpointSettings{
+		ActionDuration:     str("actionDuration"),
+		ActionSelect:       str("actionSelect"),
+		RetryTimeOut:       str("retryTimeOut"),
+		RetryDelay:         str("retryDelay"),
+		RetryErrorCodes:    list("retryErroCode"),
+		Factor:             str("factor"),
+		SuspendDuration:    str("suspendDuration"),
+		SuspendMaxDuration: str("suspendMaxDuration"),
+		SuspendErrorCodes:  list("suspendErrorCode"),
+	}
+	return nil
+}
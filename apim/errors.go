@@ -0,0 +1,102 @@
+package apim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/floydspace/terraform-provider-wso2apim/client"
+	"github.com/pkg/errors"
+)
+
+// APIErrorListItem represents a field level error of the API-M error payload.
+type APIErrorListItem struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+}
+
+// APIError represents the standard API-M error payload along with the HTTP status code of the response.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// Context describes the operation which failed.
+	Context     string             `json:"-"`
+	Code        int64              `json:"code"`
+	Message     string             `json:"message"`
+	Description string             `json:"description,omitempty"`
+	MoreInfo    string             `json:"moreInfo,omitempty"`
+	Errors      []APIErrorListItem `json:"error,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "unsuccessful API call: %s response Code: %d", e.Context, e.StatusCode)
+	if e.Message != "" {
+		sb.WriteString(", " + e.Message)
+	}
+	if e.Description != "" && e.Description != e.Message {
+		sb.WriteString(": " + e.Description)
+	}
+	for _, item := range e.Errors {
+		sb.WriteString("\n  - " + item.Message)
+		if item.Description != "" && item.Description != item.Message {
+			sb.WriteString(": " + item.Description)
+		}
+	}
+	return sb.String()
+}
+
+// newAPIError returns an APIError for the given unsuccessful API call.
+// The response body is parsed as the API-M error payload; when it is not, the HTTP status text is used as the message.
+func newAPIError(reqContext string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{}
+	if len(body) == 0 || json.Unmarshal(body, apiErr) != nil {
+		apiErr = &APIError{Message: http.StatusText(statusCode)}
+	}
+	apiErr.StatusCode = statusCode
+	apiErr.Context = reqContext
+	return apiErr
+}
+
+// wrapInvokeError converts the client.InvokeError of an unsuccessful API call into an APIError.
+// Any other error is returned as it is.
+func wrapInvokeError(reqContext string, err error) error {
+	var invokeErr *client.InvokeError
+	if errors.As(err, &invokeErr) && invokeErr.StatusCode >= http.StatusBadRequest {
+		return newAPIError(reqContext, invokeErr.StatusCode, invokeErr.Body)
+	}
+	return err
+}
+
+// hasStatus returns true if the given error is an APIError with the given HTTP status code.
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound returns true if the given error is an API-M "404 Not Found" error.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if the given error is an API-M "409 Conflict" error.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsBadRequest returns true if the given error is an API-M "400 Bad Request" validation error.
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// IsUnauthorized returns true if the given error is an API-M "401 Unauthorized" error.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden returns true if the given error is an API-M "403 Forbidden" error.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
@@ -0,0 +1,163 @@
+package apim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+)
+
+const (
+	graphQLSchemaFileName = "schema.graphql"
+	ErrMsgInvalidGraphQL  = "invalid GraphQL schema: %s"
+)
+
+// ValidateGraphQLSchema validates the given GraphQL SDL schema.
+// Returns the operations derived from the schema and any error encountered.
+func (c *Client) ValidateGraphQLSchema(ctx context.Context, schema string) ([]APIOperation, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, "validate-graphql-schema")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPost, endpoint,
+		formField{name: "file", fileName: graphQLSchemaFileName, value: schema},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var resBody GraphQLValidationResp
+	err = c.send(ctx, ValidateGraphQLContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	if !resBody.IsValid {
+		return nil, errors.Errorf(ErrMsgInvalidGraphQL, resBody.ErrorMessage)
+	}
+	if resBody.GraphQLInfo == nil {
+		return nil, nil
+	}
+	return resBody.GraphQLInfo.Operations, nil
+}
+
+// ImportGraphQLSchema creates a GraphQL API from the given SDL schema with the given API spec.
+// The operations of the API are derived from the schema.
+// Returns the created API and any error encountered.
+func (c *Client) ImportGraphQLSchema(ctx context.Context, schema string, reqBody *APIReqBody) (*APICreateResp, error) {
+	operations, err := c.ValidateGraphQLSchema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, "import-graphql-schema")
+	if err != nil {
+		return nil, err
+	}
+	props := *reqBody
+	props.Type = "GRAPHQL"
+	props.Operations = operations
+	b, err := json.Marshal(c.apiSpec(&props))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse additional properties")
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPost, endpoint,
+		formField{name: "type", value: "GraphQL"},
+		formField{name: "file", fileName: graphQLSchemaFileName, value: schema},
+		formField{name: "additionalProperties", value: string(b)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APICreateResp
+	err = c.send(ctx, ImportGraphQLContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetGraphQLSchema returns the SDL schema of the given GraphQL API and any error encountered.
+func (c *Client) GetGraphQLSchema(ctx context.Context, apiID string) (string, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "graphql-schema")
+	if err != nil {
+		return "", err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	var resBody GraphQLSchema
+	err = c.send(ctx, GetGraphQLContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return resBody.SchemaDefinition, nil
+}
+
+// UpdateGraphQLSchema replaces the SDL schema of the given GraphQL API.
+// Returns any error encountered.
+func (c *Client) UpdateGraphQLSchema(ctx context.Context, apiID, schema string) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "graphql-schema")
+	if err != nil {
+		return err
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPut, endpoint,
+		formField{name: "schemaDefinition", value: schema},
+	)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, UpdateGraphQLContext, req, nil, http.StatusOK)
+}
+
+// GetGraphQLComplexity returns the query complexity values of the given GraphQL API and any error encountered.
+func (c *Client) GetGraphQLComplexity(ctx context.Context, apiID string) ([]GraphQLComplexity, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "graphql-policies", "complexity")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody GraphQLComplexityList
+	err = c.send(ctx, GetGraphQLComplexityContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resBody.List, nil
+}
+
+// UpdateGraphQLComplexity replaces the query complexity values of the given GraphQL API.
+// Returns any error encountered.
+func (c *Client) UpdateGraphQLComplexity(ctx context.Context, apiID string, complexity []GraphQLComplexity) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "graphql-policies", "complexity")
+	if err != nil {
+		return err
+	}
+	if complexity == nil {
+		complexity = []GraphQLComplexity{}
+	}
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, &GraphQLComplexityList{List: complexity})
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, UpdateGraphQLComplexityContext, req, nil, http.StatusOK)
+}
+
+var (
+	graphQLComment     = regexp.MustCompile(`#[^\n]*`)
+	graphQLWhitespace  = regexp.MustCompile(`[\s,]+`)
+	graphQLPunctuation = regexp.MustCompile(` ?([{}()\[\]:=!|@]) ?`)
+)
+
+// NormalizeGraphQLSchema returns the given SDL schema without comments and insignificant whitespace and commas,
+// so that schemas differing only in formatting compare equal.
+func NormalizeGraphQLSchema(schema string) string {
+	s := graphQLComment.ReplaceAllString(schema, "")
+	s = graphQLWhitespace.ReplaceAllString(s, " ")
+	s = graphQLPunctuation.ReplaceAllString(s, "$1")
+	return strings.TrimSpace(s)
+}
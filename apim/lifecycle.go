@@ -0,0 +1,167 @@
+package apim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Lifecycle states of an API in the default API-M lifecycle.
+const (
+	LifecycleCreated    = "CREATED"
+	LifecyclePrototyped = "PROTOTYPED"
+	LifecyclePublished  = "PUBLISHED"
+	LifecycleBlocked    = "BLOCKED"
+	LifecycleDeprecated = "DEPRECATED"
+	LifecycleRetired    = "RETIRED"
+)
+
+// Lifecycle checklist items of the Publish action in the default API-M lifecycle.
+const (
+	ChecklistDeprecateOldVersions   = "Deprecate old versions after publishing the API"
+	ChecklistRequiresResubscription = "Requires re-subscription when publishing the API"
+)
+
+const (
+	// WorkflowStatusCreated is the workflow status of a lifecycle change pending approval.
+	WorkflowStatusCreated = "CREATED"
+	// WorkflowStatusRejected is the workflow status of a rejected lifecycle change.
+	WorkflowStatusRejected = "REJECTED"
+)
+
+// LifecyclePollInterval is the interval the API is polled at while a lifecycle change is pending approval.
+var LifecyclePollInterval = 5 * time.Second
+
+// LifecycleTransition represents an action of the API-M lifecycle and the state it leads to.
+type LifecycleTransition struct {
+	From   string
+	Action string
+	To     string
+}
+
+// lifecycleTransitions lists the transitions of the default API-M lifecycle, preferred first.
+var lifecycleTransitions = []LifecycleTransition{
+	{From: LifecycleCreated, Action: "Publish", To: LifecyclePublished},
+	{From: LifecycleCreated, Action: "Deploy as a Prototype", To: LifecyclePrototyped},
+	{From: LifecyclePrototyped, Action: "Publish", To: LifecyclePublished},
+	{From: LifecyclePrototyped, Action: "Demote to Created", To: LifecycleCreated},
+	{From: LifecyclePublished, Action: "Deprecate", To: LifecycleDeprecated},
+	{From: LifecyclePublished, Action: "Block", To: LifecycleBlocked},
+	{From: LifecyclePublished, Action: "Deploy as a Prototype", To: LifecyclePrototyped},
+	{From: LifecyclePublished, Action: "Demote to Created", To: LifecycleCreated},
+	{From: LifecycleBlocked, Action: "Re-Publish", To: LifecyclePublished},
+	{From: LifecycleBlocked, Action: "Deprecate", To: LifecycleDeprecated},
+	{From: LifecycleDeprecated, Action: "Retire", To: LifecycleRetired},
+}
+
+// LifecycleStates returns the states of the default API-M lifecycle.
+func LifecycleStates() []string {
+	return []string{LifecycleCreated, LifecyclePrototyped, LifecyclePublished, LifecycleBlocked, LifecycleDeprecated, LifecycleRetired}
+}
+
+// LifecycleActions returns the shortest sequence of transitions leading from the given lifecycle state to the target one
+// and any error encountered. An empty sequence is returned if the states are equal.
+func LifecycleActions(from, to string) ([]LifecycleTransition, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	paths := map[string][]LifecycleTransition{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if state == to {
+			return paths[state], nil
+		}
+		for _, t := range lifecycleTransitions {
+			if _, seen := paths[t.To]; t.From != state || seen {
+				continue
+			}
+			paths[t.To] = append(append([]LifecycleTransition{}, paths[state]...), t)
+			queue = append(queue, t.To)
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("no lifecycle transition from %s to %s", from, to))
+}
+
+// LifecycleChange represents the result of a lifecycle state change.
+type LifecycleChange struct {
+	// State is the lifecycle state the API is in.
+	State string
+	// Pending is true if a lifecycle change is waiting for approval.
+	Pending bool
+}
+
+// ChangeLifecycleState moves the given API from its current lifecycle state to the target one
+// by applying the transitions returned by LifecycleActions.
+// The checklist items are sent with the Publish action.
+// A change creating an approval workflow is waited for up to approvalTimeout, the remaining transitions are not applied
+// if it is still pending.
+// Returns the resulting lifecycle state and any error encountered.
+func (c *Client) ChangeLifecycleState(ctx context.Context, apiID, current, target string, checklist map[string]bool, approvalTimeout time.Duration) (*LifecycleChange, error) {
+	transitions, err := LifecycleActions(current, target)
+	if err != nil {
+		return &LifecycleChange{State: current}, err
+	}
+	state := strings.ToUpper(current)
+	for _, t := range transitions {
+		var items map[string]bool
+		if t.Action == "Publish" {
+			items = checklist
+		}
+		res, err := c.ChangeLifeCycleStatus(ctx, apiID, t.Action, items)
+		if err != nil {
+			return &LifecycleChange{State: state}, errors.Wrapf(err, "unable to apply the %s lifecycle action in %s state", t.Action, state)
+		}
+		if res.WorkflowStatus == WorkflowStatusCreated {
+			if state, err = c.waitForLifecycleApproval(ctx, apiID, state, approvalTimeout); err != nil {
+				return &LifecycleChange{State: state}, err
+			}
+			if state != t.To {
+				return &LifecycleChange{State: state, Pending: true}, nil
+			}
+			continue
+		}
+		state = t.To
+		if res.LifecycleState.State != "" {
+			state = strings.ToUpper(res.LifecycleState.State)
+		}
+	}
+	return &LifecycleChange{State: state}, nil
+}
+
+// waitForLifecycleApproval polls the given API until it leaves the given lifecycle state or the timeout elapses.
+// Returns the lifecycle state of the API and any error encountered.
+func (c *Client) waitForLifecycleApproval(ctx context.Context, apiID, state string, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case <-time.After(LifecyclePollInterval):
+		}
+		api, err := c.GetAPI(ctx, apiID)
+		if err != nil {
+			return state, err
+		}
+		if api.WorkflowStatus == WorkflowStatusRejected {
+			return state, errors.New(fmt.Sprintf("lifecycle change of the API in %s state was rejected", state))
+		}
+		if s := strings.ToUpper(api.LifeCycleStatus); s != state {
+			return s, nil
+		}
+	}
+	return state, nil
+}
+
+// lifecycleChecklist returns the given checklist items in the format of the lifecycleChecklist query parameter.
+func lifecycleChecklist(items map[string]bool) string {
+	checklist := make([]string, 0, len(items))
+	for item, checked := range items {
+		checklist = append(checklist, fmt.Sprintf("%s:%t", item, checked))
+	}
+	sort.Strings(checklist)
+	return strings.Join(checklist, ",")
+}
@@ -0,0 +1,193 @@
+package apim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// IsDefinitionURL returns true if the given API definition is a URL rather than the definition content.
+func IsDefinitionURL(definition string) bool {
+	d := strings.TrimSpace(definition)
+	return !strings.ContainsAny(d, "\n ") && (strings.HasPrefix(d, "http://") || strings.HasPrefix(d, "https://"))
+}
+
+// definitionFileName returns the file name the given definition content is uploaded with,
+// API-M determines the format of the content from the extension.
+func definitionFileName(name, definition string) string {
+	if strings.HasPrefix(strings.TrimSpace(definition), "{") {
+		return name + ".json"
+	}
+	return name + ".yaml"
+}
+
+// definitionField returns the form field carrying the given definition,
+// the urlField if the definition is a URL or the contentField otherwise.
+func definitionField(urlField, contentField, fileName, definition string) formField {
+	if IsDefinitionURL(definition) {
+		return formField{name: urlField, value: strings.TrimSpace(definition)}
+	}
+	return formField{name: contentField, fileName: fileName, value: definition}
+}
+
+// ImportOpenAPI creates an API from the given OpenAPI definition, either its content or a URL.
+// The given API spec is sent as the additional properties of the API, operations are derived from the definition.
+// Returns the created API and any error encountered.
+func (c *Client) ImportOpenAPI(ctx context.Context, definition string, reqBody *APIReqBody) (*APICreateResp, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, "import-openapi")
+	if err != nil {
+		return nil, err
+	}
+	props, err := json.Marshal(c.apiSpec(reqBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse additional properties")
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPost, endpoint,
+		definitionField("url", "file", definitionFileName("openapi", definition), definition),
+		formField{name: "additionalProperties", value: string(props)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APICreateResp
+	err = c.send(ctx, ImportOpenAPIContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetOpenAPI returns the OpenAPI definition of the given API as stored by API-M and any error encountered.
+func (c *Client) GetOpenAPI(ctx context.Context, apiID string) (string, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "swagger")
+	if err != nil {
+		return "", err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	var resBody json.RawMessage
+	err = c.send(ctx, GetOpenAPIContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(resBody), nil
+}
+
+// UpdateOpenAPI replaces the OpenAPI definition of the given API with the given definition, either its content or a URL.
+// API-M regenerates the operations of the API from the definition.
+// Returns any error encountered.
+func (c *Client) UpdateOpenAPI(ctx context.Context, apiID, definition string) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "swagger")
+	if err != nil {
+		return err
+	}
+	field := formField{name: "apiDefinition", value: definition}
+	if IsDefinitionURL(definition) {
+		field = formField{name: "url", value: strings.TrimSpace(definition)}
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPut, endpoint, field)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, UpdateOpenAPIContext, req, nil, http.StatusOK)
+}
+
+// NormalizeOpenAPI returns the canonical JSON form of the given OpenAPI definition, in JSON or YAML,
+// so that definitions differing only in formatting compare equal.
+// The vendor extensions and the default security scheme added by API-M on import are removed,
+// as well as empty objects and arrays.
+func NormalizeOpenAPI(definition string) (string, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(definition), &doc); err != nil {
+		return "", errors.Wrap(err, "unable to parse the OpenAPI definition")
+	}
+	doc = normalizeDefinitionNode(doc, func(k string, v interface{}) bool {
+		return strings.HasPrefix(k, "x-wso2-") || k == "x-throttling-tier" || k == "x-auth-type" ||
+			(k == "security" && isDefaultSecurity(v)) || (k == "default" && isDefaultSecurityScheme(v))
+	})
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to serialize the OpenAPI definition")
+	}
+	return string(b), nil
+}
+
+// normalizeDefinitionNode returns the given YAML/JSON node with the keys matched by drop and the empty values removed.
+// Map keys are converted to strings so that the node can be serialized as JSON.
+func normalizeDefinitionNode(node interface{}, drop func(k string, v interface{}) bool) interface{} {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		// YAML mappings with non-string keys, such as response codes.
+		m := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			m[fmt.Sprint(k)] = v
+		}
+		return normalizeDefinitionNode(m, drop)
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for k, v := range n {
+			if drop(k, v) {
+				continue
+			}
+			if v = normalizeDefinitionNode(v, drop); !isEmptyNode(v) {
+				out[k] = v
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(n))
+		for _, v := range n {
+			if v = normalizeDefinitionNode(v, drop); !isEmptyNode(v) {
+				out = append(out, v)
+			}
+		}
+		return out
+	default:
+		return n
+	}
+}
+
+// isEmptyNode returns true if the given node is null, an empty object or an empty array.
+func isEmptyNode(node interface{}) bool {
+	switch n := node.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(n) == 0
+	case []interface{}:
+		return len(n) == 0
+	}
+	return false
+}
+
+// isDefaultSecurity returns true if the given security requirement refers only to the API-M default security scheme.
+func isDefaultSecurity(v interface{}) bool {
+	reqs, ok := v.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, r := range reqs {
+		m, ok := r.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return false
+		}
+		if _, found := m["default"]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isDefaultSecurityScheme returns true if the given node is the OAuth2 security scheme API-M adds as default.
+func isDefaultSecurityScheme(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	return ok && m["type"] == "oauth2"
+}
@@ -0,0 +1,170 @@
+package apim
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageLimit is the number of items requested per page by the list iterators.
+const DefaultPageLimit = 100
+
+// Pagination represents the pagination information of an API-M list response.
+type Pagination struct {
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Total    int    `json:"total"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+}
+
+// listResp represents a single page of an API-M list or search response.
+type listResp[T any] struct {
+	Count      int         `json:"count"`
+	List       []T         `json:"list"`
+	Next       string      `json:"next"`
+	Previous   string      `json:"previous"`
+	Pagination *Pagination `json:"pagination"`
+}
+
+// hasMore returns true if there are more pages after the given page fetched at the given offset.
+func (r *listResp[T]) hasMore(offset int) bool {
+	if len(r.List) == 0 {
+		return false
+	}
+	if p := r.Pagination; p != nil {
+		return p.Next != "" || (p.Total > 0 && offset+len(r.List) < p.Total)
+	}
+	return r.Next != ""
+}
+
+// PageIterator iterates over all the items of a paginated API-M list or search call.
+// Pages are fetched lazily using offset and limit until the server reports there are no more pages.
+//
+//	it := c.ListAPIs("name:foo")
+//	for it.Next(ctx) {
+//		api := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type PageIterator[T any] struct {
+	c          *Client
+	reqContext string
+	endpoint   string
+	query      url.Values
+	limit      int
+	offset     int
+	page       []T
+	index      int
+	more       bool
+	err        error
+}
+
+// newPageIterator returns an iterator over the given list endpoint.
+// query holds additional query parameters of the list call, limit and offset are managed by the iterator.
+func newPageIterator[T any](c *Client, reqContext, endpoint string, query url.Values) *PageIterator[T] {
+	if query == nil {
+		query = url.Values{}
+	}
+	return &PageIterator[T]{
+		c:          c,
+		reqContext: reqContext,
+		endpoint:   endpoint,
+		query:      query,
+		limit:      DefaultPageLimit,
+		index:      -1,
+		more:       true,
+	}
+}
+
+// Next advances the iterator to the next item, fetching the next page if required.
+// Returns false when there are no more items or an error occurred.
+func (it *PageIterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.more {
+			return false
+		}
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	return true
+}
+
+// Item returns the current item of the iterator.
+func (it *PageIterator[T]) Item() T {
+	return it.page[it.index]
+}
+
+// Err returns the error occurred while iterating, if any.
+func (it *PageIterator[T]) Err() error {
+	return it.err
+}
+
+// All drains the iterator and returns all the items and any error encountered.
+func (it *PageIterator[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// fetch retrieves the page at the current offset.
+func (it *PageIterator[T]) fetch(ctx context.Context) error {
+	req, err := it.c.creatHTTPGETAPIRequest(ctx, it.endpoint)
+	if err != nil {
+		return err
+	}
+	q := url.Values{}
+	for k, v := range it.query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(it.limit))
+	q.Set("offset", strconv.Itoa(it.offset))
+	req.HTTPRequest().URL.RawQuery = q.Encode()
+
+	var resp listResp[T]
+	err = it.c.send(ctx, it.reqContext, req, &resp, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	it.more = resp.hasMore(it.offset)
+	it.offset += len(resp.List)
+	it.page = resp.List
+	it.index = 0
+	return nil
+}
+
+// ListAPIs returns an iterator over the APIs matching the given search query.
+// An empty query lists all the APIs.
+func (c *Client) ListAPIs(query string) *PageIterator[APISearchInfo] {
+	return newPageIterator[APISearchInfo](c, APISearchContext, c.publisherAPIEndpoint, searchQuery(query))
+}
+
+// ListApplications returns an iterator over the Applications matching the given search query.
+// An empty query lists all the Applications.
+func (c *Client) ListApplications(query string) *PageIterator[ApplicationSearchInfo] {
+	return newPageIterator[ApplicationSearchInfo](c, ApplicationSearchContext, c.storeApplicationEndpoint, searchQuery(query))
+}
+
+// ListKeyManagers returns an iterator over the Key Managers.
+func (c *Client) ListKeyManagers() *PageIterator[KeyManagerSearchInfo] {
+	return newPageIterator[KeyManagerSearchInfo](c, KeyManagerSearchContext, c.storeKeyManagerEndpoint, nil)
+}
+
+// searchQuery returns the query parameters for the given API-M search query.
+func searchQuery(query string) url.Values {
+	q := url.Values{}
+	if query != "" {
+		q.Set("query", query)
+	}
+	return q
+}
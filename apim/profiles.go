@@ -0,0 +1,134 @@
+package apim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/floydspace/terraform-provider-wso2apim/client"
+	"github.com/pkg/errors"
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+)
+
+// VersionProfile holds the REST API contexts of a specific API-M version.
+type VersionProfile struct {
+	Version string
+	// Later API-M versions sharing the REST API contexts and models of the version, which cannot be told apart from it.
+	// They are accepted as the version of the profile and detected as the version of the profile.
+	Aliases                          []string
+	PublisherContext                 string
+	DevportalContext                 string
+	DynamicClientRegistrationContext string
+}
+
+// VersionProfiles lists the supported API-M versions, newest first.
+var VersionProfiles = []VersionProfile{
+	{
+		Version:                          "4.2",
+		Aliases:                          []string{"4.3"},
+		PublisherContext:                 "/api/am/publisher/v4",
+		DevportalContext:                 "/api/am/devportal/v3",
+		DynamicClientRegistrationContext: "/client-registration/v0.17/register",
+	},
+	{
+		Version:                          "4.1",
+		PublisherContext:                 "/api/am/publisher/v3",
+		DevportalContext:                 "/api/am/devportal/v2",
+		DynamicClientRegistrationContext: "/client-registration/v0.17/register",
+	},
+	{
+		Version:                          "4.0",
+		PublisherContext:                 "/api/am/publisher/v2",
+		DevportalContext:                 "/api/am/devportal/v2",
+		DynamicClientRegistrationContext: "/client-registration/v0.17/register",
+	},
+	{
+		Version:                          "3.2",
+		PublisherContext:                 "/api/am/publisher/v1",
+		DevportalContext:                 "/api/am/store/v1",
+		DynamicClientRegistrationContext: "/client-registration/v0.17/register",
+	},
+}
+
+// SupportedVersions returns the supported API-M versions, newest first, including the aliases of the profiles.
+func SupportedVersions() []string {
+	versions := make([]string, 0, len(VersionProfiles))
+	for _, p := range VersionProfiles {
+		versions = append(versions, p.Aliases...)
+		versions = append(versions, p.Version)
+	}
+	return versions
+}
+
+// GetVersionProfile returns the profile of the given API-M version, or of which it is an alias, and any error encountered.
+func GetVersionProfile(version string) (VersionProfile, error) {
+	for _, p := range VersionProfiles {
+		if p.Version == version || slices.Contains(p.Aliases, version) {
+			return p, nil
+		}
+	}
+	return VersionProfile{}, errors.New(fmt.Sprintf("unsupported API-M version %s, supported versions are %s", version, strings.Join(SupportedVersions(), ", ")))
+}
+
+// DetectVersionProfile probes the Publisher REST API contexts of the given host, newest first,
+// and returns the profile of the first one which exists and any error encountered.
+// The probe is not authenticated, so an existing context answers with 2XX, 401 or 403 while a missing one answers with 404.
+// Any other response, e.g. a 5XX of a gateway in front of API-M, is returned as an error rather than guessing the version.
+func DetectVersionProfile(ctx context.Context, httpClient *client.Client, host string) (VersionProfile, error) {
+	probed := map[string]bool{}
+	for _, p := range VersionProfiles {
+		if probed[p.PublisherContext] {
+			continue
+		}
+		probed[p.PublisherContext] = true
+
+		endpoint, err := utils.ConstructURL(host, p.PublisherContext, "/apis")
+		if err != nil {
+			return VersionProfile{}, errors.Wrap(err, "cannot construct endpoint")
+		}
+		req, err := client.CreateHTTPRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return VersionProfile{}, err
+		}
+		err = httpClient.Invoke(ctx, VersionDetectContext, req, nil, http.StatusOK)
+		if err == nil {
+			return p, nil
+		}
+		var invokeErr *client.InvokeError
+		if !errors.As(err, &invokeErr) {
+			return VersionProfile{}, err
+		}
+		switch {
+		case invokeErr.StatusCode >= 200 && invokeErr.StatusCode < 300,
+			invokeErr.StatusCode == http.StatusUnauthorized,
+			invokeErr.StatusCode == http.StatusForbidden:
+			return p, nil
+		case invokeErr.StatusCode != http.StatusNotFound:
+			return VersionProfile{}, errors.Wrapf(err, "unexpected response probing %s", endpoint)
+		}
+	}
+	return VersionProfile{}, errors.New(fmt.Sprintf("couldn't detect the API-M version of %s", host))
+}
+
+// Config returns the API-M configuration of the profile for the given host and credentials.
+func (p VersionProfile) Config(host, username, password string) APIM {
+	return APIM{
+		Version:                          p.Version,
+		Username:                         username,
+		Password:                         password,
+		TokenEndpoint:                    host + "/oauth2",
+		DynamicClientEndpoint:            host,
+		DynamicClientRegistrationContext: p.DynamicClientRegistrationContext,
+		PublisherEndpoint:                host,
+		PublisherAPIContext:              p.PublisherContext + "/apis",
+		PublisherAPIProductContext:       p.PublisherContext + "/api-products",
+		PublisherScopeContext:            p.PublisherContext + "/scopes",
+		StoreEndpoint:                    host,
+		StoreApplicationContext:          p.DevportalContext + "/applications",
+		StoreKeyManagerContext:           p.DevportalContext + "/key-managers",
+		StoreSubscriptionContext:         p.DevportalContext + "/subscriptions",
+		StoreMultipleSubscriptionContext: p.DevportalContext + "/subscriptions/multiple",
+	}
+}
@@ -0,0 +1,282 @@
+package apim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+)
+
+const (
+	// MaxRevisions is the maximum number of revisions API-M keeps for an API.
+	MaxRevisions = 5
+	// CurrentRevision is the revision ID standing for the API itself on API-M versions without revisions.
+	CurrentRevision = "current"
+	// DeploymentStatusRejected is the status of a deployment rejected by a workflow.
+	DeploymentStatusRejected = "REJECTED"
+	// DeploymentStatusCreated is the status of a deployment pending approval.
+	DeploymentStatusCreated = "CREATED"
+)
+
+// DeploymentPollInterval is the interval the deployments are polled at while waiting for them to succeed.
+var DeploymentPollInterval = 5 * time.Second
+
+// SupportsRevisions returns true if the API-M version of the client deploys APIs through revisions.
+// API-M 3.2 deploys the API itself to its gateway environments instead.
+func (c *Client) SupportsRevisions() bool {
+	return c.version != "3.2"
+}
+
+// CreateRevision creates a revision of the given API with the given description.
+// Returns the created revision and any error encountered.
+func (c *Client) CreateRevision(ctx context.Context, apiID, description string) (*APIRevision, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "revisions")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, endpoint, &APIRevision{Description: description})
+	if err != nil {
+		return nil, err
+	}
+	var resBody APIRevision
+	err = c.send(ctx, CreateRevisionContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetRevision returns the given revision of the given API and any error encountered.
+func (c *Client) GetRevision(ctx context.Context, apiID, revisionID string) (*APIRevision, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "revisions", revisionID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APIRevision
+	err = c.send(ctx, GetRevisionContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetRevisions returns the revisions of the given API, oldest first, and any error encountered.
+func (c *Client) GetRevisions(ctx context.Context, apiID string) ([]APIRevision, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "revisions")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APIRevisionList
+	err = c.send(ctx, GetRevisionContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resBody.List, nil
+}
+
+// DeleteRevision deletes the given revision of the given API, the revision must not be deployed.
+// Returns any error encountered.
+func (c *Client) DeleteRevision(ctx context.Context, apiID, revisionID string) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "revisions", revisionID)
+	if err != nil {
+		return err
+	}
+	req, err := c.creatHTTPDELETEAPIRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, DeleteRevisionContext, req, nil, http.StatusOK)
+}
+
+// PruneRevisions deletes the oldest revisions of the given API which are not deployed,
+// until a new revision can be created within the MaxRevisions limit.
+// Returns the deleted revisions and any error encountered.
+func (c *Client) PruneRevisions(ctx context.Context, apiID string) ([]APIRevision, error) {
+	revisions, err := c.GetRevisions(ctx, apiID)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []APIRevision
+	for _, r := range revisions {
+		if len(revisions)-len(deleted) < MaxRevisions {
+			break
+		}
+		if len(r.DeploymentInfo) > 0 {
+			continue
+		}
+		if err := c.DeleteRevision(ctx, apiID, r.ID); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, r)
+	}
+	return deleted, nil
+}
+
+// DeployRevision deploys the given revision of the given API to the given gateway environments.
+// A revision deployed to an environment replaces the revision previously deployed to it.
+// Returns any error encountered.
+func (c *Client) DeployRevision(ctx context.Context, apiID, revisionID string, deployments []APIRevisionDeployment) error {
+	return c.changeRevisionDeployments(ctx, DeployRevisionContext, "deploy-revision", apiID, revisionID, deployments)
+}
+
+// UndeployRevision undeploys the given revision of the given API from the given gateway environments.
+// Returns any error encountered.
+func (c *Client) UndeployRevision(ctx context.Context, apiID, revisionID string, deployments []APIRevisionDeployment) error {
+	return c.changeRevisionDeployments(ctx, UndeployRevisionContext, "undeploy-revision", apiID, revisionID, deployments)
+}
+
+func (c *Client) changeRevisionDeployments(ctx context.Context, reqContext, action, apiID, revisionID string, deployments []APIRevisionDeployment) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, action)
+	if err != nil {
+		return err
+	}
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, endpoint, deployments)
+	if err != nil {
+		return err
+	}
+	q := url.Values{}
+	q.Add("revisionId", revisionID)
+	req.HTTPRequest().URL.RawQuery = q.Encode()
+	return c.send(ctx, reqContext, req, nil, http.StatusCreated)
+}
+
+// GetDeployments returns the revision deployments of the given API and any error encountered.
+func (c *Client) GetDeployments(ctx context.Context, apiID string) ([]APIRevisionDeployment, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "deploy-revision")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APIRevisionDeploymentList
+	err = c.send(ctx, GetDeploymentsContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resBody.List, nil
+}
+
+// WaitForDeployments polls the deployments of the given API until the given revision is reported as successfully
+// deployed to all the given gateway environments, or the timeout elapses.
+// Returns any error encountered, including a rejected deployment and the timeout.
+func (c *Client) WaitForDeployments(ctx context.Context, apiID, revisionID string, environments []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		deployments, err := c.GetDeployments(ctx, apiID)
+		if err != nil {
+			return err
+		}
+		pending, err := pendingDeployments(deployments, revisionID, environments)
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+		if !time.Now().Before(deadline) {
+			return errors.New(fmt.Sprintf("revision %s is not reported as deployed to %s within %s",
+				revisionID, strings.Join(pending, ", "), timeout))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(DeploymentPollInterval):
+		}
+	}
+}
+
+// pendingDeployments returns the given gateway environments the given revision is not yet successfully deployed to,
+// and an error if a deployment was rejected.
+func pendingDeployments(deployments []APIRevisionDeployment, revisionID string, environments []string) ([]string, error) {
+	deployed := map[string]bool{}
+	for _, d := range deployments {
+		if d.RevisionUUID != revisionID {
+			continue
+		}
+		if d.Status == DeploymentStatusRejected {
+			return nil, errors.New(fmt.Sprintf("deployment of revision %s to %s was rejected", revisionID, d.Name))
+		}
+		// API-M versions not reporting the gateway deployment time are done once the deployment is approved.
+		deployed[d.Name] = d.Status != DeploymentStatusCreated && (d.SuccessDeployedTime == nil || *d.SuccessDeployedTime != "")
+	}
+	var pending []string
+	for _, env := range environments {
+		if !deployed[env] {
+			pending = append(pending, env)
+		}
+	}
+	sort.Strings(pending)
+	return pending, nil
+}
+
+// GetGatewayEnvironments returns the gateway environments the given API is deployed to on API-M versions without revisions,
+// and any error encountered.
+func (c *Client) GetGatewayEnvironments(ctx context.Context, apiID string) ([]string, error) {
+	api, err := c.getRawAPI(ctx, apiID, GetGatewayEnvironmentsContext)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := api["gatewayEnvironments"].([]interface{})
+	environments := make([]string, 0, len(raw))
+	for _, env := range raw {
+		if s, ok := env.(string); ok {
+			environments = append(environments, s)
+		}
+	}
+	return environments, nil
+}
+
+// UpdateGatewayEnvironments replaces the gateway environments the given API is deployed to
+// on API-M versions without revisions. The other fields of the API are kept as they are.
+// Returns any error encountered.
+func (c *Client) UpdateGatewayEnvironments(ctx context.Context, apiID string, environments []string) error {
+	api, err := c.getRawAPI(ctx, apiID, UpdateGatewayEnvironmentsContext)
+	if err != nil {
+		return err
+	}
+	if environments == nil {
+		environments = []string{}
+	}
+	api["gatewayEnvironments"] = environments
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID)
+	if err != nil {
+		return err
+	}
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, api)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, UpdateGatewayEnvironmentsContext, req, nil, http.StatusOK)
+}
+
+// getRawAPI returns all the fields of the given API, including the ones not modeled by APISearchInfo,
+// and any error encountered.
+func (c *Client) getRawAPI(ctx context.Context, apiID, reqContext string) (map[string]interface{}, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody map[string]interface{}
+	err = c.send(ctx, reqContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resBody, nil
+}
@@ -0,0 +1,74 @@
+package apim
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+)
+
+// CreateScope creates a shared scope, which can be used by several APIs, with the provided scope spec.
+// Returns the created scope and any error encountered.
+func (c *Client) CreateScope(ctx context.Context, scope *Scope) (*Scope, error) {
+	req, err := c.creatHTTPPOSTAPIRequest(ctx, c.publisherScopeEndpoint, scope)
+	if err != nil {
+		return nil, err
+	}
+	var resBody Scope
+	err = c.send(ctx, CreateScopeContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetScope returns the shared scope with the given ID and any error encountered.
+func (c *Client) GetScope(ctx context.Context, scopeID string) (*Scope, error) {
+	endpoint, err := utils.ConstructURL(c.publisherScopeEndpoint, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody Scope
+	err = c.send(ctx, ScopeSearchContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// UpdateScope replaces the shared scope with the given ID with the provided scope spec, the name of a scope cannot be changed.
+// Returns the updated scope and any error encountered.
+func (c *Client) UpdateScope(ctx context.Context, scopeID string, scope *Scope) (*Scope, error) {
+	endpoint, err := utils.ConstructURL(c.publisherScopeEndpoint, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPPUTAPIRequest(ctx, endpoint, scope)
+	if err != nil {
+		return nil, err
+	}
+	var resBody Scope
+	err = c.send(ctx, UpdateScopeContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// DeleteScope deletes the shared scope with the given ID, the scope must not be used by any API.
+// Returns any error encountered.
+func (c *Client) DeleteScope(ctx context.Context, scopeID string) error {
+	endpoint, err := utils.ConstructURL(c.publisherScopeEndpoint, scopeID)
+	if err != nil {
+		return err
+	}
+	req, err := c.creatHTTPDELETEAPIRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, ScopeDeleteContext, req, nil, http.StatusOK)
+}
@@ -0,0 +1,226 @@
+package apim
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/wso2/openservicebroker-apim/pkg/utils"
+)
+
+const (
+	wsdlFileName        = "api.wsdl"
+	wsdlArchiveFileName = "api.zip"
+	ErrMsgInvalidWSDL   = "WSDL definition must be a URL, the WSDL content or a base64 encoded zip archive"
+)
+
+// zipMagic is the signature a zip archive starts with.
+var zipMagic = []byte("PK\x03\x04")
+
+// IsWSDLArchive returns true if the given WSDL content is a zip archive.
+func IsWSDLArchive(content []byte) bool {
+	return bytes.HasPrefix(content, zipMagic)
+}
+
+// DecodeWSDL returns the content of the given WSDL definition, either the WSDL content
+// or a base64 encoded zip archive of the WSDL and the files it imports, and any error encountered.
+func DecodeWSDL(definition string) ([]byte, error) {
+	d := strings.TrimSpace(definition)
+	if strings.HasPrefix(d, "<") {
+		return []byte(definition), nil
+	}
+	content, err := base64.StdEncoding.DecodeString(d)
+	if err != nil || !IsWSDLArchive(content) {
+		return nil, errors.New(ErrMsgInvalidWSDL)
+	}
+	return content, nil
+}
+
+// wsdlField returns the form field carrying the given WSDL definition and any error encountered.
+func wsdlField(definition string) (formField, error) {
+	if IsDefinitionURL(definition) {
+		return formField{name: "url", value: strings.TrimSpace(definition)}, nil
+	}
+	content, err := DecodeWSDL(definition)
+	if err != nil {
+		return formField{}, err
+	}
+	if IsWSDLArchive(content) {
+		return formField{name: "file", fileName: wsdlArchiveFileName, value: string(content)}, nil
+	}
+	return formField{name: "file", fileName: wsdlFileName, value: definition}, nil
+}
+
+// ImportWSDL creates a SOAP API from the given WSDL definition, either a URL, the WSDL content or a base64 encoded zip archive.
+// implementationType is SOAP to pass the SOAP messages through to the backend,
+// or SOAPTOREST to expose the operations of the WSDL as REST resources.
+// Returns the created API and any error encountered.
+func (c *Client) ImportWSDL(ctx context.Context, definition, implementationType string, reqBody *APIReqBody) (*APICreateResp, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, "import-wsdl")
+	if err != nil {
+		return nil, err
+	}
+	field, err := wsdlField(definition)
+	if err != nil {
+		return nil, err
+	}
+	props := *reqBody
+	props.Type = implementationType
+	props.Operations = nil
+	b, err := json.Marshal(c.apiSpec(&props))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse additional properties")
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPost, endpoint,
+		field,
+		formField{name: "additionalProperties", value: string(b)},
+		formField{name: "implementationType", value: implementationType},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var resBody APICreateResp
+	err = c.send(ctx, ImportWSDLContext, req, &resBody, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &resBody, nil
+}
+
+// GetWSDL returns the WSDL content of the given API as stored by API-M, either a WSDL document or a zip archive,
+// and any error encountered.
+func (c *Client) GetWSDL(ctx context.Context, apiID string) ([]byte, error) {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "wsdl")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.creatHTTPGETAPIRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var resBody []byte
+	err = c.send(ctx, GetWSDLContext, req, &resBody, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resBody, nil
+}
+
+// UpdateWSDL replaces the WSDL of the given API with the given definition,
+// either a URL, the WSDL content or a base64 encoded zip archive.
+// Returns any error encountered.
+func (c *Client) UpdateWSDL(ctx context.Context, apiID, definition string) error {
+	endpoint, err := utils.ConstructURL(c.publisherAPIEndpoint, apiID, "wsdl")
+	if err != nil {
+		return err
+	}
+	field, err := wsdlField(definition)
+	if err != nil {
+		return err
+	}
+	req, err := c.creatHTTPMultipartAPIRequest(ctx, http.MethodPut, endpoint, field)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, UpdateWSDLContext, req, nil, http.StatusOK)
+}
+
+// NormalizeWSDL returns the canonical form of the given WSDL content, either a WSDL document or a zip archive,
+// so that definitions differing only in formatting, comments or attribute order compare equal.
+// The files of an archive are compared by name and the WSDL and XSD files by their canonical form.
+func NormalizeWSDL(content []byte) (string, error) {
+	if !IsWSDLArchive(content) {
+		return normalizeXML(content)
+	}
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", errors.Wrap(err, "unable to open the WSDL archive")
+	}
+	files := make([]*zip.File, 0, len(r.File))
+	for _, f := range r.File {
+		if !f.FileInfo().IsDir() {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	var sb strings.Builder
+	for _, f := range files {
+		b, err := readZipFile(f)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read %s of the WSDL archive", f.Name)
+		}
+		name := strings.ToLower(f.Name)
+		if strings.HasSuffix(name, ".wsdl") || strings.HasSuffix(name, ".xsd") {
+			s, err := normalizeXML(b)
+			if err != nil {
+				return "", errors.Wrapf(err, "unable to parse %s of the WSDL archive", f.Name)
+			}
+			b = []byte(s)
+		}
+		sb.WriteString(f.Name + "\n")
+		sb.Write(b)
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// normalizeXML returns the given XML document without the prolog, comments and whitespace between elements,
+// and with the attributes sorted. Namespace prefixes are kept as written.
+func normalizeXML(content []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	var sb strings.Builder
+	for {
+		t, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "unable to parse the WSDL definition")
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			sb.WriteString("<" + xmlName(t.Name))
+			attrs := append([]xml.Attr{}, t.Attr...)
+			sort.Slice(attrs, func(i, j int) bool { return xmlName(attrs[i].Name) < xmlName(attrs[j].Name) })
+			for _, a := range attrs {
+				sb.WriteString(" " + xmlName(a.Name) + `="`)
+				_ = xml.EscapeText(&sb, []byte(a.Value))
+				sb.WriteString(`"`)
+			}
+			sb.WriteString(">")
+		case xml.EndElement:
+			sb.WriteString("</" + xmlName(t.Name) + ">")
+		case xml.CharData:
+			_ = xml.EscapeText(&sb, bytes.TrimSpace(t))
+		}
+	}
+	if sb.Len() == 0 {
+		return "", errors.New("unable to parse the WSDL definition: no root element")
+	}
+	return sb.String(), nil
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright (c) 2019 WSO2 Inc. (http:www.wso2.org) All Rights Reserved.
+ *
+ * WSO2 Inc. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http:www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ *
+ * Modifications: derived from pkg/client of github.com/wso2/openservicebroker-apim.
+ * Changed to carry a context through the requests, configure TLS and timeouts per client,
+ * abort retries once the context is done, keep the body of failed responses and log with tflog.
+ */
+
+// Package client contains functions required to make HTTP calls.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pkg/errors"
+)
+
+const (
+	HeaderAuth                   = "Authorization"
+	HeaderBear                   = "Bearer "
+	HTTPContentType              = "Content-Type"
+	ContentTypeApplicationJSON   = "application/json"
+	ContentTypeURLEncoded        = "application/x-www-form-urlencoded; param=value"
+	ErrMsgUnableToCreateReq      = "unable to create request"
+	ErrMsgUnableToParseReqBody   = "unable to parse request body"
+	ErrMsgUnableToParseRespBody  = "unable to parse response body, context: %s "
+	ErrMsgUnableInitiateReq      = "unable to initiate request: %s"
+	ErrMsgUnsuccessfulAPICall    = "unsuccessful API call: %s response Code: %s URL: %s"
+	ErrMsgUnableToCloseBody      = "unable to close the body"
+	ErrMsgUnableToLoadClientCert = "unable to load the client certificate and key"
+)
+
+var (
+	ErrInvalidParameters = errors.New("invalid parameters")
+	ErrNoCACertificates  = errors.New("no valid PEM encoded CA certificates found")
+)
+
+// RetryPolicy defines a function which validate the response and apply desired policy
+// to determine whether to retry the particular request or not.
+type RetryPolicy func(resp *http.Response) bool
+
+// BackOffPolicy policy determines the duration between two retires
+type BackOffPolicy func(min, max time.Duration, attempt int) time.Duration
+
+// Config represents the HTTP client settings.
+type Config struct {
+	// InsecureCon disables the verification of the server certificate chain and host name.
+	InsecureCon bool
+	// CACertPEM holds PEM encoded CA certificates trusted in addition to the system certificate pool.
+	CACertPEM string
+	// ClientCertPEM and ClientKeyPEM hold the PEM encoded client certificate and key used for mutual TLS.
+	ClientCertPEM string
+	ClientKeyPEM  string
+	// MinBackOff and MaxBackOff bound the wait between two retries, in seconds.
+	MinBackOff int
+	MaxBackOff int
+	// Timeout is the timeout of a single request attempt, in seconds.
+	Timeout int
+	// MaxRetries is the number of retries after the first failed attempt.
+	MaxRetries int
+}
+
+// Client represent the state of the HTTP client.
+type Client struct {
+	httpClient    *http.Client
+	checkForReTry RetryPolicy
+	backOff       BackOffPolicy
+	minBackOff    time.Duration
+	maxBackOff    time.Duration
+	maxRetry      int
+}
+
+// HTTPRequest wraps the http.request and the Body.
+// Body is wrapped with io.ReadSeeker which allows to reset the body buffer reader to initial state in retires.
+type HTTPRequest struct {
+	body    io.ReadSeeker
+	httpReq *http.Request
+}
+
+// HTTPRequest returns the HTTP request.
+func (r *HTTPRequest) HTTPRequest() *http.Request {
+	return r.httpReq
+}
+
+// SetHeader method set the given header key and value to the HTTP request.
+func (r *HTTPRequest) SetHeader(k, v string) {
+	r.httpReq.Header.Set(k, v)
+}
+
+// Token returns the Bearer token of the HTTP request, if any.
+func (r *HTTPRequest) Token() string {
+	return strings.TrimPrefix(r.httpReq.Header.Get(HeaderAuth), HeaderBear)
+}
+
+// SetToken replaces the Bearer token of the HTTP request and resets the body reader,
+// so that the request can be sent again.
+func (r *HTTPRequest) SetToken(token string) error {
+	r.SetHeader(HeaderAuth, HeaderBear+token)
+	if r.body != nil {
+		if _, err := r.body.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Default returns a client which uses the http.DefaultClient.
+func Default() *Client {
+	return &Client{
+		httpClient:    http.DefaultClient,
+		checkForReTry: isRetryableResponse,
+		backOff:       calculateBackOff,
+		minBackOff:    1 * time.Second,
+		maxBackOff:    60 * time.Second,
+		maxRetry:      3,
+	}
+}
+
+// New returns a client configured with the given values and any error encountered.
+func New(c *Config) (*Client, error) {
+	tlsConfig, err := newTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: time.Duration(c.Timeout) * time.Second,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		minBackOff:    time.Duration(c.MinBackOff) * time.Second,
+		maxBackOff:    time.Duration(c.MaxBackOff) * time.Second,
+		maxRetry:      c.MaxRetries,
+		backOff:       calculateBackOff,
+		checkForReTry: isRetryableResponse,
+	}, nil
+}
+
+// newTLSConfig returns the TLS configuration for the given values and any error encountered.
+func newTLSConfig(c *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureCon}
+	if c.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(c.CACertPEM)) {
+			return nil, ErrNoCACertificates
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.ClientCertPEM != "" || c.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(c.ClientCertPEM), []byte(c.ClientKeyPEM))
+		if err != nil {
+			return nil, errors.Wrap(err, ErrMsgUnableToLoadClientCert)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// InvokeError wraps more information about the error.
+// Body holds the raw response body of an unsuccessful API call, if any.
+type InvokeError struct {
+	err        error
+	StatusCode int
+	Body       []byte
+}
+
+func (e *InvokeError) Error() string {
+	return e.err.Error()
+}
+
+// B64BasicAuth returns a base64 encoded value of "u:p" string and any error encountered.
+func B64BasicAuth(u, p string) (string, error) {
+	if u == "" || p == "" {
+		return "", ErrInvalidParameters
+	}
+	d := u + ":" + p
+	return base64.StdEncoding.EncodeToString([]byte(d)), nil
+}
+
+// ParseBody parse response body into the given struct.
+// Must send the pointer to the response body, a *[]byte receives the raw response body.
+// Returns any error encountered.
+func ParseBody(res *http.Response, v interface{}) error {
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if raw, ok := v.(*[]byte); ok {
+		*raw = b
+		return nil
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Invoke the request and parse the response body to the given struct.
+// ctx parameter carries the cancellation, deadline and log fields of the caller.
+// reqContext parameter is used to maintain the request context in the log.
+// resCode parameter is used to determine the desired response code.
+// Returns any error encountered.
+func (c *Client) Invoke(ctx context.Context, reqContext string, req *HTTPRequest, body interface{}, expectedRespCode int) error {
+	ctx = tflog.SetField(ctx, "wso2apim_request", reqContext)
+	ctx = tflog.SetField(ctx, "wso2apim_method", req.httpReq.Method)
+	ctx = tflog.SetField(ctx, "wso2apim_url", req.httpReq.URL.String())
+	req.httpReq = req.httpReq.WithContext(ctx)
+
+	tflog.Debug(ctx, "Sending WSO2 API Manager request")
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return errors.Wrapf(err, ErrMsgUnableInitiateReq, reqContext)
+	}
+	tflog.Debug(ctx, "Received WSO2 API Manager response", map[string]any{"wso2apim_status": resp.StatusCode})
+	defer closeBody(ctx, resp)
+	if resp.StatusCode != expectedRespCode {
+		b, _ := io.ReadAll(resp.Body)
+		return &InvokeError{
+			err:        errors.Errorf(ErrMsgUnsuccessfulAPICall, reqContext, resp.Status, req.httpReq.URL),
+			StatusCode: resp.StatusCode,
+			Body:       b,
+		}
+	}
+
+	// If response has a body
+	if body != nil {
+		err = ParseBody(resp, body)
+		if err != nil {
+			return &InvokeError{
+				err:        errors.Wrapf(err, ErrMsgUnableToParseRespBody, reqContext),
+				StatusCode: resp.StatusCode,
+			}
+		}
+	}
+	return nil
+}
+
+// closeBody drains and closes the body of the given response, so that the connection can be reused.
+func closeBody(ctx context.Context, resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if err := resp.Body.Close(); err != nil {
+		tflog.Warn(ctx, ErrMsgUnableToCloseBody, map[string]any{"error": err.Error()})
+	}
+}
+
+// CreateHTTPPOSTRequest returns a POST HTTP request with a Bearer token header with the content type to application/json
+// and any error encountered.
+func CreateHTTPPOSTRequest(ctx context.Context, token, url string, body io.ReadSeeker) (*HTTPRequest, error) {
+	req, err := CreateHTTPRequest(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrMsgUnableToCreateReq)
+	}
+	req.SetHeader(HeaderAuth, HeaderBear+token)
+	req.SetHeader(HTTPContentType, ContentTypeApplicationJSON)
+	return req, nil
+}
+
+// CreateHTTPPUTRequest returns a PUT HTTP request with a Bearer token header with the content type to application/json
+// and any error encountered.
+func CreateHTTPPUTRequest(ctx context.Context, token, url string, body io.ReadSeeker) (*HTTPRequest, error) {
+	req, err := CreateHTTPRequest(ctx, http.MethodPut, url, body)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrMsgUnableToCreateReq)
+	}
+	req.SetHeader(HeaderAuth, HeaderBear+token)
+	req.SetHeader(HTTPContentType, ContentTypeApplicationJSON)
+	return req, nil
+}
+
+// CreateHTTPGETRequest returns a GET HTTP request with a Bearer token header
+// and any error encountered.
+func CreateHTTPGETRequest(ctx context.Context, token, url string) (*HTTPRequest, error) {
+	req, err := CreateHTTPRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrMsgUnableToCreateReq)
+	}
+	req.SetHeader(HeaderAuth, HeaderBear+token)
+	return req, nil
+}
+
+// CreateHTTPDELETERequest returns a DELETE HTTP request with a Bearer token header
+// and any error encountered.
+func CreateHTTPDELETERequest(ctx context.Context, token, url string) (*HTTPRequest, error) {
+	req, err := CreateHTTPRequest(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrMsgUnableToCreateReq)
+	}
+	req.SetHeader(HeaderAuth, HeaderBear+token)
+	return req, nil
+}
+
+// BodyReader returns the byte buffer representation of the provided struct and any error encountered.
+func BodyReader(v interface{}) (io.ReadSeeker, error) {
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(v)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrMsgUnableToParseReqBody)
+	}
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+// CreateHTTPRequest returns client.HTTPRequest struct which wraps the http.request, request Body, and any error encountered.
+func CreateHTTPRequest(ctx context.Context, method, url string, body io.ReadSeeker) (*HTTPRequest, error) {
+	var rcBody io.ReadCloser
+	if body != nil {
+		rcBody = io.NopCloser(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, rcBody)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPRequest{httpReq: req, body: body}, nil
+}
+
+// do invokes the request and returns the response and, an error if exists.
+// If the request is failed it will retry according to the registered Retry policy and Back off policy.
+// Waiting between retries is aborted as soon as the given context is done.
+func (c *Client) do(ctx context.Context, req *HTTPRequest) (resp *http.Response, err error) {
+	for attempt := 1; ; attempt++ {
+		resp, err = c.httpClient.Do(req.httpReq)
+		// This error occurs due to  network connectivity problem and not for non 2xx responses.
+		if err != nil {
+			return nil, err
+		}
+		if attempt > c.maxRetry || !c.checkForReTry(resp) {
+			return resp, nil
+		}
+		closeBody(ctx, resp)
+
+		if req.body != nil {
+			// Reset the body reader
+			if _, err := req.body.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		bt := c.backOff(c.minBackOff, c.maxBackOff, attempt)
+		tflog.Debug(ctx, "Retrying WSO2 API Manager request", map[string]any{
+			"wso2apim_status":   resp.StatusCode,
+			"wso2apim_attempt":  attempt,
+			"wso2apim_back_off": bt.Seconds(),
+		})
+		if err := sleep(ctx, bt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for the given duration or until the context is done.
+// Returns the context error if the wait was aborted.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// isRetryableResponse will retry the request if the response code is 429 or 5XX.
+// Other 4XX responses are not retried since repeating the same request results in the same response.
+func isRetryableResponse(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// calculateBackOff waits until attempt^2 or (min,max).
+func calculateBackOff(min, max time.Duration, attempt int) time.Duration {
+	du := math.Pow(2, float64(attempt))
+	sleep := time.Duration(du) * time.Second
+	if sleep < min {
+		return min
+	}
+	if sleep > max {
+		return max
+	}
+	return sleep
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) 2019 WSO2 Inc. (http:www.wso2.org) All Rights Reserved.
+ *
+ * WSO2 Inc. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http:www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ *
+ * Modifications: derived from pkg/client of github.com/wso2/openservicebroker-apim.
+ * Changed to cover the context, TLS, retry and response body handling of the client.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
+)
+
+type testVal struct {
+	ID   int
+	Name string
+}
+
+const (
+	HTTPMockEndpoint          = "https://localhost/api"
+	Context                   = "testing"
+	Token                     = "Token"
+	PayloadID                 = 1
+	PayloadName               = "test"
+	ErrMsgTestIncorrectResult = "expected value: %v but then returned value: %v"
+)
+
+var payload = testVal{
+	ID:   PayloadID,
+	Name: PayloadName,
+}
+
+func TestB64BasicAuth(t *testing.T) {
+	_, err := B64BasicAuth("", "")
+	if err == nil {
+		t.Errorf("Expected error didn't occur")
+	}
+	re1, err := B64BasicAuth("admin", "admin")
+	if err != nil {
+		t.Error(err)
+	}
+	exp := "YWRtaW46YWRtaW4="
+	if re1 != exp {
+		t.Errorf(ErrMsgTestIncorrectResult, exp, re1)
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	t.Run("success test case", testInvokeSuccessFunc())
+	t.Run("failure test case", testInvokeFailFunc())
+}
+
+func testInvokeSuccessFunc() func(t *testing.T) {
+	return func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		responder, err := httpmock.NewJsonResponder(http.StatusOK, payload)
+		if err != nil {
+			t.Error(err)
+		}
+		httpmock.RegisterResponder(http.MethodGet, HTTPMockEndpoint, responder)
+
+		req, err := CreateHTTPGETRequest(context.Background(), Token, HTTPMockEndpoint)
+		if err != nil {
+			t.Error(err)
+		}
+		var body testVal
+		err = Default().Invoke(context.Background(), Context, req, &body, http.StatusOK)
+		if err != nil {
+			t.Error(err)
+		}
+		if body != payload {
+			t.Errorf(ErrMsgTestIncorrectResult, payload, body)
+		}
+	}
+}
+
+func testInvokeFailFunc() func(t *testing.T) {
+	return func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		responder, err := httpmock.NewJsonResponder(http.StatusInternalServerError, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		httpmock.RegisterResponder(http.MethodGet, HTTPMockEndpoint, responder)
+
+		req, err := CreateHTTPGETRequest(context.Background(), Token, HTTPMockEndpoint)
+		if err != nil {
+			t.Error(err)
+		}
+		c := Default()
+		c.minBackOff = time.Millisecond
+		c.maxBackOff = time.Millisecond
+		err = c.Invoke(context.Background(), Context, req, nil, http.StatusOK)
+		if err == nil {
+			t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusInternalServerError))
+		}
+		invokeErr, ok := err.(*InvokeError)
+		if !ok {
+			t.Fatalf("expected *InvokeError but got %T", err)
+		}
+		if invokeErr.StatusCode != http.StatusInternalServerError {
+			t.Errorf(ErrMsgTestIncorrectResult, http.StatusInternalServerError, invokeErr.StatusCode)
+		}
+		if calls := httpmock.GetTotalCallCount(); calls != c.maxRetry+1 {
+			t.Errorf(ErrMsgTestIncorrectResult, c.maxRetry+1, calls)
+		}
+	}
+}
+
+func TestInvokeCancelled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusInternalServerError, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, HTTPMockEndpoint, responder)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := CreateHTTPGETRequest(ctx, Token, HTTPMockEndpoint)
+	if err != nil {
+		t.Error(err)
+	}
+	start := time.Now()
+	err = Default().Invoke(ctx, Context, req, nil, http.StatusOK)
+	if err == nil {
+		t.Error("Expecting an error due to the cancelled context")
+	}
+	if errors.Cause(err) != context.DeadlineExceeded {
+		t.Errorf(ErrMsgTestIncorrectResult, context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the retry back off to be aborted but Invoke took %s", elapsed)
+	}
+}
+
+func TestInvokeNotRetried(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusNotFound, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, HTTPMockEndpoint, responder)
+
+	req, err := CreateHTTPGETRequest(context.Background(), Token, HTTPMockEndpoint)
+	if err != nil {
+		t.Error(err)
+	}
+	err = Default().Invoke(context.Background(), Context, req, nil, http.StatusOK)
+	if err == nil {
+		t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusNotFound))
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, 1, calls)
+	}
+}
+
+// closeRecorder records whether the response body was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestInvokeClosesBody(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   interface{}
+	}{
+		{name: "success without body", status: http.StatusOK},
+		{name: "success with body", status: http.StatusOK, body: &testVal{}},
+		{name: "unexpected status", status: http.StatusNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			respBody := &closeRecorder{Reader: strings.NewReader(`{"ID": 1, "Name": "test"}`)}
+			httpmock.RegisterResponder(http.MethodGet, HTTPMockEndpoint, func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: c.status, Body: respBody, Header: http.Header{}, Request: req}, nil
+			})
+
+			req, err := CreateHTTPGETRequest(context.Background(), Token, HTTPMockEndpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = Default().Invoke(context.Background(), Context, req, c.body, http.StatusOK)
+			if !respBody.closed {
+				t.Error("expected the response body to be closed")
+			}
+		})
+	}
+}
+
+func TestNewWithCACert(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HTTPContentType, ContentTypeApplicationJSON)
+		w.Write([]byte(`{"ID": 1, "Name": "test"}`))
+	}))
+	defer srv.Close()
+
+	req, err := CreateHTTPGETRequest(context.Background(), Token, srv.URL)
+	if err != nil {
+		t.Error(err)
+	}
+	untrusted, err := New(&Config{Timeout: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = untrusted.Invoke(context.Background(), Context, req, nil, http.StatusOK); err == nil {
+		t.Error("Expecting a certificate verification error")
+	}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	trusted, err := New(&Config{Timeout: 5, CACertPEM: string(caPEM)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err = CreateHTTPGETRequest(context.Background(), Token, srv.URL)
+	if err != nil {
+		t.Error(err)
+	}
+	var body testVal
+	if err = trusted.Invoke(context.Background(), Context, req, &body, http.StatusOK); err != nil {
+		t.Error(err)
+	}
+	if body != payload {
+		t.Errorf(ErrMsgTestIncorrectResult, payload, body)
+	}
+}
+
+func TestNewWithInvalidCerts(t *testing.T) {
+	if _, err := New(&Config{CACertPEM: "not a certificate"}); err != ErrNoCACertificates {
+		t.Errorf(ErrMsgTestIncorrectResult, ErrNoCACertificates, err)
+	}
+	if _, err := New(&Config{ClientCertPEM: "not a certificate"}); err == nil {
+		t.Error("Expecting an error for an invalid client certificate")
+	}
+}
+
+func TestCalculateBackOff(t *testing.T) {
+	if got := calculateBackOff(5*time.Second, 60*time.Second, 1); got != 5*time.Second {
+		t.Errorf(ErrMsgTestIncorrectResult, 5*time.Second, got)
+	}
+	if got := calculateBackOff(1*time.Second, 60*time.Second, 3); got != 8*time.Second {
+		t.Errorf(ErrMsgTestIncorrectResult, 8*time.Second, got)
+	}
+	if got := calculateBackOff(1*time.Second, 10*time.Second, 6); got != 10*time.Second {
+		t.Errorf(ErrMsgTestIncorrectResult, 10*time.Second, got)
+	}
+}
@@ -4,3 +4,15 @@ provider "wso2apim" {
   username = "NPA"
   password = "123456"
 }
+
+# Client credentials authentication with a pre-provisioned OAuth client
+provider "wso2apim" {
+  alias = "ci"
+  host  = "https://localhost:9443"
+
+  auth {
+    grant_type    = "client_credentials"
+    client_id     = "ci-client"
+    client_secret = "ci-secret"
+  }
+}
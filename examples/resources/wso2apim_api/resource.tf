@@ -5,3 +5,144 @@ resource "wso2apim_api" "example" {
   context     = "/foo"
   version     = "v1"
 }
+
+# Manage example WSO2 API Manager Api imported from an OpenAPI definition
+resource "wso2apim_api" "pets" {
+  name               = "pets-api"
+  context            = "/pets"
+  version            = "v1"
+  openapi_definition = file("${path.module}/openapi.yaml")
+}
+
+# Manage a new version of the example Api, keeping the subscriptions of v1 during the migration
+resource "wso2apim_api" "example_v2" {
+  name               = wso2apim_api.example.name
+  context            = wso2apim_api.example.context
+  version            = "v2"
+  source_api_id      = wso2apim_api.example.id
+  is_default_version = true
+}
+
+# Manage an Api restricting its operations with a shared scope and an api-local scope
+resource "wso2apim_api" "items" {
+  name    = "items-api"
+  context = "/items"
+  version = "v1"
+
+  scopes = [{
+    name   = wso2apim_scope.example.name
+    shared = true
+    }, {
+    name  = "items:write"
+    roles = ["admin"]
+  }]
+
+  operations = [{
+    target = "/items"
+    verb   = "GET"
+    scopes = [wso2apim_scope.example.name]
+    }, {
+    target = "/items"
+    verb   = "POST"
+    scopes = ["items:write"]
+  }]
+}
+
+# Manage an internal Api, hidden from the other tenants and only modifiable by the platform team
+resource "wso2apim_api" "internal" {
+  name                 = "internal-api"
+  context              = "/internal"
+  version              = "v1"
+  visibility           = "RESTRICTED"
+  visible_roles        = ["internal"]
+  access_control       = "RESTRICTED"
+  access_control_roles = ["platform"]
+}
+
+# Manage an Api called by browser applications, accepting OAuth2 access tokens and API keys
+resource "wso2apim_api" "spa" {
+  name                 = "spa-api"
+  context              = "/spa"
+  version              = "v1"
+  authorization_header = "X-Authorization"
+
+  security = {
+    oauth2  = true
+    api_key = true
+  }
+
+  cors_configuration = {
+    allow_origins = ["https://app.example.com"]
+    allow_headers = ["X-Authorization", "Content-Type"]
+    allow_methods = ["GET", "POST", "OPTIONS"]
+  }
+}
+
+# Manage an Api calling its backend with the client credentials of an OAuth2 client
+resource "wso2apim_api" "backend" {
+  name    = "backend-api"
+  context = "/backend"
+  version = "v1"
+
+  endpoint_config = {
+    endpoint_type = "http"
+    production_endpoints = {
+      url = "https://backend.example.com"
+    }
+  }
+
+  endpoint_security = {
+    production = {
+      type          = "OAUTH"
+      grant_type    = "CLIENT_CREDENTIALS"
+      token_url     = "https://idp.example.com/oauth2/token"
+      client_id     = var.backend_client_id
+      client_secret = var.backend_client_secret
+    }
+  }
+}
+
+# Manage an Api falling back to a standby backend when the active backend times out
+resource "wso2apim_api" "failover" {
+  name    = "failover-api"
+  context = "/failover"
+  version = "v1"
+
+  endpoint_config = {
+    endpoint_type = "failover"
+    production_endpoints = {
+      url = "https://active.example.com"
+      advanced = {
+        action_duration   = 30000
+        action_select     = "fault"
+        retry_error_codes = ["101504"]
+      }
+    }
+    production_failovers = [{
+      url = "https://standby.example.com"
+    }]
+  }
+}
+
+# Manage an Api listed in the Developer Portal catalogue with its owners, tags and custom properties
+resource "wso2apim_api" "payments" {
+  name       = "payments-api"
+  context    = "/payments"
+  version    = "v1"
+  tags       = ["payments", "finance"]
+  categories = ["Finance"]
+
+  business_information = {
+    business_owner        = "Payments"
+    business_owner_email  = "payments@example.com"
+    technical_owner       = "Payments Platform"
+    technical_owner_email = "payments-oncall@example.com"
+  }
+
+  additional_properties = {
+    team = {
+      value   = "payments"
+      display = true
+    }
+  }
+}
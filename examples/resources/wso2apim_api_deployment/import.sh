@@ -0,0 +1,2 @@
+# Api deployment can be imported by specifying the api identifier.
+terraform import wso2apim_api_deployment.example 00000000-0000-0000-0000-000000000000
@@ -0,0 +1,10 @@
+# Manage example WSO2 API Manager Api deployment
+resource "wso2apim_api_deployment" "example" {
+  api_id      = wso2apim_api.example.id
+  revision_id = wso2apim_api_revision.example.id
+
+  environments = [{
+    name  = "Default"
+    vhost = "localhost"
+  }]
+}
@@ -0,0 +1,2 @@
+# Api product can be imported by specifying the api product identifier.
+terraform import wso2apim_api_product.example 00000000-0000-0000-0000-000000000000
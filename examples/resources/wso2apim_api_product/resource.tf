@@ -0,0 +1,28 @@
+# Manage example WSO2 API Manager Api Product bundling operations of two apis
+resource "wso2apim_api_product" "example" {
+  name        = "partner-product"
+  description = "Partner offering"
+  context     = "/partner"
+  policies    = ["Gold"]
+
+  apis = [{
+    api_id = wso2apim_api.orders.id
+    operations = [{
+      target = "/orders"
+      verb   = "GET"
+    }]
+    }, {
+    api_id = wso2apim_api.items.id
+    operations = [{
+      target = "/items"
+      verb   = "GET"
+      }, {
+      target = "/items/{id}"
+      verb   = "GET"
+    }]
+  }]
+
+  deployment_environments = [{
+    name = "Default"
+  }]
+}
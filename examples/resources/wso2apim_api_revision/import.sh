@@ -0,0 +1,2 @@
+# Api revision can be imported by specifying the api identifier and the revision identifier separated by a comma.
+terraform import wso2apim_api_revision.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
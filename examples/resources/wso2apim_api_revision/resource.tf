@@ -0,0 +1,14 @@
+# Manage example WSO2 API Manager Api revision, a new revision is created whenever the api changes
+resource "wso2apim_api_revision" "example" {
+  api_id      = wso2apim_api.example.id
+  description = "Managed by Terraform"
+
+  triggers = {
+    last_updated = wso2apim_api.example.last_updated
+  }
+
+  # Keep the deployed revision until the new one replaces it on the gateways
+  lifecycle {
+    create_before_destroy = true
+  }
+}
@@ -0,0 +1,2 @@
+# Shared scope can be imported by specifying the scope identifier.
+terraform import wso2apim_scope.example 00000000-0000-0000-0000-000000000000
@@ -0,0 +1,7 @@
+# Manage example WSO2 API Manager shared Scope
+resource "wso2apim_scope" "example" {
+  name         = "items:read"
+  display_name = "Read items"
+  description  = "Read the items of the inventory"
+  roles        = ["admin", "Internal/subscriber"]
+}
@@ -4,3 +4,10 @@ resource "wso2apim_subscription" "example" {
   api_id            = wso2apim_api.example.id
   throttling_policy = "Unlimited"
 }
+
+# Manage example WSO2 API Manager Subscription to an Api Product
+resource "wso2apim_subscription" "product" {
+  application_id    = wso2apim_application.example.id
+  api_product_id    = wso2apim_api_product.example.id
+  throttling_policy = "Unlimited"
+}
@@ -0,0 +1,120 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/floydspace/terraform-provider-wso2apim/client"
+	"github.com/pkg/errors"
+	"github.com/wso2/openservicebroker-apim/pkg/log"
+)
+
+// ClientCredentialsGrantManager is used to manage Access token using client_credentials grant type.
+// Uses a pre-provisioned OAuth client, so no dynamic client registration is performed.
+// The client_credentials grant does not issue a refresh token, hence an expired access token is generated again.
+type ClientCredentialsGrantManager struct {
+	once          sync.Once
+	initErr       error
+	token         *token
+	scopes        []string
+	TokenEndpoint string
+	ClientID      string
+	ClientSecret  string
+	HTTPClient    *client.Client
+	// RefreshSkew is the time before the expiry at which the access token is generated again.
+	RefreshSkew time.Duration
+}
+
+// Init initialize the Token Manager. Generate token for the given scopes.
+// Must run before using the Token Manager.
+// Returns any error encountered, subsequent calls return the error of the first one.
+func (m *ClientCredentialsGrantManager) Init(ctx context.Context, scopes []string) error {
+	m.once.Do(func() {
+		m.initErr = m.init(ctx, scopes)
+	})
+	return m.initErr
+}
+
+// init generates the token for the given scopes.
+func (m *ClientCredentialsGrantManager) init(ctx context.Context, scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.New(ErrMSGNotEnoughArgs)
+	}
+	m.scopes = scopes
+	aT, expiresIn, err := m.generateToken(ctx, GenerateAccessToken)
+	if err != nil {
+		return errors.Wrapf(err, ErrMSGUnableToGetAccessToken, scopes)
+	}
+	m.token = &token{
+		accessToken: aT,
+		expiresIn:   expiresIn,
+	}
+	ld := log.NewData().
+		Add(LogKeyAT, aT).
+		Add(LogKeyExpiresIn, expiresIn)
+	log.Debug(fmt.Sprintf("generated a token for scopes %v", scopes), ld)
+	return nil
+}
+
+// Token method returns an access token and any error occurred.
+func (m *ClientCredentialsGrantManager) Token(ctx context.Context) (string, error) {
+	m.token.lock.RLock()
+	if !isExpired(m.token.expiresIn, m.RefreshSkew) {
+		aT := m.token.accessToken
+		m.token.lock.RUnlock()
+		return aT, nil
+	}
+	m.token.lock.RUnlock()
+	m.token.lock.Lock()
+	defer m.token.lock.Unlock()
+	if !isExpired(m.token.expiresIn, m.RefreshSkew) {
+		return m.token.accessToken, nil
+	}
+	return m.renew(ctx)
+}
+
+// Refresh method forces a new access token to be generated, since the given one was rejected.
+// If the given token has already been replaced, the current access token is returned instead.
+// Returns the access token and any error occurred.
+func (m *ClientCredentialsGrantManager) Refresh(ctx context.Context, rejected string) (string, error) {
+	m.token.lock.Lock()
+	defer m.token.lock.Unlock()
+	if m.token.accessToken != rejected {
+		return m.token.accessToken, nil
+	}
+	return m.renew(ctx)
+}
+
+// renew method generates a new access token. The caller must hold the write lock of the token.
+func (m *ClientCredentialsGrantManager) renew(ctx context.Context) (string, error) {
+	log.Debug("access token is expired, re-generating", log.NewData().
+		Add(LogKeyExpiresIn, m.token.expiresIn.String()))
+	aT, expiresIn, err := m.generateToken(ctx, GenerateAccessToken)
+	if err != nil {
+		return "", err
+	}
+	m.token.accessToken = aT
+	m.token.expiresIn = expiresIn
+	return aT, nil
+}
+
+// createAccessTokenReq method returns access token request body for the scopes of the manager.
+func (m *ClientCredentialsGrantManager) createAccessTokenReq() url.Values {
+	data := url.Values{}
+	data.Set(GrantType, GrantClientCredentials)
+	data.Set(Scope, strings.Join(m.scopes, " "))
+	return data
+}
+
+// generateToken method returns a new Access token and its expire time.
+func (m *ClientCredentialsGrantManager) generateToken(ctx context.Context, reqContext string) (string, time.Time, error) {
+	resBody, err := requestToken(ctx, m.HTTPClient, m.TokenEndpoint, m.ClientID, m.ClientSecret, m.createAccessTokenReq(), reqContext)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return resBody.AccessToken, time.Now().Add(time.Duration(resBody.ExpiresIn) * time.Second), nil
+}
@@ -21,15 +21,15 @@ package token
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
-	"strconv"
 	"sync"
 	"time"
 
+	"github.com/floydspace/terraform-provider-wso2apim/client"
 	"github.com/pkg/errors"
-	"github.com/wso2/openservicebroker-apim/pkg/client"
 	"github.com/wso2/openservicebroker-apim/pkg/log"
 	"github.com/wso2/openservicebroker-apim/pkg/utils"
 )
@@ -50,6 +50,7 @@ const (
 	Password                        = "password"
 	GrantPassword                   = "password"
 	GrantRefreshToken               = "refresh_token"
+	GrantClientCredentials          = "client_credentials"
 	GrantType                       = "grant_type"
 	Scope                           = "scope"
 	RefreshToken                    = "refresh_token"
@@ -59,6 +60,7 @@ const (
 	ScopeAppPublish                 = "apim:api_publish"
 	ScopeAPIDelete                  = "apim:api_delete"
 	ScopeAppManage                  = "apim:app_manage"
+	ScopeSharedScopeManage          = "apim:shared_scope_manage"
 	LogKeyAT                        = "access-token"
 	LogKeyRT                        = "refresh-token"
 	LogKeyExpiresIn                 = "expires in"
@@ -74,6 +76,9 @@ const (
 
 	// Owner for dynamic client registration
 	Owner = "admin"
+
+	// DefaultRefreshSkew is the default time before the expiry at which an access token is refreshed
+	DefaultRefreshSkew = 30 * time.Second
 )
 
 // BasicCredentials represents the username and Password.
@@ -120,9 +125,12 @@ type token struct {
 
 // PasswordRefreshTokenGrantManager is used to manage Access token using password and refresh_token grant type.
 // Holds access token for the given scopes and regenerate token using refresh token if the access token is expired.
+// If the refresh token is rejected, a new access token is generated using the password grant.
 type PasswordRefreshTokenGrantManager struct {
 	once                             sync.Once
+	initErr                          error
 	token                            *token
+	scopes                           []string
 	clientID                         string
 	clientSec                        string
 	TokenEndpoint                    string
@@ -130,59 +138,72 @@ type PasswordRefreshTokenGrantManager struct {
 	DynamicClientRegistrationContext string
 	UserName                         string
 	Password                         string
+	HTTPClient                       *client.Client
+	// RefreshSkew is the time before the expiry at which the access token is refreshed.
+	RefreshSkew time.Duration
 }
 
 // Manager interface manages the token for a set of given scopes.
 type Manager interface {
 	// Init initialize the Token Manager. Generate token for the given scopes.
 	// Must run before using the Token Manager.
-	Init(scopes []string)
+	// Returns any error encountered.
+	Init(ctx context.Context, scopes []string) error
 
 	// Token method returns an access token and any error occurred.
-	Token() (string, error)
+	Token(ctx context.Context) (string, error)
+
+	// Refresh method forces a new access token to be generated, since the given one was rejected.
+	// If the given token has already been replaced, the current access token is returned instead.
+	// Returns the access token and any error occurred.
+	Refresh(ctx context.Context, rejected string) (string, error)
 }
 
 // Init initialize the Token Manager. Generate token for the given scopes.
 // Must run before using the Token Manager.
-func (m *PasswordRefreshTokenGrantManager) Init(scopes []string) {
+// Returns any error encountered, subsequent calls return the error of the first one.
+func (m *PasswordRefreshTokenGrantManager) Init(ctx context.Context, scopes []string) error {
 	m.once.Do(func() {
-		if len(scopes) == 0 {
-			log.HandleErrorAndExit(ErrMSGNotEnoughArgs, nil)
-		}
-		err := m.registerDynamicClient(&DynamicClientRegReq{
-			// CallbackURL: CallBackURL,
-			ClientName: ClientName,
-			GrantType:  DynamicClientRegGrantType,
-			Owner:      m.UserName,
-			SaasApp:    true,
-		})
-		if err != nil {
-			log.HandleErrorAndExit(ErrMSGUnableToGetClientCreds, err)
-		}
-
-		data := m.createAccessTokenReq(scopes)
-		aT, rT, validPeriod, err := m.generateToken(data, GenerateAccessToken)
-		if err != nil {
-			log.HandleErrorAndExit(fmt.Sprintf(ErrMSGUnableToGetAccessToken, scopes), err)
-		}
-		// Handling the expire time of the access token
-		duration, err := time.ParseDuration(strconv.Itoa(validPeriod) + "s")
-		if err != nil {
-			log.HandleErrorAndExit(ErrMSGUnableToParseExpireTime, err)
-		}
+		m.initErr = m.init(ctx, scopes)
+	})
+	return m.initErr
+}
 
-		expiresIn := time.Now().Add(duration)
-		m.token = &token{
-			accessToken:  aT,
-			refreshToken: rT,
-			expiresIn:    expiresIn,
-		}
-		ld := log.NewData().
-			Add(LogKeyAT, aT).
-			Add(LogKeyExpiresIn, expiresIn).
-			Add(LogKeyRT, rT)
-		log.Debug(fmt.Sprintf("generated a token for scopes %v", scopes), ld)
+// init registers a dynamic client and generates the token for the given scopes.
+func (m *PasswordRefreshTokenGrantManager) init(ctx context.Context, scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.New(ErrMSGNotEnoughArgs)
+	}
+	m.scopes = scopes
+	err := m.registerDynamicClient(ctx, &DynamicClientRegReq{
+		// CallbackURL: CallBackURL,
+		ClientName: ClientName,
+		GrantType:  DynamicClientRegGrantType,
+		Owner:      m.UserName,
+		SaasApp:    true,
 	})
+	if err != nil {
+		return errors.Wrap(err, ErrMSGUnableToGetClientCreds)
+	}
+
+	data := m.createAccessTokenReq(scopes)
+	aT, rT, validPeriod, err := m.generateToken(ctx, data, GenerateAccessToken)
+	if err != nil {
+		return errors.Wrapf(err, ErrMSGUnableToGetAccessToken, scopes)
+	}
+
+	expiresIn := time.Now().Add(time.Duration(validPeriod) * time.Second)
+	m.token = &token{
+		accessToken:  aT,
+		refreshToken: rT,
+		expiresIn:    expiresIn,
+	}
+	ld := log.NewData().
+		Add(LogKeyAT, aT).
+		Add(LogKeyExpiresIn, expiresIn).
+		Add(LogKeyRT, rT)
+	log.Debug(fmt.Sprintf("generated a token for scopes %v", scopes), ld)
+	return nil
 }
 
 // createAccessTokenReq method returns access token request body for the given scopes.
@@ -211,22 +232,18 @@ func createRefreshTokenReq(rT string) url.Values {
 	return data
 }
 
-// isExpired method returns true if the difference between the current time and given time is 10s.
-func isExpired(expiresIn time.Time) bool {
-	if time.Now().Sub(expiresIn) > (10 * time.Second) {
-		return true
-	}
-	return false
+// isExpired method returns true if the given expire time is within the given skew from the current time.
+func isExpired(expiresIn time.Time, skew time.Duration) bool {
+	return !time.Now().Add(skew).Before(expiresIn)
 }
 
 // Token method returns an access token and any error occurred.
-func (m *PasswordRefreshTokenGrantManager) Token() (string, error) {
+func (m *PasswordRefreshTokenGrantManager) Token(ctx context.Context) (string, error) {
 	m.token.lock.RLock()
-	ld := log.NewData().
-		Add(LogKeyAT, m.token.accessToken).
-		Add(LogKeyExpiresIn, m.token.expiresIn.String()).
-		Add(LogKeyRT, m.token.refreshToken)
-	if !isExpired(m.token.expiresIn) {
+	if !isExpired(m.token.expiresIn, m.RefreshSkew) {
+		ld := log.NewData().
+			Add(LogKeyAT, m.token.accessToken).
+			Add(LogKeyExpiresIn, m.token.expiresIn.String())
 		log.Debug("access token is not expired", ld)
 		aT := m.token.accessToken
 		m.token.lock.RUnlock()
@@ -234,22 +251,41 @@ func (m *PasswordRefreshTokenGrantManager) Token() (string, error) {
 	}
 	m.token.lock.RUnlock()
 	m.token.lock.Lock()
-	if !isExpired(m.token.expiresIn) {
-		m.token.lock.Unlock()
+	defer m.token.lock.Unlock()
+	if !isExpired(m.token.expiresIn, m.RefreshSkew) {
 		return m.token.accessToken, nil
 	}
-	ld = log.NewData().
+	return m.renew(ctx)
+}
+
+// Refresh method forces a new access token to be generated, since the given one was rejected.
+// If the given token has already been replaced, the current access token is returned instead.
+// Returns the access token and any error occurred.
+func (m *PasswordRefreshTokenGrantManager) Refresh(ctx context.Context, rejected string) (string, error) {
+	m.token.lock.Lock()
+	defer m.token.lock.Unlock()
+	if m.token.accessToken != rejected {
+		return m.token.accessToken, nil
+	}
+	return m.renew(ctx)
+}
+
+// renew method generates a new access token using the refresh token and falls back to the password grant
+// if the refresh token is rejected. The caller must hold the write lock of the token.
+func (m *PasswordRefreshTokenGrantManager) renew(ctx context.Context) (string, error) {
+	ld := log.NewData().
 		Add(LogKeyAT, m.token.accessToken).
 		Add(LogKeyExpiresIn, m.token.expiresIn.String()).
 		Add(LogKeyRT, m.token.refreshToken)
 	log.Debug("access token is expired, re-generating", ld)
-	aT, rT, expiresIn, err := m.generateRefreshToken(m.token.refreshToken)
+	aT, rT, expiresIn, err := m.generateRefreshToken(ctx, m.token.refreshToken)
 	if err != nil {
-		m.token.lock.Unlock()
-		return "", err
+		log.Debug("refresh token is rejected, re-generating using the password grant", ld)
+		aT, rT, expiresIn, err = m.generateToken(ctx, m.createAccessTokenReq(m.scopes), GenerateAccessToken)
+		if err != nil {
+			return "", errors.Wrapf(err, ErrMSGUnableToGetAccessToken, m.scopes)
+		}
 	}
-	//Parse time to type time.Duration
-	duration, err := time.ParseDuration(strconv.Itoa(expiresIn) + SuffixSecond)
 	ld = log.NewData().
 		Add(LogKeyAT, aT).
 		Add(LogKeyRT, rT).
@@ -257,16 +293,14 @@ func (m *PasswordRefreshTokenGrantManager) Token() (string, error) {
 	log.Debug("new access token is generated", ld)
 	m.token.refreshToken = rT
 	m.token.accessToken = aT
-	m.token.expiresIn = time.Now().Add(duration)
-
-	m.token.lock.Unlock()
+	m.token.expiresIn = time.Now().Add(time.Duration(expiresIn) * time.Second)
 	return aT, nil
 }
 
 // generateRefreshToken method generates a new Access token and a Refresh token.
-func (m *PasswordRefreshTokenGrantManager) generateRefreshToken(rTNow string) (aT, newRT string, expiresIn int, err error) {
+func (m *PasswordRefreshTokenGrantManager) generateRefreshToken(ctx context.Context, rTNow string) (aT, newRT string, expiresIn int, err error) {
 	data := createRefreshTokenReq(rTNow)
-	aT, rT, expiresIn, err := m.generateToken(data, RefreshTokenContext)
+	aT, rT, expiresIn, err := m.generateToken(ctx, data, RefreshTokenContext)
 	if err != nil {
 		return "", "", 0, err
 	}
@@ -274,27 +308,37 @@ func (m *PasswordRefreshTokenGrantManager) generateRefreshToken(rTNow string) (a
 }
 
 // generateToken method returns an Access token and a Refresh token from given params.
-func (m *PasswordRefreshTokenGrantManager) generateToken(reqBody url.Values, context string) (aT, rT string, expiresIn int, err error) {
-	u, err := utils.ConstructURL(m.TokenEndpoint, Context)
+func (m *PasswordRefreshTokenGrantManager) generateToken(ctx context.Context, reqBody url.Values, reqContext string) (aT, rT string, expiresIn int, err error) {
+	resBody, err := requestToken(ctx, m.HTTPClient, m.TokenEndpoint, m.clientID, m.clientSec, reqBody, reqContext)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return resBody.AccessToken, resBody.RefreshToken, resBody.ExpiresIn, nil
+}
+
+// requestToken calls the token endpoint with the given request body authenticated by the given client credentials.
+// Returns the token response and any error encountered.
+func requestToken(ctx context.Context, httpClient *client.Client, tokenEndpoint, clientID, clientSecret string, reqBody url.Values, reqContext string) (*Resp, error) {
+	u, err := utils.ConstructURL(tokenEndpoint, Context)
 	if err != nil {
-		return "", "", 0, errors.Wrap(err, "cannot construct, token endpoint")
+		return nil, errors.Wrap(err, "cannot construct, token endpoint")
 	}
-	req, err := client.CreateHTTPRequest(http.MethodPost, u, bytes.NewReader([]byte(reqBody.Encode())))
+	req, err := client.CreateHTTPRequest(ctx, http.MethodPost, u, bytes.NewReader([]byte(reqBody.Encode())))
 	if err != nil {
-		return "", "", 0, errors.Wrapf(err, ErrMsgUnableToCreateRequestBody,
-			context)
+		return nil, errors.Wrapf(err, ErrMsgUnableToCreateRequestBody,
+			reqContext)
 	}
-	req.HTTPRequest().SetBasicAuth(m.clientID, m.clientSec)
+	req.HTTPRequest().SetBasicAuth(clientID, clientSecret)
 	req.SetHeader(client.HTTPContentType, client.ContentTypeURLEncoded)
 	var resBody Resp
-	if err := client.Invoke(context, req, &resBody, http.StatusOK); err != nil {
-		return "", "", 0, err
+	if err := httpClient.Invoke(ctx, reqContext, req, &resBody, http.StatusOK); err != nil {
+		return nil, err
 	}
-	return resBody.AccessToken, resBody.RefreshToken, resBody.ExpiresIn, nil
+	return &resBody, nil
 }
 
 // registerDynamicClient method gets the Client ID and Client Secret using the given Dynamic client registration request.
-func (m *PasswordRefreshTokenGrantManager) registerDynamicClient(reqBody *DynamicClientRegReq) error {
+func (m *PasswordRefreshTokenGrantManager) registerDynamicClient(ctx context.Context, reqBody *DynamicClientRegReq) error {
 	bodyReader, err := client.BodyReader(reqBody)
 	if err != nil {
 		return errors.Wrapf(err, ErrMsgUnableToParseRequestBody, DynamicClientRegMsg)
@@ -303,7 +347,7 @@ func (m *PasswordRefreshTokenGrantManager) registerDynamicClient(reqBody *Dynami
 	if err != nil {
 		return errors.Wrap(err, "cannot construct, token endpoint")
 	}
-	req, err := client.CreateHTTPRequest(http.MethodPost, dynamicClientRegistrationEndpoint, bodyReader)
+	req, err := client.CreateHTTPRequest(ctx, http.MethodPost, dynamicClientRegistrationEndpoint, bodyReader)
 	if err != nil {
 		return errors.Wrapf(err, ErrMsgUnableToCreateRequestBody, DynamicClientRegMsg)
 	}
@@ -311,7 +355,7 @@ func (m *PasswordRefreshTokenGrantManager) registerDynamicClient(reqBody *Dynami
 	req.SetHeader(client.HTTPContentType, client.ContentTypeApplicationJSON)
 
 	var resBody DynamicClientRegResBody
-	if err := client.Invoke(DynamicClientRegMsg, req, &resBody, http.StatusOK); err != nil {
+	if err := m.HTTPClient.Invoke(ctx, DynamicClientRegMsg, req, &resBody, http.StatusOK); err != nil {
 		return err
 	}
 	m.clientID = resBody.ClientID
@@ -19,6 +19,7 @@
 package token
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -26,7 +27,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/floydspace/terraform-provider-wso2apim/client"
 	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -48,6 +51,7 @@ func init() {
 		UserName:              "admin",
 		Password:              "admin",
 		TokenEndpoint:         tokenEndpoint,
+		HTTPClient:            client.Default(),
 		token: &token{
 			accessToken:  dummyToken,
 			refreshToken: refreshToken,
@@ -59,13 +63,15 @@ func init() {
 }
 
 func TestIsExpired(t *testing.T) {
-	t.Run("not expired", testIsExpired(time.Now().Add(10*time.Second), false))
-	t.Run("expired", testIsExpired(time.Now().Add((-10)*time.Second), true))
+	t.Run("not expired", testIsExpired(time.Now().Add(10*time.Second), 0, false))
+	t.Run("expired", testIsExpired(time.Now().Add((-10)*time.Second), 0, true))
+	t.Run("expires within skew", testIsExpired(time.Now().Add(10*time.Second), DefaultRefreshSkew, true))
+	t.Run("expires after skew", testIsExpired(time.Now().Add(60*time.Second), DefaultRefreshSkew, false))
 }
 
-func testIsExpired(time time.Time, expectedVal bool) func(t *testing.T) {
+func testIsExpired(time time.Time, skew time.Duration, expectedVal bool) func(t *testing.T) {
 	return func(t *testing.T) {
-		expired := isExpired(time)
+		expired := isExpired(time, skew)
 		if expired != expectedVal {
 			t.Errorf(ErrMsgTestIncorrectResult, expectedVal, expired)
 		}
@@ -89,7 +95,7 @@ func testDynamicClientRegSuccessFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPost, dynamicClientEndpoint+dynamicClientContext, responder)
 
-		err = tmTest.registerDynamicClient(defaultClientRegBody())
+		err = tmTest.registerDynamicClient(context.Background(), defaultClientRegBody())
 		if err != nil {
 			t.Error(err)
 		}
@@ -109,7 +115,7 @@ func testDynamicClientRegFailFunc() func(t *testing.T) {
 		}
 		httpmock.RegisterResponder(http.MethodPost, dynamicClientEndpoint+dynamicClientContext, responder)
 
-		err = tmTest.registerDynamicClient(defaultClientRegBody())
+		err = tmTest.registerDynamicClient(context.Background(), defaultClientRegBody())
 		if err == nil {
 			t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusMethodNotAllowed))
 		}
@@ -132,7 +138,7 @@ func testGenTokenFailFunc() func(t *testing.T) {
 		httpmock.RegisterResponder(http.MethodPost, tokenEndpoint+Context, responder)
 
 		data := tmTest.createAccessTokenReq([]string{"scope:test"})
-		_, _, _, err = tmTest.generateToken(data, GenerateAccessToken)
+		_, _, _, err = tmTest.generateToken(context.Background(), data, GenerateAccessToken)
 		if err == nil {
 			t.Error("Expecting an error with code: " + strconv.Itoa(http.StatusMethodNotAllowed))
 		}
@@ -154,7 +160,7 @@ func testGenTokenSuccessFunc() func(t *testing.T) {
 		httpmock.RegisterResponder(http.MethodPost, tokenEndpoint+Context, responder)
 
 		data := tmTest.createAccessTokenReq([]string{"scope:test"})
-		aT, rT, ex, err := tmTest.generateToken(data, GenerateAccessToken)
+		aT, rT, ex, err := tmTest.generateToken(context.Background(), data, GenerateAccessToken)
 		if err != nil {
 			t.Error(err)
 		}
@@ -189,7 +195,7 @@ func TestToken(t *testing.T) {
 }
 
 func testTokenSuccessFunc(t *testing.T) {
-	aT, err := tmTest.Token()
+	aT, err := tmTest.Token(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -215,6 +221,7 @@ func testTokenRefreshFunc(t *testing.T) {
 		UserName:              "admin",
 		Password:              "admin",
 		TokenEndpoint:         tokenEndpoint,
+		HTTPClient:            client.Default(),
 		token: &token{
 			accessToken:  dummyToken,
 			refreshToken: refreshToken,
@@ -223,7 +230,7 @@ func testTokenRefreshFunc(t *testing.T) {
 		}}
 	httpmock.RegisterResponder(http.MethodPost, tokenEndpoint+Context, responder)
 
-	aT, err := tm.Token()
+	aT, err := tm.Token(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -231,3 +238,175 @@ func testTokenRefreshFunc(t *testing.T) {
 		t.Errorf(ErrMsgTestIncorrectResult, "newToken", aT)
 	}
 }
+
+func TestClientCredentialsGrantManager(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	calls := 0
+	httpmock.RegisterResponder(http.MethodPost, tokenEndpoint+Context, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if id, secret, _ := req.BasicAuth(); id != "ci-client" || secret != "ci-secret" {
+			return httpmock.NewStringResponse(http.StatusUnauthorized, ""), nil
+		}
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+		if g := req.PostForm.Get(GrantType); g != GrantClientCredentials {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, Resp{
+			AccessToken: "token" + strconv.Itoa(calls),
+			ExpiresIn:   expiresIn,
+		})
+	})
+
+	tm := &ClientCredentialsGrantManager{
+		TokenEndpoint: tokenEndpoint,
+		ClientID:      "ci-client",
+		ClientSecret:  "ci-secret",
+		HTTPClient:    client.Default(),
+	}
+	if err := tm.Init(context.Background(), []string{scope}); err != nil {
+		t.Fatal(err)
+	}
+	aT, err := tm.Token(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if aT != "token1" {
+		t.Errorf(ErrMsgTestIncorrectResult, "token1", aT)
+	}
+
+	// Force fully expire the current token
+	tm.token.expiresIn = time.Now().Add(-20 * time.Second)
+	aT, err = tm.Token(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if aT != "token2" {
+		t.Errorf(ErrMsgTestIncorrectResult, "token2", aT)
+	}
+	if calls != 2 {
+		t.Errorf(ErrMsgTestIncorrectResult, 2, calls)
+	}
+}
+
+func TestTokenFallbackToPasswordGrant(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, tokenEndpoint+Context, func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+		if req.PostForm.Get(GrantType) == GrantRefreshToken {
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"error":"invalid_grant"}`), nil
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, Resp{
+			AccessToken:  "passwordToken",
+			RefreshToken: "passwordRefreshToken",
+			ExpiresIn:    expiresIn,
+		})
+	})
+	tm := &PasswordRefreshTokenGrantManager{
+		UserName:      "admin",
+		Password:      "admin",
+		TokenEndpoint: tokenEndpoint,
+		HTTPClient:    client.Default(),
+		RefreshSkew:   DefaultRefreshSkew,
+		scopes:        []string{scope},
+		token: &token{
+			accessToken:  dummyToken,
+			refreshToken: "revokedRefreshToken",
+			// Expires within the refresh skew
+			expiresIn: time.Now().Add(10 * time.Second),
+		}}
+
+	aT, err := tm.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aT != "passwordToken" {
+		t.Errorf(ErrMsgTestIncorrectResult, "passwordToken", aT)
+	}
+	if tm.token.refreshToken != "passwordRefreshToken" {
+		t.Errorf(ErrMsgTestIncorrectResult, "passwordRefreshToken", tm.token.refreshToken)
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 2 {
+		t.Errorf(ErrMsgTestIncorrectResult, 2, calls)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, Resp{
+		AccessToken:  "newToken",
+		RefreshToken: "newRefreshToken",
+		ExpiresIn:    expiresIn,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodPost, tokenEndpoint+Context, responder)
+	tm := &PasswordRefreshTokenGrantManager{
+		TokenEndpoint: tokenEndpoint,
+		HTTPClient:    client.Default(),
+		token: &token{
+			accessToken:  dummyToken,
+			refreshToken: refreshToken,
+			expiresIn:    time.Now().Add(time.Hour),
+		}}
+
+	// The rejected token has already been replaced, so no new token is generated.
+	aT, err := tm.Refresh(context.Background(), "replacedToken")
+	if err != nil {
+		t.Error(err)
+	}
+	if aT != dummyToken {
+		t.Errorf(ErrMsgTestIncorrectResult, dummyToken, aT)
+	}
+	aT, err = tm.Refresh(context.Background(), dummyToken)
+	if err != nil {
+		t.Error(err)
+	}
+	if aT != "newToken" {
+		t.Errorf(ErrMsgTestIncorrectResult, "newToken", aT)
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, 1, calls)
+	}
+}
+
+func TestInitError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	responder, err := httpmock.NewJsonResponder(http.StatusUnauthorized, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	httpmock.RegisterResponder(http.MethodPost, dynamicClientEndpoint+dynamicClientContext, responder)
+	tm := &PasswordRefreshTokenGrantManager{
+		DynamicClientEndpoint:            dynamicClientEndpoint,
+		DynamicClientRegistrationContext: dynamicClientContext,
+		UserName:                         "admin",
+		Password:                         "wrong",
+		TokenEndpoint:                    tokenEndpoint,
+		HTTPClient:                       client.Default(),
+	}
+	err = tm.Init(context.Background(), []string{scope})
+	var invokeErr *client.InvokeError
+	if !errors.As(err, &invokeErr) || invokeErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf(ErrMsgTestIncorrectResult, http.StatusUnauthorized, err)
+	}
+	// Subsequent calls return the error of the first one without calling the server again.
+	if err2 := tm.Init(context.Background(), []string{scope}); err2 != err {
+		t.Errorf(ErrMsgTestIncorrectResult, err, err2)
+	}
+	if calls := httpmock.GetTotalCallCount(); calls != 1 {
+		t.Errorf(ErrMsgTestIncorrectResult, 1, calls)
+	}
+
+	if err = (&ClientCredentialsGrantManager{}).Init(context.Background(), nil); err == nil {
+		t.Error("Expecting an error as no scope is given")
+	}
+}
@@ -11,7 +11,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource = &apiDataSource{}
+	_ datasource.DataSource              = &apiDataSource{}
+	_ datasource.DataSourceWithConfigure = &apiDataSource{}
 )
 
 // NewApiDataSource is a helper function to simplify the provider implementation.
@@ -21,22 +22,42 @@ func NewApiDataSource() datasource.DataSource {
 
 // apiDataSource is the data source implementation.
 type apiDataSource struct {
+	client *apim.Client
 }
 
 // apiDataSourceModel maps the data source schema data.
 type apiDataSourceModel struct {
-	ID              types.String                    `tfsdk:"id"`
-	Name            types.String                    `tfsdk:"name"`
-	Description     types.String                    `tfsdk:"description"`
-	Context         types.String                    `tfsdk:"context"`
-	Version         types.String                    `tfsdk:"version"`
-	Provider        types.String                    `tfsdk:"api_provider"`
-	Type            types.String                    `tfsdk:"type"`
-	LifeCycleStatus types.String                    `tfsdk:"lifecycle_status"`
-	HasThumbnail    types.Bool                      `tfsdk:"has_thumbnail"`
-	Policies        []string                        `tfsdk:"policies"`
-	EndpointConfig  *apiEndpointConfigResourceModel `tfsdk:"endpoint_config"`
-	Operations      []apiOperationResourceModel     `tfsdk:"operations"`
+	ID              types.String                      `tfsdk:"id"`
+	Name            types.String                      `tfsdk:"name"`
+	Description     types.String                      `tfsdk:"description"`
+	Context         types.String                      `tfsdk:"context"`
+	Version         types.String                      `tfsdk:"version"`
+	Provider        types.String                      `tfsdk:"api_provider"`
+	Type            types.String                      `tfsdk:"type"`
+	LifeCycleStatus types.String                      `tfsdk:"lifecycle_status"`
+	HasThumbnail    types.Bool                        `tfsdk:"has_thumbnail"`
+	Policies        []string                          `tfsdk:"policies"`
+	EndpointConfig  *apiEndpointConfigDataSourceModel `tfsdk:"endpoint_config"`
+	Operations      []apiOperationResourceModel       `tfsdk:"operations"`
+}
+
+type apiEndpointConfigDataSourceModel struct {
+	EndpointType        types.String                `tfsdk:"endpoint_type"`
+	SandboxEndpoints    *apiEndpointDataSourceModel `tfsdk:"sandbox_endpoints"`
+	ProductionEndpoints *apiEndpointDataSourceModel `tfsdk:"production_endpoints"`
+}
+
+type apiEndpointDataSourceModel struct {
+	URL types.String `tfsdk:"url"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *apiDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*wso2apimProviderData).Client
 }
 
 // Metadata returns the data source type name.
@@ -137,6 +158,28 @@ func (d *apiDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 							Description: "Operation verb.",
 							Computed:    true,
 						},
+						"auth_type": schema.StringAttribute{
+							Description: "Security of the operation.",
+							Computed:    true,
+						},
+						"throttling_policy": schema.StringAttribute{
+							Description: "Throttling policy of the operation.",
+							Computed:    true,
+						},
+						"scopes": schema.ListAttribute{
+							Description: "Scopes required to invoke the operation.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"operation_policies": schema.SingleNestedAttribute{
+							Description: "Operation policies applied to the request, response and fault flows of the operation.",
+							Computed:    true,
+							Attributes: map[string]schema.Attribute{
+								"request":  operationPolicyListDataSourceAttribute("Policies applied to the request flow."),
+								"response": operationPolicyListDataSourceAttribute("Policies applied to the response flow."),
+								"fault":    operationPolicyListDataSourceAttribute("Policies applied to the fault flow."),
+							},
+						},
 					},
 				},
 			},
@@ -153,7 +196,7 @@ func (d *apiDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 
-	api, err := apim.GetAPI(state.ID.ValueString())
+	api, err := d.client.GetAPI(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading WSO2 API Manager Api",
@@ -173,32 +216,24 @@ func (d *apiDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 	state.LifeCycleStatus = types.StringValue(api.LifeCycleStatus)
 	state.HasThumbnail = types.BoolValue(api.HasThumbnail)
 	state.Policies = api.Policies
-	var stateEndpointConfig *apiEndpointConfigResourceModel
+	var stateEndpointConfig *apiEndpointConfigDataSourceModel
 	if api.EndpointConfig != nil {
-		var stateSandboxEndpoints *apiEndpointAdvancedConfigResourceModel
+		var stateSandboxEndpoints *apiEndpointDataSourceModel
 		if api.EndpointConfig.SandboxEndpoints != nil {
-			stateSandboxEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.SandboxEndpoints.URL)}
+			stateSandboxEndpoints = &apiEndpointDataSourceModel{URL: types.StringValue(api.EndpointConfig.SandboxEndpoints.URL)}
 		}
-		var stateProductionEndpoints *apiEndpointAdvancedConfigResourceModel
+		var stateProductionEndpoints *apiEndpointDataSourceModel
 		if api.EndpointConfig.ProductionEndpoints != nil {
-			stateProductionEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.ProductionEndpoints.URL)}
+			stateProductionEndpoints = &apiEndpointDataSourceModel{URL: types.StringValue(api.EndpointConfig.ProductionEndpoints.URL)}
 		}
-		stateEndpointConfig = &apiEndpointConfigResourceModel{
+		stateEndpointConfig = &apiEndpointConfigDataSourceModel{
 			EndpointType:        types.StringValue(api.EndpointConfig.EndpointType),
 			SandboxEndpoints:    stateSandboxEndpoints,
 			ProductionEndpoints: stateProductionEndpoints,
 		}
 	}
 	state.EndpointConfig = stateEndpointConfig
-	var operations []apiOperationResourceModel
-	for _, operation := range api.Operations {
-		operations = append(operations, apiOperationResourceModel{
-			// ID:     types.StringValue(operation.ID),
-			Target: types.StringValue(operation.Target),
-			Verb:   types.StringValue(operation.Verb),
-		})
-	}
-	state.Operations = operations
+	state.Operations = apiOperationsState(nil, api.Operations)
 
 	// Set state
 	diags = resp.State.Set(ctx, &state)
@@ -207,3 +242,28 @@ func (d *apiDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 }
+
+// operationPolicyListDataSourceAttribute returns the schema of the operation policies applied to a flow of an operation.
+func operationPolicyListDataSourceAttribute(description string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: description,
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Description: "Name of the operation policy.",
+					Computed:    true,
+				},
+				"version": schema.StringAttribute{
+					Description: "Version of the operation policy.",
+					Computed:    true,
+				},
+				"parameters": schema.MapAttribute{
+					Description: "Parameters of the operation policy.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,431 @@
+package wso2apim
+
+import (
+	"context"
+	"time"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &apiDeploymentResource{}
+	_ resource.ResourceWithImportState = &apiDeploymentResource{}
+	_ resource.ResourceWithConfigure   = &apiDeploymentResource{}
+)
+
+// NewApiDeploymentResource is a helper function to simplify the provider implementation.
+func NewApiDeploymentResource() resource.Resource {
+	return &apiDeploymentResource{}
+}
+
+// apiDeploymentResource is the resource implementation.
+type apiDeploymentResource struct {
+	client *apim.Client
+}
+
+// apiDeploymentResourceModel maps the resource schema data.
+type apiDeploymentResourceModel struct {
+	ID           types.String                            `tfsdk:"id"`
+	ApiID        types.String                            `tfsdk:"api_id"`
+	RevisionID   types.String                            `tfsdk:"revision_id"`
+	Environments []apiDeploymentEnvironmentResourceModel `tfsdk:"environments"`
+	Timeout      types.Int64                             `tfsdk:"timeout"`
+	LastUpdated  types.String                            `tfsdk:"last_updated"`
+}
+
+type apiDeploymentEnvironmentResourceModel struct {
+	Name               types.String `tfsdk:"name"`
+	Vhost              types.String `tfsdk:"vhost"`
+	DisplayOnDevportal types.Bool   `tfsdk:"display_on_devportal"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *apiDeploymentResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
+// Metadata returns the resource type name.
+func (r *apiDeploymentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_deployment"
+}
+
+// Schema defines the schema for the resource.
+func (r *apiDeploymentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the gateway deployments of a WSO2 API Manager Api, deploying a revision of the api to gateway environments. " +
+			"On WSO2 API Manager 3.2 the gateway environments of the api itself are set instead.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Deployment ID, the API ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"api_id": schema.StringAttribute{
+				Description: "API ID.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"revision_id": schema.StringAttribute{
+				Description: "ID of the revision deployed, replacing the revision previously deployed to the environments.",
+				Required:    true,
+			},
+			"environments": schema.ListNestedAttribute{
+				Description: "Gateway environments the revision is deployed to. The deployments of the api to other environments are not managed by the resource.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the gateway environment.",
+							Required:    true,
+						},
+						"vhost": schema.StringAttribute{
+							Description: "Virtual host of the gateway environment. Defaults to `localhost`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString("localhost"),
+						},
+						"display_on_devportal": schema.BoolAttribute{
+							Description: "Whether the gateway URLs of the environment are displayed on the Developer Portal. Defaults to `true`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(true),
+						},
+					},
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				Description: "Time to wait for the gateways to report the revision as deployed to all the environments, in seconds. Defaults to `300`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(300),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Last updated timestamp.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Create a new resource
+func (r *apiDeploymentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan apiDeploymentResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.deploy(ctx, &plan, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Map response body to schema and populate Computed attribute values
+	plan.ID = plan.ApiID
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r *apiDeploymentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state apiDeploymentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get refreshed deployments from WSO2 API Manager
+	deployments, err := r.deployments(ctx, state.ApiID.ValueString(), state.RevisionID.ValueString())
+	revisionID, environments := deploymentEnvironmentsState(state.RevisionID, state.Environments, deployments, r.client.SupportsRevisions())
+	if apim.IsNotFound(err) || (err == nil && len(environments) == 0) {
+		// The api or its deployments were deleted outside of Terraform, let Terraform plan to re-create them.
+		tflog.Warn(ctx, "WSO2 API Manager Api deployments not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading WSO2 API Manager Api deployments",
+			"Could not read deployments of api ID "+state.ApiID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state.RevisionID, state.Environments = revisionID, environments
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *apiDeploymentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan apiDeploymentResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state apiDeploymentResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.deploy(ctx, &plan, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update resource state with updated items and timestamp
+	plan.ID = plan.ApiID
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *apiDeploymentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state apiDeploymentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Undeploy existing deployments
+	var err error
+	if r.client.SupportsRevisions() {
+		err = r.undeploy(ctx, state.ApiID.ValueString(), state.Environments)
+	} else {
+		err = r.client.UpdateGatewayEnvironments(ctx, state.ApiID.ValueString(), nil)
+	}
+	if err != nil && !apim.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting WSO2 API Manager Api deployments",
+			"Could not undeploy api, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *apiDeploymentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("api_id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("timeout"), 300)...)
+}
+
+// deploy deploys the revision of the given plan to its environments and waits for the deployments to succeed.
+// The environments of the given prior state which are not in the plan are undeployed,
+// and the ones already deployed with the same revision and settings are left as they are.
+func (r *apiDeploymentResource) deploy(ctx context.Context, plan, state *apiDeploymentResourceModel, diags *diag.Diagnostics) {
+	apiID := plan.ApiID.ValueString()
+	revisionID := plan.RevisionID.ValueString()
+
+	if !r.client.SupportsRevisions() {
+		environments := make([]string, 0, len(plan.Environments))
+		for _, env := range plan.Environments {
+			environments = append(environments, env.Name.ValueString())
+		}
+		if err := r.client.UpdateGatewayEnvironments(ctx, apiID, environments); err != nil {
+			diags.AddError(
+				"Error deploying api",
+				"Could not set gateway environments of api, unexpected error: "+err.Error(),
+			)
+		}
+		return
+	}
+
+	deployed := map[string]apiDeploymentEnvironmentResourceModel{}
+	var removed []apiDeploymentEnvironmentResourceModel
+	if state != nil {
+		planned := map[string]bool{}
+		for _, env := range plan.Environments {
+			planned[env.Name.ValueString()] = true
+		}
+		for _, env := range state.Environments {
+			if !planned[env.Name.ValueString()] {
+				removed = append(removed, env)
+			} else if state.RevisionID.Equal(plan.RevisionID) {
+				deployed[env.Name.ValueString()] = env
+			}
+		}
+	}
+	if len(removed) > 0 {
+		if err := r.undeploy(ctx, apiID, removed); err != nil {
+			diags.AddError(
+				"Error deploying api",
+				"Could not undeploy api from removed gateway environments, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	var deployments []apim.APIRevisionDeployment
+	environments := make([]string, 0, len(plan.Environments))
+	for _, env := range plan.Environments {
+		environments = append(environments, env.Name.ValueString())
+		if prior, ok := deployed[env.Name.ValueString()]; ok && prior.Vhost.Equal(env.Vhost) && prior.DisplayOnDevportal.Equal(env.DisplayOnDevportal) {
+			continue
+		}
+		deployments = append(deployments, apim.APIRevisionDeployment{
+			Name:               env.Name.ValueString(),
+			Vhost:              env.Vhost.ValueString(),
+			DisplayOnDevportal: env.DisplayOnDevportal.ValueBool(),
+		})
+	}
+	if len(deployments) > 0 {
+		if err := r.client.DeployRevision(ctx, apiID, revisionID, deployments); err != nil {
+			diags.AddError(
+				"Error deploying api",
+				"Could not deploy api revision "+revisionID+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	timeout := time.Duration(plan.Timeout.ValueInt64()) * time.Second
+	if err := r.client.WaitForDeployments(ctx, apiID, revisionID, environments, timeout); err != nil {
+		diags.AddError(
+			"Error deploying api",
+			"Could not deploy api revision "+revisionID+", unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// deployments returns the revision deployments of the given api and any error encountered.
+// On API-M versions without revisions the gateway environments of the api are returned as deployments of the given revision.
+func (r *apiDeploymentResource) deployments(ctx context.Context, apiID, revisionID string) ([]apim.APIRevisionDeployment, error) {
+	if r.client.SupportsRevisions() {
+		return r.client.GetDeployments(ctx, apiID)
+	}
+	environments, err := r.client.GetGatewayEnvironments(ctx, apiID)
+	var deployments []apim.APIRevisionDeployment
+	for _, env := range environments {
+		deployments = append(deployments, apim.APIRevisionDeployment{RevisionUUID: revisionID, Name: env})
+	}
+	return deployments, err
+}
+
+// undeploy undeploys the revisions deployed to the given environments of the given api.
+func (r *apiDeploymentResource) undeploy(ctx context.Context, apiID string, environments []apiDeploymentEnvironmentResourceModel) error {
+	names := map[string]bool{}
+	for _, env := range environments {
+		names[env.Name.ValueString()] = true
+	}
+	deployments, err := r.client.GetDeployments(ctx, apiID)
+	if err != nil {
+		return err
+	}
+	byRevision := map[string][]apim.APIRevisionDeployment{}
+	for _, d := range deployments {
+		if names[d.Name] {
+			byRevision[d.RevisionUUID] = append(byRevision[d.RevisionUUID], d)
+		}
+	}
+	for revisionID, revisionDeployments := range byRevision {
+		if err := r.client.UndeployRevision(ctx, apiID, revisionID, revisionDeployments); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deploymentEnvironmentsState returns the revision and the environments of the given deployments read from WSO2 API Manager,
+// in the order of the prior state.
+// Only the deployments to the environments of the prior state are considered, as the other environments of the api
+// may be managed elsewhere, unless the prior state has no environments, e.g. on import.
+// The revision is the prior revision unless one of these environments has another revision deployed.
+// The vhost and display settings are kept from the prior state on API-M versions without revisions.
+func deploymentEnvironmentsState(priorRevision types.String, prior []apiDeploymentEnvironmentResourceModel, deployments []apim.APIRevisionDeployment, revisions bool) (types.String, []apiDeploymentEnvironmentResourceModel) {
+	managed := map[string]bool{}
+	for _, env := range prior {
+		managed[env.Name.ValueString()] = true
+	}
+	byName := map[string]apim.APIRevisionDeployment{}
+	for _, d := range deployments {
+		if len(prior) == 0 || managed[d.Name] {
+			byName[d.Name] = d
+		}
+	}
+	revision := priorRevision
+	environments := make([]apiDeploymentEnvironmentResourceModel, 0, len(deployments))
+	add := func(d apim.APIRevisionDeployment, priorEnv *apiDeploymentEnvironmentResourceModel) {
+		if d.RevisionUUID != "" && d.RevisionUUID != priorRevision.ValueString() {
+			revision = types.StringValue(d.RevisionUUID)
+		}
+		env := apiDeploymentEnvironmentResourceModel{
+			Name:               types.StringValue(d.Name),
+			Vhost:              types.StringValue(d.Vhost),
+			DisplayOnDevportal: types.BoolValue(d.DisplayOnDevportal),
+		}
+		if !revisions {
+			env.Vhost, env.DisplayOnDevportal = types.StringValue("localhost"), types.BoolValue(true)
+			if priorEnv != nil {
+				env.Vhost, env.DisplayOnDevportal = priorEnv.Vhost, priorEnv.DisplayOnDevportal
+			}
+		}
+		environments = append(environments, env)
+		delete(byName, d.Name)
+	}
+	for i := range prior {
+		if d, ok := byName[prior[i].Name.ValueString()]; ok {
+			add(d, &prior[i])
+		}
+	}
+	for _, d := range deployments {
+		if _, ok := byName[d.Name]; ok {
+			add(d, nil)
+		}
+	}
+	return revision, environments
+}
@@ -0,0 +1,143 @@
+package wso2apim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccApiDeploymentResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "deployed-api"
+	context  = "/deployed"
+	version  = "v1"
+	policies = ["Unlimited"]
+}
+
+resource "wso2apim_api_revision" "test" {
+	api_id      = wso2apim_api.test.id
+	description = "first"
+}
+
+resource "wso2apim_api_deployment" "test" {
+	api_id      = wso2apim_api.test.id
+	revision_id = wso2apim_api_revision.test.id
+
+	environments = [{
+		name = "Default"
+	}]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("wso2apim_api_revision.test", "id"),
+					resource.TestCheckResourceAttrPair("wso2apim_api_deployment.test", "revision_id", "wso2apim_api_revision.test", "id"),
+					resource.TestCheckResourceAttr("wso2apim_api_deployment.test", "environments.0.vhost", "localhost"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "wso2apim_api_deployment.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "deployed-api"
+	context  = "/deployed"
+	version  = "v1"
+	policies = ["Unlimited"]
+}
+
+resource "wso2apim_api_revision" "test" {
+	api_id      = wso2apim_api.test.id
+	description = "second"
+
+	lifecycle {
+		create_before_destroy = true
+	}
+}
+
+resource "wso2apim_api_deployment" "test" {
+	api_id      = wso2apim_api.test.id
+	revision_id = wso2apim_api_revision.test.id
+
+	environments = [{
+		name = "Default"
+	}]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api_revision.test", "description", "second"),
+					resource.TestCheckResourceAttrPair("wso2apim_api_deployment.test", "revision_id", "wso2apim_api_revision.test", "id"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestDeploymentEnvironmentsState(t *testing.T) {
+	env := func(name string) apiDeploymentEnvironmentResourceModel {
+		return apiDeploymentEnvironmentResourceModel{
+			Name:               types.StringValue(name),
+			Vhost:              types.StringValue("localhost"),
+			DisplayOnDevportal: types.BoolValue(true),
+		}
+	}
+	deployments := []apim.APIRevisionDeployment{
+		{Name: "Internal", RevisionUUID: "r3", Vhost: "localhost", DisplayOnDevportal: true},
+		{Name: "Default", RevisionUUID: "r1", Vhost: "localhost", DisplayOnDevportal: true},
+	}
+	cases := []struct {
+		name             string
+		priorRevision    types.String
+		prior            []apiDeploymentEnvironmentResourceModel
+		wantRevision     types.String
+		wantEnvironments []apiDeploymentEnvironmentResourceModel
+	}{
+		{
+			name:             "environments not in the prior state are ignored",
+			priorRevision:    types.StringValue("r1"),
+			prior:            []apiDeploymentEnvironmentResourceModel{env("Default")},
+			wantRevision:     types.StringValue("r1"),
+			wantEnvironments: []apiDeploymentEnvironmentResourceModel{env("Default")},
+		},
+		{
+			name:             "removed environment",
+			priorRevision:    types.StringValue("r1"),
+			prior:            []apiDeploymentEnvironmentResourceModel{env("Default"), env("External")},
+			wantRevision:     types.StringValue("r1"),
+			wantEnvironments: []apiDeploymentEnvironmentResourceModel{env("Default")},
+		},
+		{
+			name:             "import",
+			priorRevision:    types.StringNull(),
+			prior:            nil,
+			wantRevision:     types.StringValue("r1"),
+			wantEnvironments: []apiDeploymentEnvironmentResourceModel{env("Internal"), env("Default")},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			revision, environments := deploymentEnvironmentsState(c.priorRevision, c.prior, deployments, true)
+			if !revision.Equal(c.wantRevision) {
+				t.Errorf("expected %+v, got %+v", c.wantRevision, revision)
+			}
+			if !reflect.DeepEqual(environments, c.wantEnvironments) {
+				t.Errorf("expected %+v, got %+v", c.wantEnvironments, environments)
+			}
+		})
+	}
+}
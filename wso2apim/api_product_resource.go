@@ -0,0 +1,566 @@
+package wso2apim
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &apiProductResource{}
+	_ resource.ResourceWithImportState = &apiProductResource{}
+	_ resource.ResourceWithConfigure   = &apiProductResource{}
+)
+
+// NewApiProductResource is a helper function to simplify the provider implementation.
+func NewApiProductResource() resource.Resource {
+	return &apiProductResource{}
+}
+
+// apiProductResource is the resource implementation.
+type apiProductResource struct {
+	client *apim.Client
+	config *wso2apimProviderModel
+}
+
+// apiProductResourceModel maps the resource schema data.
+type apiProductResourceModel struct {
+	ID                     types.String                            `tfsdk:"id"`
+	Name                   types.String                            `tfsdk:"name"`
+	Description            types.String                            `tfsdk:"description"`
+	Context                types.String                            `tfsdk:"context"`
+	Provider               types.String                            `tfsdk:"api_provider"`
+	APIs                   []apiProductAPIResourceModel            `tfsdk:"apis"`
+	Policies               []string                                `tfsdk:"policies"`
+	Visibility             types.String                            `tfsdk:"visibility"`
+	VisibleRoles           []string                                `tfsdk:"visible_roles"`
+	LifecycleState         types.String                            `tfsdk:"lifecycle_state"`
+	DeploymentEnvironments []apiDeploymentEnvironmentResourceModel `tfsdk:"deployment_environments"`
+	DeploymentTimeout      types.Int64                             `tfsdk:"deployment_timeout"`
+	PruneRevisions         types.Bool                              `tfsdk:"prune_revisions"`
+	RevisionID             types.String                            `tfsdk:"revision_id"`
+	LastUpdated            types.String                            `tfsdk:"last_updated"`
+}
+
+type apiProductAPIResourceModel struct {
+	ApiID      types.String                       `tfsdk:"api_id"`
+	Operations []apiProductOperationResourceModel `tfsdk:"operations"`
+}
+
+type apiProductOperationResourceModel struct {
+	Target types.String `tfsdk:"target"`
+	Verb   types.String `tfsdk:"verb"`
+}
+
+// Configure adds the provider configuration to the resource.
+func (r *apiProductResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*wso2apimProviderData)
+	r.client = providerData.Client
+	r.config = providerData.Config
+}
+
+// Metadata returns the resource type name.
+func (r *apiProductResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_product"
+}
+
+// Schema defines the schema for the resource.
+func (r *apiProductResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a WSO2 API Manager Api Product, bundling operations of several apis into one subscribable product.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Api Product ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the api product.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the api product.",
+				Optional:    true,
+			},
+			"context": schema.StringAttribute{
+				Description: "Context of the api product.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"api_provider": schema.StringAttribute{
+				Description: "Provider of the api product.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"apis": schema.ListNestedAttribute{
+				Description: "Apis bundled by the api product.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"api_id": schema.StringAttribute{
+							Description: "API ID.",
+							Required:    true,
+						},
+						"operations": schema.ListNestedAttribute{
+							Description: "Operations of the api exposed by the api product.",
+							Required:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"target": schema.StringAttribute{
+										Description: "Operation target, the resource path.",
+										Required:    true,
+									},
+									"verb": schema.StringAttribute{
+										Description: "Operation verb.",
+										Required:    true,
+									},
+								},
+							},
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+							},
+						},
+					},
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"policies": schema.ListAttribute{
+				Description: "Policies of the api product.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+			},
+			"visibility": schema.StringAttribute{
+				Description: "Visibility of the api product on the Developer Portal, one of `PUBLIC`, `PRIVATE` or `RESTRICTED` to the `visible_roles`. " +
+					"Defaults to `PUBLIC`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("PUBLIC"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("PUBLIC", "PRIVATE", "RESTRICTED"),
+				},
+			},
+			"visible_roles": schema.ListAttribute{
+				Description: "Roles the api product is visible to when its visibility is `RESTRICTED`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+			},
+			"lifecycle_state": schema.StringAttribute{
+				Description: "LifeCycle state the api product is moved to, one of `CREATED`, `PROTOTYPED`, `PUBLISHED`, `BLOCKED`, `DEPRECATED` or `RETIRED`. " +
+					"Defaults to `PUBLISHED`. WSO2 API Manager 3.2 publishes api products on creation and does not support changing their lifecycle state.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(apim.LifecyclePublished),
+				Validators: []validator.String{
+					stringvalidator.OneOf(apim.LifecycleStates()...),
+				},
+			},
+			"deployment_environments": schema.ListNestedAttribute{
+				Description: "Gateway environments the api product is deployed to, its deployments to other environments are not managed by the resource. " +
+					"A new revision of the api product is created and deployed whenever the api product changes. " +
+					"On WSO2 API Manager 3.2 the gateway environments of the api product itself are set instead.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the gateway environment.",
+							Required:    true,
+						},
+						"vhost": schema.StringAttribute{
+							Description: "Virtual host of the gateway environment. Defaults to `localhost`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString("localhost"),
+						},
+						"display_on_devportal": schema.BoolAttribute{
+							Description: "Whether the gateway URLs of the environment are displayed on the Developer Portal. Defaults to `true`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(true),
+						},
+					},
+				},
+			},
+			"deployment_timeout": schema.Int64Attribute{
+				Description: "Time to wait for the gateways to report the api product as deployed to all the environments, in seconds. Defaults to `300`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(300),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"prune_revisions": schema.BoolAttribute{
+				Description: "Whether the oldest revisions of the api product which are not deployed are deleted when the api product already has the maximum of 5 revisions, " +
+					"including the revisions not created by Terraform. Otherwise a new revision cannot be created until older revisions are deleted. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"revision_id": schema.StringAttribute{
+				Description: "ID of the revision of the api product deployed to the `deployment_environments`.",
+				Computed:    true,
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Last updated timestamp.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Create a new resource
+func (r *apiProductResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan apiProductResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new api product
+	product, err := r.client.CreateAPIProduct(ctx, apiProductRequest(&plan))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating api product",
+			"Could not create api product, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to schema and populate Computed attribute values
+	plan.ID = types.StringValue(product.ID)
+	plan.RevisionID = types.StringNull()
+	r.deploy(ctx, &plan, nil, &resp.Diagnostics)
+	r.changeLifecycleState(ctx, &plan, product, &resp.Diagnostics)
+	r.apiProductState(&plan, product)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r *apiProductResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state apiProductResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get refreshed api product value from WSO2 API Manager
+	product, err := r.client.GetAPIProduct(ctx, state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		// The api product was deleted outside of Terraform, let Terraform plan to re-create it.
+		tflog.Warn(ctx, "WSO2 API Manager Api Product not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading WSO2 API Manager Api Product",
+			"Could not read api product ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	if state.DeploymentEnvironments != nil {
+		deployer := &apiDeploymentResource{client: r.client.ForAPIProducts()}
+		deployments, err := deployer.deployments(ctx, state.ID.ValueString(), state.RevisionID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api Product",
+				"Could not read deployments of api product ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		state.RevisionID, state.DeploymentEnvironments = deploymentEnvironmentsState(state.RevisionID, state.DeploymentEnvironments, deployments, r.client.SupportsRevisions())
+		if len(state.DeploymentEnvironments) == 0 {
+			state.DeploymentEnvironments = nil
+		}
+	}
+
+	// Overwrite items with refreshed state
+	r.apiProductState(&state, product)
+	state.LifecycleState = types.StringValue(product.LifecycleState())
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *apiProductResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan apiProductResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state apiProductResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update existing api product
+	product, err := r.client.UpdateAPIProduct(ctx, plan.ID.ValueString(), apiProductRequest(&plan))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating api product",
+			"Could not update api product, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update resource state with updated items and timestamp
+	r.deploy(ctx, &plan, &state, &resp.Diagnostics)
+	r.changeLifecycleState(ctx, &plan, product, &resp.Diagnostics)
+	r.apiProductState(&plan, product)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *apiProductResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state apiProductResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete existing api product
+	err := r.client.DeleteAPIProduct(ctx, state.ID.ValueString())
+	if err != nil && !apim.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting WSO2 API Manager Api Product",
+			"Could not delete api product, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *apiProductResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deployment_timeout"), 300)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("prune_revisions"), false)...)
+}
+
+// deploy deploys the api product of the given plan to its deployment environments, and sets the revision_id of the plan.
+// A new revision is created if the api product differs from the given prior state, if any,
+// otherwise the prior revision is deployed to the environments added to the plan.
+func (r *apiProductResource) deploy(ctx context.Context, plan, state *apiProductResourceModel, diags *diag.Diagnostics) {
+	var prior *apiDeploymentResourceModel
+	if state != nil {
+		plan.RevisionID = state.RevisionID
+		prior = &apiDeploymentResourceModel{ApiID: state.ID, RevisionID: state.RevisionID, Environments: state.DeploymentEnvironments}
+	}
+	if len(plan.DeploymentEnvironments) == 0 && (prior == nil || len(prior.Environments) == 0) {
+		return
+	}
+
+	products := r.client.ForAPIProducts()
+	changed := state == nil || plan.RevisionID.IsNull() || !reflect.DeepEqual(apiProductRequest(plan), apiProductRequest(state))
+	if len(plan.DeploymentEnvironments) > 0 && changed {
+		revision := &apim.APIRevision{ID: apim.CurrentRevision}
+		if products.SupportsRevisions() {
+			var err error
+			if plan.PruneRevisions.ValueBool() {
+				var deleted []apim.APIRevision
+				deleted, err = products.PruneRevisions(ctx, plan.ID.ValueString())
+				for _, d := range deleted {
+					tflog.Info(ctx, "Deleted old WSO2 API Manager Api Product revision", map[string]any{"id": plan.ID.ValueString(), "revision_id": d.ID})
+				}
+			}
+			if err == nil {
+				revision, err = products.CreateRevision(ctx, plan.ID.ValueString(), "")
+			}
+			if err != nil {
+				diags.AddError(
+					"Error deploying api product",
+					"Could not create api product revision, unexpected error: "+err.Error(),
+				)
+				return
+			}
+		}
+		plan.RevisionID = types.StringValue(revision.ID)
+	}
+
+	deployer := &apiDeploymentResource{client: products}
+	deployer.deploy(ctx, &apiDeploymentResourceModel{
+		ApiID:        plan.ID,
+		RevisionID:   plan.RevisionID,
+		Environments: plan.DeploymentEnvironments,
+		Timeout:      plan.DeploymentTimeout,
+	}, prior, diags)
+}
+
+// changeLifecycleState moves the given api product from its current lifecycle state to the lifecycle_state of the given plan,
+// and sets the lifecycle_state of the plan to the resulting state.
+func (r *apiProductResource) changeLifecycleState(ctx context.Context, plan *apiProductResourceModel, product *apim.APIProduct, diags *diag.Diagnostics) {
+	current := product.LifecycleState()
+	target := plan.LifecycleState.ValueString()
+	if current == target {
+		return
+	}
+	if !r.client.SupportsRevisions() {
+		diags.AddAttributeError(
+			path.Root("lifecycle_state"),
+			"Unsupported api product lifecycle state",
+			"WSO2 API Manager 3.2 does not support changing the lifecycle state of api products from "+current+" to "+target+".",
+		)
+		plan.LifecycleState = types.StringValue(current)
+		return
+	}
+	lifecycle, err := r.client.ForAPIProducts().ChangeLifecycleState(ctx, product.ID, current, target, nil, 0)
+	if err != nil {
+		diags.AddError(
+			"Error changing api product lifecycle state",
+			"Could not change api product lifecycle state from "+current+" to "+target+", unexpected error: "+err.Error(),
+		)
+	}
+	if lifecycle.Pending {
+		diags.AddWarning(
+			"Api product lifecycle change pending approval",
+			"The change of the api product lifecycle state from "+lifecycle.State+" towards "+target+" is waiting for the approval of a workflow, "+
+				"the remaining lifecycle actions are applied by the next apply once it is approved.",
+		)
+	}
+	plan.LifecycleState = types.StringValue(lifecycle.State)
+}
+
+// apiProductState sets the attributes of the given model to the given api product read from WSO2 API Manager.
+func (r *apiProductResource) apiProductState(model *apiProductResourceModel, product *apim.APIProduct) {
+	productContext := product.Context
+	if !r.config.ApiContextPrefix.IsUnknown() {
+		productContext = strings.Split(product.Context, r.config.ApiContextPrefix.ValueString())[1]
+	}
+
+	model.ID = types.StringValue(product.ID)
+	model.Name = types.StringValue(product.Name)
+	if product.Description != "" || !model.Description.IsNull() {
+		model.Description = types.StringValue(product.Description)
+	}
+	model.Context = types.StringValue(productContext)
+	model.Provider = types.StringValue(product.Provider)
+	model.Visibility = types.StringValue(product.Visibility)
+	model.VisibleRoles = append([]string{}, product.VisibleRoles...)
+	model.Policies = append([]string{}, product.Policies...)
+	model.APIs = apiProductAPIsState(model.APIs, product.APIs)
+}
+
+// apiProductRequest returns the api product spec of the given model.
+func apiProductRequest(model *apiProductResourceModel) *apim.APIProduct {
+	apis := make([]apim.APIProductAPI, 0, len(model.APIs))
+	for _, api := range model.APIs {
+		operations := make([]apim.APIOperation, 0, len(api.Operations))
+		for _, operation := range api.Operations {
+			operations = append(operations, apim.APIOperation{
+				Target: operation.Target.ValueString(),
+				Verb:   operation.Verb.ValueString(),
+			})
+		}
+		apis = append(apis, apim.APIProductAPI{APIID: api.ApiID.ValueString(), Operations: operations})
+	}
+	return &apim.APIProduct{
+		Name:         model.Name.ValueString(),
+		Context:      model.Context.ValueString(),
+		Description:  model.Description.ValueString(),
+		Provider:     model.Provider.ValueString(),
+		Visibility:   model.Visibility.ValueString(),
+		VisibleRoles: append([]string{}, model.VisibleRoles...),
+		Policies:     append([]string{}, model.Policies...),
+		APIs:         apis,
+	}
+}
+
+// apiProductAPIsState returns the given apis of an api product read from WSO2 API Manager,
+// and their operations, in the order of the prior state followed by the ones not in the prior state.
+func apiProductAPIsState(prior []apiProductAPIResourceModel, apis []apim.APIProductAPI) []apiProductAPIResourceModel {
+	apiIDs := make([]string, 0, len(prior))
+	var operationKeys []string
+	for _, api := range prior {
+		apiIDs = append(apiIDs, api.ApiID.ValueString())
+		for _, operation := range api.Operations {
+			operationKeys = append(operationKeys, api.ApiID.ValueString()+" "+operation.Verb.ValueString()+" "+operation.Target.ValueString())
+		}
+	}
+
+	apis = append([]apim.APIProductAPI{}, apis...)
+	sortByPrior(apis, apiIDs, func(api apim.APIProductAPI) string { return api.APIID })
+	state := make([]apiProductAPIResourceModel, 0, len(apis))
+	for _, api := range apis {
+		operations := append([]apim.APIOperation{}, api.Operations...)
+		sortByPrior(operations, operationKeys, func(operation apim.APIOperation) string {
+			return api.APIID + " " + operation.Verb + " " + operation.Target
+		})
+		model := apiProductAPIResourceModel{ApiID: types.StringValue(api.APIID)}
+		for _, operation := range operations {
+			model.Operations = append(model.Operations, apiProductOperationResourceModel{
+				Target: types.StringValue(operation.Target),
+				Verb:   types.StringValue(operation.Verb),
+			})
+		}
+		state = append(state, model)
+	}
+	return state
+}
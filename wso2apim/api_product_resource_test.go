@@ -0,0 +1,122 @@
+package wso2apim
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccApiProductResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "items" {
+	name     = "items-api"
+	context  = "/items"
+	version  = "v1"
+	policies = ["Unlimited"]
+	operations = [{
+		target = "/items"
+		verb   = "GET"
+	}, {
+		target = "/items"
+		verb   = "POST"
+	}]
+}
+
+resource "wso2apim_api_product" "test" {
+	name     = "partner-product"
+	context  = "/partner"
+	policies = ["Unlimited"]
+
+	apis = [{
+		api_id = wso2apim_api.items.id
+		operations = [{
+			target = "/items"
+			verb   = "GET"
+		}]
+	}]
+
+	deployment_environments = [{
+		name = "Default"
+	}]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("wso2apim_api_product.test", "id"),
+					resource.TestCheckResourceAttrSet("wso2apim_api_product.test", "revision_id"),
+					resource.TestCheckResourceAttr("wso2apim_api_product.test", "visibility", "PUBLIC"),
+					resource.TestCheckResourceAttr("wso2apim_api_product.test", "lifecycle_state", "PUBLISHED"),
+					resource.TestCheckResourceAttr("wso2apim_api_product.test", "apis.0.operations.#", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "wso2apim_api_product.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "deployment_environments", "revision_id"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "items" {
+	name     = "items-api"
+	context  = "/items"
+	version  = "v1"
+	policies = ["Unlimited"]
+	operations = [{
+		target = "/items"
+		verb   = "GET"
+	}, {
+		target = "/items"
+		verb   = "POST"
+	}]
+}
+
+resource "wso2apim_api_product" "test" {
+	name        = "partner-product"
+	description = "Partner offering"
+	context     = "/partner"
+	policies    = ["Unlimited"]
+
+	apis = [{
+		api_id = wso2apim_api.items.id
+		operations = [{
+			target = "/items"
+			verb   = "GET"
+		}, {
+			target = "/items"
+			verb   = "POST"
+		}]
+	}]
+
+	deployment_environments = [{
+		name = "Default"
+	}]
+}
+
+resource "wso2apim_application" "partner" {
+	name              = "partner-app"
+	throttling_policy = "Unlimited"
+}
+
+resource "wso2apim_subscription" "partner" {
+	application_id    = wso2apim_application.partner.id
+	api_product_id    = wso2apim_api_product.test.id
+	throttling_policy = "Unlimited"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api_product.test", "description", "Partner offering"),
+					resource.TestCheckResourceAttr("wso2apim_api_product.test", "apis.0.operations.#", "2"),
+					resource.TestCheckResourceAttrPair("wso2apim_subscription.partner", "api_product_id", "wso2apim_api_product.test", "id"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
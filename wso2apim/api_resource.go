@@ -2,29 +2,47 @@ package wso2apim
 
 import (
 	"context"
+	"encoding/base64"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &apiResource{}
-	_ resource.ResourceWithImportState = &apiResource{}
-	_ resource.ResourceWithConfigure   = &apiResource{}
+	_ resource.Resource                   = &apiResource{}
+	_ resource.ResourceWithImportState    = &apiResource{}
+	_ resource.ResourceWithConfigure      = &apiResource{}
+	_ resource.ResourceWithValidateConfig = &apiResource{}
 )
 
+// asyncAPITypes are the api types whose operations are channels rather than resources.
+var asyncAPITypes = []string{"WS", "WEBSUB", "SSE", "ASYNC"}
+
 // NewApiResource is a helper function to simplify the provider implementation.
 func NewApiResource() resource.Resource {
 	return &apiResource{}
@@ -32,40 +50,84 @@ func NewApiResource() resource.Resource {
 
 // apiResource is the resource implementation.
 type apiResource struct {
+	client *apim.Client
 	config *wso2apimProviderModel
 }
 
 // apiResourceModel maps the resource schema data.
 type apiResourceModel struct {
-	ID              types.String                    `tfsdk:"id"`
-	Name            types.String                    `tfsdk:"name"`
-	Description     types.String                    `tfsdk:"description"`
-	Context         types.String                    `tfsdk:"context"`
-	Version         types.String                    `tfsdk:"version"`
-	Provider        types.String                    `tfsdk:"api_provider"`
-	Type            types.String                    `tfsdk:"type"`
-	LifeCycleStatus types.String                    `tfsdk:"lifecycle_status"`
-	HasThumbnail    types.Bool                      `tfsdk:"has_thumbnail"`
-	Policies        []string                        `tfsdk:"policies"`
-	EndpointConfig  *apiEndpointConfigResourceModel `tfsdk:"endpoint_config"`
-	Operations      []apiOperationResourceModel     `tfsdk:"operations"`
-	LastUpdated     types.String                    `tfsdk:"last_updated"`
+	ID                           types.String                                  `tfsdk:"id"`
+	Name                         types.String                                  `tfsdk:"name"`
+	Description                  types.String                                  `tfsdk:"description"`
+	Context                      types.String                                  `tfsdk:"context"`
+	Version                      types.String                                  `tfsdk:"version"`
+	SourceApiID                  types.String                                  `tfsdk:"source_api_id"`
+	IsDefaultVersion             types.Bool                                    `tfsdk:"is_default_version"`
+	Provider                     types.String                                  `tfsdk:"api_provider"`
+	Type                         types.String                                  `tfsdk:"type"`
+	LifeCycleStatus              types.String                                  `tfsdk:"lifecycle_status"`
+	LifecycleState               types.String                                  `tfsdk:"lifecycle_state"`
+	LifecycleChecklist           *apiLifecycleChecklistResourceModel           `tfsdk:"lifecycle_checklist"`
+	LifecycleApprovalTimeout     types.Int64                                   `tfsdk:"lifecycle_approval_timeout"`
+	HasThumbnail                 types.Bool                                    `tfsdk:"has_thumbnail"`
+	Policies                     []string                                      `tfsdk:"policies"`
+	Visibility                   types.String                                  `tfsdk:"visibility"`
+	VisibleRoles                 []string                                      `tfsdk:"visible_roles"`
+	VisibleTenants               []string                                      `tfsdk:"visible_tenants"`
+	AccessControl                types.String                                  `tfsdk:"access_control"`
+	AccessControlRoles           []string                                      `tfsdk:"access_control_roles"`
+	SubscriptionAvailability     types.String                                  `tfsdk:"subscription_availability"`
+	SubscriptionAvailableTenants []string                                      `tfsdk:"subscription_available_tenants"`
+	EndpointConfig               *apiEndpointConfigResourceModel               `tfsdk:"endpoint_config"`
+	EndpointSecurity             *apiEndpointSecurityResourceModel             `tfsdk:"endpoint_security"`
+	Operations                   []apiOperationResourceModel                   `tfsdk:"operations"`
+	Scopes                       []apiScopeResourceModel                       `tfsdk:"scopes"`
+	OpenAPI                      types.String                                  `tfsdk:"openapi_definition"`
+	GraphQLSchema                types.String                                  `tfsdk:"graphql_schema"`
+	GraphQLComplexity            []apiGraphQLComplexityResourceModel           `tfsdk:"graphql_complexity"`
+	WSDL                         types.String                                  `tfsdk:"wsdl_definition"`
+	AsyncAPI                     types.String                                  `tfsdk:"asyncapi_definition"`
+	WebsubSubscription           *apiWebsubSubscriptionResourceModel           `tfsdk:"websub_subscription"`
+	Security                     *apiSecurityResourceModel                     `tfsdk:"security"`
+	CorsConfiguration            *apiCorsConfigurationResourceModel            `tfsdk:"cors_configuration"`
+	AuthorizationHeader          types.String                                  `tfsdk:"authorization_header"`
+	Audiences                    []string                                      `tfsdk:"audiences"`
+	KeyManagers                  []string                                      `tfsdk:"key_managers"`
+	BusinessInformation          *apiBusinessInformationResourceModel          `tfsdk:"business_information"`
+	Tags                         []string                                      `tfsdk:"tags"`
+	Categories                   []string                                      `tfsdk:"categories"`
+	AdditionalProperties         map[string]apiAdditionalPropertyResourceModel `tfsdk:"additional_properties"`
+	LastUpdated                  types.String                                  `tfsdk:"last_updated"`
+}
+
+type apiBusinessInformationResourceModel struct {
+	BusinessOwner       types.String `tfsdk:"business_owner"`
+	BusinessOwnerEmail  types.String `tfsdk:"business_owner_email"`
+	TechnicalOwner      types.String `tfsdk:"technical_owner"`
+	TechnicalOwnerEmail types.String `tfsdk:"technical_owner_email"`
 }
 
-type apiEndpointConfigResourceModel struct {
-	EndpointType        types.String                            `tfsdk:"endpoint_type"`
-	SandboxEndpoints    *apiEndpointAdvancedConfigResourceModel `tfsdk:"sandbox_endpoints"`
-	ProductionEndpoints *apiEndpointAdvancedConfigResourceModel `tfsdk:"production_endpoints"`
+type apiAdditionalPropertyResourceModel struct {
+	Value   types.String `tfsdk:"value"`
+	Display types.Bool   `tfsdk:"display"`
 }
 
-type apiEndpointAdvancedConfigResourceModel struct {
-	URL types.String `tfsdk:"url"`
+type apiLifecycleChecklistResourceModel struct {
+	DeprecateOldVersions   types.Bool `tfsdk:"deprecate_old_versions"`
+	RequiresResubscription types.Bool `tfsdk:"requires_resubscription"`
 }
 
-type apiOperationResourceModel struct {
-	// ID     types.String `tfsdk:"id"`
-	Target types.String `tfsdk:"target"`
-	Verb   types.String `tfsdk:"verb"`
+type apiWebsubSubscriptionResourceModel struct {
+	Enabled          types.Bool   `tfsdk:"enabled"`
+	Secret           types.String `tfsdk:"secret"`
+	SigningAlgorithm types.String `tfsdk:"signing_algorithm"`
+	SignatureHeader  types.String `tfsdk:"signature_header"`
+}
+
+type apiGraphQLComplexityResourceModel struct {
+	Type            types.String `tfsdk:"type"`
+	Field           types.String `tfsdk:"field"`
+	ComplexityValue types.Int64  `tfsdk:"complexity_value"`
 }
 
 // Configure adds the provider configuration to the resource.
@@ -74,7 +136,9 @@ func (r *apiResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.config = req.ProviderData.(*wso2apimProviderModel)
+	providerData := req.ProviderData.(*wso2apimProviderData)
+	r.client = providerData.Client
+	r.config = providerData.Config
 }
 
 // Metadata returns the resource type name.
@@ -113,12 +177,30 @@ func (r *apiResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"version": schema.StringAttribute{
-				Description: "Version of the api.",
-				Required:    true,
+				Description: "Version of the api. Changing it replaces the api, " +
+					"declare a new version alongside the existing one with `source_api_id` to keep the subscriptions of the existing version.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_api_id": schema.StringAttribute{
+				Description: "ID of the api this api is created as a new version of. " +
+					"The api is created as a copy of the source api, including its definition, operations and documents, " +
+					"and the configured attributes are applied to the copy. " +
+					"The `name` and `context` must be the ones of the source api.",
+				Optional: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"is_default_version": schema.BoolAttribute{
+				Description: "Whether the api is invoked when its context is called without a version. " +
+					"Only one version of an api is the default version. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
 			"api_provider": schema.StringAttribute{
 				Description: "Provider of the api.",
 				Optional:    true,
@@ -141,9 +223,44 @@ func (r *apiResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"lifecycle_status": schema.StringAttribute{
-				Description: "LifeCycle status of the api.",
+				Description: "LifeCycle status of the api, differs from `lifecycle_state` while a lifecycle change is pending approval or has failed.",
 				Computed:    true,
 			},
+			"lifecycle_state": schema.StringAttribute{
+				Description: "LifeCycle state the api is moved to, one of `CREATED`, `PROTOTYPED`, `PUBLISHED`, `BLOCKED`, `DEPRECATED` or `RETIRED`. " +
+					"The lifecycle actions leading to it are applied in order, e.g. `Publish`, `Deprecate` and `Retire` to retire a created api. " +
+					"Defaults to `PUBLISHED`, in which case a failing lifecycle action, e.g. publishing an api without a deployed revision, " +
+					"is reported as a warning and retried by the next apply, while it is an error for a configured state.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(apim.LifecyclePublished),
+				Validators: []validator.String{
+					stringvalidator.OneOf(apim.LifecycleStates()...),
+				},
+			},
+			"lifecycle_checklist": schema.SingleNestedAttribute{
+				Description: "LifeCycle checklist items applied when the api is published.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"deprecate_old_versions": schema.BoolAttribute{
+						Description: "Deprecate the older versions of the api once it is published.",
+						Optional:    true,
+					},
+					"requires_resubscription": schema.BoolAttribute{
+						Description: "Require the applications subscribed to older versions of the api to subscribe again.",
+						Optional:    true,
+					},
+				},
+			},
+			"lifecycle_approval_timeout": schema.Int64Attribute{
+				Description: "Time to wait for the approval of a lifecycle change by a workflow, in seconds. " +
+					"A change still pending approval is reported as a warning and the remaining lifecycle actions are applied once it is approved. " +
+					"Defaults to `0`, not waiting.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 			"has_thumbnail": schema.BoolAttribute{
 				Description: "Whether the api has a thumbnail.",
 				Computed:    true,
@@ -155,40 +272,189 @@ func (r *apiResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Computed:    true,
 				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
 			},
+			"visibility": schema.StringAttribute{
+				Description: "Visibility of the api on the Developer Portal, one of `PUBLIC`, `PRIVATE` to the users of the tenant of the api " +
+					"or `RESTRICTED` to the `visible_roles`. Defaults to `PUBLIC`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("PUBLIC"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("PUBLIC", "PRIVATE", "RESTRICTED"),
+				},
+			},
+			"visible_roles": schema.ListAttribute{
+				Description: "Roles the api is visible to when its visibility is `RESTRICTED`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+			},
+			"visible_tenants": schema.ListAttribute{
+				Description: "Tenants the api is visible to, in a multi-tenant deployment.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+			},
+			"access_control": schema.StringAttribute{
+				Description: "Publisher access control of the api, `NONE` to let any publisher view and modify the api " +
+					"or `RESTRICTED` to the publishers having the `access_control_roles`. Defaults to `NONE`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("NONE"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("NONE", "RESTRICTED"),
+				},
+			},
+			"access_control_roles": schema.ListAttribute{
+				Description: "Roles of the publishers allowed to view and modify the api when its access control is `RESTRICTED`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+			},
+			"subscription_availability": schema.StringAttribute{
+				Description: "Tenants whose applications can subscribe to the api, one of `current_tenant`, `all_tenants` " +
+					"or `specific_tenants` listed in `subscription_available_tenants`. Defaults to `current_tenant`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("current_tenant"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("current_tenant", "all_tenants", "specific_tenants"),
+				},
+			},
+			"subscription_available_tenants": schema.ListAttribute{
+				Description: "Tenants whose applications can subscribe to the api when its subscription availability is `specific_tenants`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+			},
 			"endpoint_config": schema.SingleNestedAttribute{
 				Description: "Endpoint configuration of the api.",
 				Optional:    true,
 				Attributes: map[string]schema.Attribute{
 					"endpoint_type": schema.StringAttribute{
-						Description: "Endpoint type.",
-						Optional:    true,
+						Description: "Endpoint type, one of `http`, `address`, `ws` for `WS` apis with ws:// or wss:// endpoint URLs, " +
+							"`failover` falling back to the failover endpoints in order, `load_balance` balancing the requests between the endpoints of `load_balance`, " +
+							"`default` for dynamic endpoints with the endpoint URLs set to `default`, `awslambda` invoking the AWS Lambda functions of the operations, " +
+							"or `INLINE` for a prototyped api mocking the responses.",
+						Optional: true,
 					},
-					"sandbox_endpoints": schema.SingleNestedAttribute{
-						Description: "Sandbox endpoints.",
+					"sandbox_endpoints":    endpointAttribute("Sandbox endpoint."),
+					"production_endpoints": endpointAttribute("Production endpoint."),
+					"sandbox_failovers": schema.ListNestedAttribute{
+						Description:  "Sandbox endpoints of a `failover` endpoint type, tried in order when the sandbox endpoint fails.",
+						Optional:     true,
+						NestedObject: endpointObject(),
+					},
+					"production_failovers": schema.ListNestedAttribute{
+						Description:  "Production endpoints of a `failover` endpoint type, tried in order when the production endpoint fails.",
+						Optional:     true,
+						NestedObject: endpointObject(),
+					},
+					"load_balance": schema.SingleNestedAttribute{
+						Description: "Endpoints of a `load_balance` endpoint type and the way the requests are balanced between them.",
 						Optional:    true,
 						Attributes: map[string]schema.Attribute{
-							"url": schema.StringAttribute{
-								Description: "Sandbox endpoint URL.",
+							"algorithm": schema.StringAttribute{
+								Description: "Class name of the load balancing algorithm. Defaults to `" + roundRobinAlgorithm + "`.",
+								Optional:    true,
+								Computed:    true,
+								Default:     stringdefault.StaticString(roundRobinAlgorithm),
+							},
+							"session_management": schema.StringAttribute{
+								Description: "Session affinity of the clients, one of `none`, `transport` using HTTP cookies, `soap` using SOAP sessions or `simpleClientSession` using the client ID. Defaults to `none`.",
 								Optional:    true,
+								Computed:    true,
+								Default:     stringdefault.StaticString("none"),
+								Validators: []validator.String{
+									stringvalidator.OneOf("none", "transport", "soap", "simpleClientSession"),
+								},
+							},
+							"session_timeout": schema.Int64Attribute{
+								Description: "Timeout of the sessions in milliseconds.",
+								Optional:    true,
+							},
+							"sandbox_endpoints": schema.ListNestedAttribute{
+								Description:  "Sandbox endpoints the requests are balanced between.",
+								Optional:     true,
+								NestedObject: endpointObject(),
+							},
+							"production_endpoints": schema.ListNestedAttribute{
+								Description:  "Production endpoints the requests are balanced between.",
+								Optional:     true,
+								NestedObject: endpointObject(),
 							},
 						},
 					},
-					"production_endpoints": schema.SingleNestedAttribute{
-						Description: "Sandbox endpoints.",
-						Optional:    true,
+					"aws_lambda": schema.SingleNestedAttribute{
+						Description: "Credentials the gateway invokes the AWS Lambda functions of an `awslambda` endpoint type with. " +
+							"The secret key is not returned by WSO2 API Manager, changes made to it outside of Terraform are not reported as drift.",
+						Optional: true,
 						Attributes: map[string]schema.Attribute{
-							"url": schema.StringAttribute{
-								Description: "Sandbox endpoint URL.",
+							"access_method": schema.StringAttribute{
+								Description: "Credentials used, `role-supplied` for the IAM role of the gateway or `stored` for the given access key.",
+								Required:    true,
+								Validators: []validator.String{
+									stringvalidator.OneOf("role-supplied", "stored"),
+								},
+							},
+							"access_key": schema.StringAttribute{
+								Description: "Access key ID of `stored` credentials.",
+								Optional:    true,
+							},
+							"secret_key": schema.StringAttribute{
+								Description: "Secret access key of `stored` credentials.",
+								Optional:    true,
+								Sensitive:   true,
+							},
+							"region": schema.StringAttribute{
+								Description: "AWS region of `stored` credentials.",
+								Optional:    true,
+							},
+							"assume_role": schema.BoolAttribute{
+								Description: "Whether the gateway assumes the given role to invoke the functions. Defaults to `false`.",
+								Optional:    true,
+								Computed:    true,
+								Default:     booldefault.StaticBool(false),
+							},
+							"role_arn": schema.StringAttribute{
+								Description: "ARN of the assumed role.",
+								Optional:    true,
+							},
+							"role_session_name": schema.StringAttribute{
+								Description: "Session name of the assumed role.",
+								Optional:    true,
+							},
+							"role_region": schema.StringAttribute{
+								Description: "AWS region of the assumed role.",
 								Optional:    true,
 							},
 						},
 					},
 				},
 			},
-			"operations": schema.ListNestedAttribute{
-				Description: "Operations of the api (Resources).",
-				Computed:    true,
-				Optional:    true,
+			"endpoint_security": schema.SingleNestedAttribute{
+				Description: "Credentials the gateway calls the endpoints of the api with. " +
+					"The secrets are not returned by WSO2 API Manager, changes made to them outside of Terraform are not reported as drift.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"production": environmentEndpointSecurityAttribute("Credentials of the production endpoints."),
+					"sandbox":    environmentEndpointSecurityAttribute("Credentials of the sandbox endpoints."),
+				},
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRoot("endpoint_config")),
+				},
+			},
+			"operations": schema.SetNestedAttribute{
+				Description: "Operations of the api (Resources). An operation is identified by its verb and target, " +
+					"which must be unique across the operations. Defaults to the operations generated by API-M, e.g. from the imported definition.",
+				Computed: true,
+				Optional: true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						// "id": schema.StringAttribute{
@@ -196,18 +462,349 @@ func (r *apiResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 						// 	Computed:    true,
 						// },
 						"target": schema.StringAttribute{
-							Description: "Operation target.",
-							Optional:    true,
+							Description: "Operation target, the resource path or, for `WS`, `WEBSUB`, `SSE` and `ASYNC` apis, the topic name.",
+							Required:    true,
 						},
 						"verb": schema.StringAttribute{
-							Description: "Operation verb.",
-							Optional:    true,
+							Description: "Operation verb, `SUBSCRIBE` or `PUBLISH` for `WS`, `WEBSUB`, `SSE` and `ASYNC` apis.",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "QUERY", "MUTATION", "SUBSCRIPTION", "SUBSCRIBE", "PUBLISH"),
+							},
+						},
+						"auth_type": schema.StringAttribute{
+							Description: "Security of the operation, one of `Application & Application User`, `Application`, `Application User` or `None` " +
+								"for an operation invoked without an access token. Defaults to `Application & Application User`.",
+							Optional: true,
+							Computed: true,
+							Default:  stringdefault.StaticString("Application & Application User"),
 							Validators: []validator.String{
-								stringvalidator.OneOf("GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"),
+								stringvalidator.OneOf("Application & Application User", "Application", "Application User", "None"),
+							},
+						},
+						"throttling_policy": schema.StringAttribute{
+							Description: "Throttling policy of the operation. Defaults to `Unlimited`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString("Unlimited"),
+						},
+						"scopes": schema.ListAttribute{
+							Description: "Names of the `scopes` the operation is restricted to, the access tokens must have one of them.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+						"operation_policies": schema.SingleNestedAttribute{
+							Description: "Operation policies applied to the request, response and fault flows of the operation, in order.",
+							Optional:    true,
+							Attributes: map[string]schema.Attribute{
+								"request":  operationPolicyListAttribute("Policies applied to the request flow."),
+								"response": operationPolicyListAttribute("Policies applied to the response flow."),
+								"fault":    operationPolicyListAttribute("Policies applied to the fault flow."),
+							},
+						},
+					},
+				},
+			},
+			"scopes": schema.ListNestedAttribute{
+				Description: "OAuth scopes of the api, restricting its operations to the users having the roles of a scope.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the scope.",
+							Required:    true,
+						},
+						"shared": schema.BoolAttribute{
+							Description: "Whether the scope is a shared scope, e.g. managed by `wso2apim_scope`, referenced by its name. Defaults to `false`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+						},
+						"display_name": schema.StringAttribute{
+							Description: "Display name of the api-local scope. Defaults to the name of the scope.",
+							Optional:    true,
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Description of the api-local scope.",
+							Optional:    true,
+						},
+						"roles": schema.ListAttribute{
+							Description: "Roles allowed to request the api-local scope, any user can request a scope without roles.",
+							ElementType: types.StringType,
+							Optional:    true,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"openapi_definition": schema.StringAttribute{
+				Description: "OpenAPI definition of the api, either the content of an OpenAPI 2 or 3 document in JSON or YAML, or its URL. " +
+					"Operations of the api are derived from the definition. " +
+					"Formatting-only changes and the extensions added by WSO2 API Manager are not reported as drift.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("operations")),
+				},
+			},
+			"graphql_schema": schema.StringAttribute{
+				Description: "GraphQL SDL schema of the api, makes the api a `GRAPHQL` api. " +
+					"Operations of the api are derived from the query, mutation and subscription fields of the schema. " +
+					"Formatting-only changes are not reported as drift.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("operations"), path.MatchRoot("openapi_definition")),
+				},
+			},
+			"graphql_complexity": schema.ListNestedAttribute{
+				Description: "Query complexity values of the fields of the GraphQL schema. " +
+					"The maximum query depth and complexity cannot be set per api: WSO2 API Manager reads them from the " +
+					"`graphQLMaxDepth` and `graphQLMaxComplexity` fields of the subscription throttling policies, managed in the Admin Portal " +
+					"or with the Admin REST API, which this provider does not manage. Select subscription policies with the desired limits in `policies`.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Description: "GraphQL type the field belongs to.",
+							Required:    true,
+						},
+						"field": schema.StringAttribute{
+							Description: "Field of the GraphQL type.",
+							Required:    true,
+						},
+						"complexity_value": schema.Int64Attribute{
+							Description: "Complexity value of the field.",
+							Required:    true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
 							},
 						},
 					},
 				},
+				Validators: []validator.List{
+					listvalidator.AlsoRequires(path.MatchRoot("graphql_schema")),
+				},
+			},
+			"wsdl_definition": schema.StringAttribute{
+				Description: "WSDL definition of the api, either its URL, the content of a WSDL document, " +
+					"or a zip archive of the WSDL and the files it imports encoded with `filebase64`. " +
+					"Makes the api a `SOAP` api passing the SOAP messages through to the backend, " +
+					"or a `SOAPTOREST` api exposing the operations of the WSDL as REST resources, depending on `type`. Defaults to `SOAP`. " +
+					"Formatting-only changes are not reported as drift.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("operations"), path.MatchRoot("openapi_definition"), path.MatchRoot("graphql_schema")),
+				},
+			},
+			"asyncapi_definition": schema.StringAttribute{
+				Description: "AsyncAPI definition of a `WS`, `WEBSUB`, `SSE` or `ASYNC` api, either the content of an AsyncAPI 2 document in JSON or YAML, or its URL. " +
+					"The `type` of the api is required with it. " +
+					"Operations of the api are derived from the channels of the definition. " +
+					"Formatting-only changes and the extensions added by WSO2 API Manager are not reported as drift.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("operations"), path.MatchRoot("openapi_definition"), path.MatchRoot("graphql_schema"), path.MatchRoot("wsdl_definition"),
+					),
+				},
+			},
+			"websub_subscription": schema.SingleNestedAttribute{
+				Description: "Verification of the subscriptions to a `WEBSUB` api, the gateway acting as the WebSub hub.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether the content delivered by the publishers is verified.",
+						Required:    true,
+					},
+					"secret": schema.StringAttribute{
+						Description: "Secret the content delivered by the publishers is signed with.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"signing_algorithm": schema.StringAttribute{
+						Description: "Algorithm the content is signed with, one of `SHA1`, `SHA256`, `SHA384` or `SHA512`.",
+						Optional:    true,
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+						Validators: []validator.String{
+							stringvalidator.OneOf("SHA1", "SHA256", "SHA384", "SHA512"),
+						},
+					},
+					"signature_header": schema.StringAttribute{
+						Description: "Header carrying the signature of the content.",
+						Optional:    true,
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+				},
+			},
+			"security": schema.SingleNestedAttribute{
+				Description: "Security schemes of the api, the application-level schemes `oauth2`, `api_key` and `basic_auth`, " +
+					"and the transport-level `mutualssl`. Defaults to `oauth2` when not set.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"oauth2": schema.BoolAttribute{
+						Description: "Whether the api accepts OAuth2 access tokens. Defaults to `true`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(true),
+					},
+					"api_key": schema.BoolAttribute{
+						Description: "Whether the api accepts API keys. Defaults to `false`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+					},
+					"basic_auth": schema.BoolAttribute{
+						Description: "Whether the api accepts the credentials of the users with basic authentication. Defaults to `false`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+					},
+					"mutualssl": schema.BoolAttribute{
+						Description: "Whether the api accepts clients authenticated with mutual SSL. Defaults to `false`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+					},
+					"application_security_mandatory": schema.BoolAttribute{
+						Description: "Whether one of the application-level schemes is mandatory, rather than optional when `mutualssl` is enabled. Defaults to `true`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(true),
+					},
+					"mutualssl_mandatory": schema.BoolAttribute{
+						Description: "Whether mutual SSL is mandatory, rather than optional when an application-level scheme is enabled. Defaults to `false`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+					},
+				},
+			},
+			"cors_configuration": schema.SingleNestedAttribute{
+				Description: "CORS configuration of the api, answering the preflight requests of browsers at the gateway.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether the CORS configuration is applied. Defaults to `true`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(true),
+					},
+					"allow_origins": schema.SetAttribute{
+						Description: "Origins allowed to call the api, `*` for any origin.",
+						ElementType: types.StringType,
+						Optional:    true,
+						Computed:    true,
+						Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+					},
+					"allow_credentials": schema.BoolAttribute{
+						Description: "Whether the browsers send credentials, e.g. cookies, with the requests. Defaults to `false`.",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+					},
+					"allow_headers": schema.SetAttribute{
+						Description: "Headers allowed in the requests.",
+						ElementType: types.StringType,
+						Optional:    true,
+						Computed:    true,
+						Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+					},
+					"allow_methods": schema.SetAttribute{
+						Description: "Methods allowed for the requests.",
+						ElementType: types.StringType,
+						Optional:    true,
+						Computed:    true,
+						Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(stringvalidator.OneOf("GET", "PUT", "POST", "DELETE", "PATCH", "OPTIONS", "HEAD")),
+						},
+					},
+				},
+			},
+			"authorization_header": schema.StringAttribute{
+				Description: "Name of the header carrying the access token, the one configured for the tenant or the server when not set.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"audiences": schema.SetAttribute{
+				Description: "Audiences the access tokens must be issued for, any audience when not set.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+			"key_managers": schema.SetAttribute{
+				Description: "Key managers the access tokens must be issued by, `all` for any key manager. Defaults to `all`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{types.StringValue("all")})),
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"business_information": schema.SingleNestedAttribute{
+				Description: "Owners of the api, displayed in the Developer Portal.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"business_owner": schema.StringAttribute{
+						Description: "Name of the business owner of the api.",
+						Optional:    true,
+					},
+					"business_owner_email": schema.StringAttribute{
+						Description: "Email of the business owner of the api.",
+						Optional:    true,
+					},
+					"technical_owner": schema.StringAttribute{
+						Description: "Name of the technical owner of the api.",
+						Optional:    true,
+					},
+					"technical_owner_email": schema.StringAttribute{
+						Description: "Email of the technical owner of the api.",
+						Optional:    true,
+					},
+				},
+			},
+			"tags": schema.SetAttribute{
+				Description: "Tags of the api, the Developer Portal can search and group the apis by.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+			"categories": schema.SetAttribute{
+				Description: "Names of the api categories the api belongs to, created in the Admin Portal.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+			"additional_properties": schema.MapNestedAttribute{
+				Description: "Custom properties of the api keyed by their name.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.StringAttribute{
+							Description: "Value of the property.",
+							Required:    true,
+						},
+						"display": schema.BoolAttribute{
+							Description: "Whether the property is displayed in the Developer Portal, supported by WSO2 API Manager 4.x. Defaults to `false`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+						},
+					},
+				},
 			},
 			"last_updated": schema.StringAttribute{
 				Description: "Last updated timestamp.",
@@ -217,60 +814,269 @@ func (r *apiResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 	}
 }
 
-// Create a new resource
-func (r *apiResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	// Retrieve values from plan
-	var plan apiResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+// ValidateConfig validates that the operations match the type of the api,
+// channels with SUBSCRIBE or PUBLISH verbs for WS, WEBSUB, SSE and ASYNC apis and resources otherwise,
+// that an operation is declared once per verb and target, that the shared scopes are only referenced by their name,
+// that the roles and tenants of the visibility, access control and subscription availability are set only when restricted,
+// that the security schemes secure the api, that the endpoint configuration matches its endpoint type,
+// and that the credentials of the endpoint security type are set.
+func (r *apiResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var apiType types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &apiType)...)
+	var operations types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("operations"), &operations)...)
+	var websubSubscription types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("websub_subscription"), &websubSubscription)...)
+	var scopes types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("scopes"), &scopes)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var endpointConfig *apim.APIEndpointConfig
-	if plan.EndpointConfig != nil {
-		var sandboxEndpoints *apim.APIEndpointAdvancedConfig
-		if plan.EndpointConfig.SandboxEndpoints != nil {
-			sandboxEndpoints = &apim.APIEndpointAdvancedConfig{
-				URL: plan.EndpointConfig.SandboxEndpoints.URL.ValueString(),
+	for i, element := range scopes.Elements() {
+		scope, ok := element.(types.Object)
+		if !ok || scope.IsNull() || scope.IsUnknown() {
+			continue
+		}
+		shared, ok := scope.Attributes()["shared"].(types.Bool)
+		if !ok || !shared.ValueBool() {
+			continue
+		}
+		for _, name := range []string{"display_name", "description", "roles"} {
+			if value := scope.Attributes()[name]; value != nil && !value.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("scopes").AtListIndex(i).AtName(name),
+					"Invalid shared scope",
+					name+" cannot be set on a shared scope, it is managed with the shared scope itself.",
+				)
 			}
 		}
-		var productionEndpoints *apim.APIEndpointAdvancedConfig
-		if plan.EndpointConfig.ProductionEndpoints != nil {
-			productionEndpoints = &apim.APIEndpointAdvancedConfig{
-				URL: plan.EndpointConfig.ProductionEndpoints.URL.ValueString(),
+	}
+	for _, restriction := range []struct{ attribute, value, list string }{
+		{"visibility", "RESTRICTED", "visible_roles"},
+		{"access_control", "RESTRICTED", "access_control_roles"},
+		{"subscription_availability", "specific_tenants", "subscription_available_tenants"},
+	} {
+		var value types.String
+		var list types.List
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(restriction.attribute), &value)...)
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(restriction.list), &list)...)
+		if value.IsUnknown() || list.IsUnknown() {
+			continue
+		}
+		restricted := value.ValueString() == restriction.value
+		if restricted && len(list.Elements()) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(restriction.list),
+				"Missing "+restriction.list,
+				restriction.list+" must be set when "+restriction.attribute+" is "+restriction.value+".",
+			)
+		}
+		if !restricted && len(list.Elements()) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(restriction.list),
+				"Invalid "+restriction.list,
+				restriction.list+" can only be set when "+restriction.attribute+" is "+restriction.value+".",
+			)
+		}
+	}
+	validateEndpointConfig(ctx, req, resp)
+	for _, environment := range []string{"production", "sandbox"} {
+		validateEndpointSecurity(ctx, req, resp, path.Root("endpoint_security").AtName(environment))
+	}
+	var securityObject types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("security"), &securityObject)...)
+	if !securityObject.IsNull() && !securityObject.IsUnknown() {
+		var security apiSecurityResourceModel
+		resp.Diagnostics.Append(securityObject.As(ctx, &security, basetypes.ObjectAsOptions{})...)
+		// Unset schemes take their default value.
+		enabled := func(value types.Bool, defaultValue bool) bool {
+			if value.IsNull() || value.IsUnknown() {
+				return defaultValue
+			}
+			return value.ValueBool()
+		}
+		application := enabled(security.OAuth2, true) || enabled(security.APIKey, false) || enabled(security.BasicAuth, false)
+		mutualSSL := enabled(security.MutualSSL, false)
+		if !application && !mutualSSL {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("security"),
+				"Missing security scheme",
+				"At least one of oauth2, api_key, basic_auth or mutualssl must be enabled.",
+			)
+		}
+		if application && mutualSSL && !enabled(security.ApplicationSecurityMandatory, true) && !enabled(security.MutualSSLMandatory, false) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("security"),
+				"Invalid security scheme",
+				"Either the application-level security or mutualssl must be mandatory.",
+			)
+		}
+	}
+	var graphQLSchema, wsdl, asyncAPI types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("graphql_schema"), &graphQLSchema)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("wsdl_definition"), &wsdl)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("asyncapi_definition"), &asyncAPI)...)
+	// The type is derived from the definition when it is not set.
+	typeSet := !apiType.IsNull() && !apiType.IsUnknown()
+	if typeSet && !graphQLSchema.IsNull() && apiType.ValueString() != "GRAPHQL" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid api type",
+			"An api with a graphql_schema must be of type GRAPHQL, got: "+apiType.ValueString(),
+		)
+	}
+	if typeSet && !wsdl.IsNull() && apiType.ValueString() != "SOAP" && apiType.ValueString() != "SOAPTOREST" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid api type",
+			"An api with a wsdl_definition must be of type SOAP or SOAPTOREST, got: "+apiType.ValueString(),
+		)
+	}
+	// The type cannot be derived from an AsyncAPI definition.
+	if apiType.IsNull() && !asyncAPI.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Missing api type",
+			"type is required for an api with an asyncapi_definition, one of: "+strings.Join(asyncAPITypes, ", "),
+		)
+	}
+	if typeSet && !asyncAPI.IsNull() && !isAsyncAPIType(apiType.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid api type",
+			"An api with an asyncapi_definition must be of type "+strings.Join(asyncAPITypes, ", ")+", got: "+apiType.ValueString(),
+		)
+	}
+	if !apiType.IsUnknown() && !websubSubscription.IsNull() && apiType.ValueString() != "WEBSUB" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("websub_subscription"),
+			"Invalid api type",
+			"websub_subscription is only supported by apis of type WEBSUB, got: "+apiType.ValueString(),
+		)
+	}
+	if operations.IsNull() || operations.IsUnknown() {
+		return
+	}
+	async := isAsyncAPIType(apiType.ValueString())
+	seen := map[string]bool{}
+	for _, element := range operations.Elements() {
+		operation, ok := element.(types.Object)
+		if !ok || operation.IsNull() || operation.IsUnknown() {
+			continue
+		}
+		verb, ok := operation.Attributes()["verb"].(types.String)
+		if !ok || verb.IsNull() || verb.IsUnknown() {
+			continue
+		}
+		if target, ok := operation.Attributes()["target"].(types.String); ok && !target.IsNull() && !target.IsUnknown() {
+			key := verb.ValueString() + " " + target.ValueString()
+			if seen[key] {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("operations").AtSetValue(element),
+					"Duplicate operation",
+					"The operation "+key+" is declared more than once, operations are identified by their verb and target.",
+				)
 			}
+			seen[key] = true
+		}
+		if apiType.IsUnknown() {
+			continue
 		}
-		endpointConfig = &apim.APIEndpointConfig{
-			EndpointType:        plan.EndpointConfig.EndpointType.ValueString(),
-			SandboxEndpoints:    sandboxEndpoints,
-			ProductionEndpoints: productionEndpoints,
+		channel := verb.ValueString() == "SUBSCRIBE" || verb.ValueString() == "PUBLISH"
+		if async && !channel {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("operations").AtSetValue(element).AtName("verb"),
+				"Invalid operation verb",
+				"Operations of "+apiType.ValueString()+" apis must have a SUBSCRIBE or PUBLISH verb, got: "+verb.ValueString(),
+			)
+		}
+		if !async && channel {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("operations").AtSetValue(element).AtName("verb"),
+				"Invalid operation verb",
+				verb.ValueString()+" operations are only supported by apis of type "+strings.Join(asyncAPITypes, ", "),
+			)
 		}
 	}
+}
 
-	// Create operations
-	var operations []apim.APIOperation
-	for _, operation := range plan.Operations {
-		operations = append(operations, apim.APIOperation{
-			// ID:     operation.ID.ValueString(),
-			Target: operation.Target.ValueString(),
-			Verb:   operation.Verb.ValueString(),
-		})
+// Create a new resource
+func (r *apiResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan apiResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	endpointConfig := endpointConfigRequest(&plan)
+
+	// Create operations
+	operations := apiOperationsRequest(plan.Operations)
+
 	// Create new api
-	api, err := apim.CreateAPI(&apim.APIReqBody{
-		Name:           plan.Name.ValueString(),
-		Description:    plan.Description.ValueString(),
-		Context:        plan.Context.ValueString(),
-		Version:        plan.Version.ValueString(),
-		Provider:       plan.Provider.ValueString(),
-		Type:           plan.Type.ValueString(),
-		Policies:       plan.Policies,
-		EndpointConfig: endpointConfig,
-		Operations:     operations,
-	})
+	reqBody := &apim.APIReqBody{
+		Name:                            plan.Name.ValueString(),
+		Description:                     plan.Description.ValueString(),
+		Context:                         plan.Context.ValueString(),
+		Version:                         plan.Version.ValueString(),
+		Provider:                        plan.Provider.ValueString(),
+		IsDefaultVersion:                plan.IsDefaultVersion.ValueBool(),
+		Type:                            plan.Type.ValueString(),
+		Policies:                        plan.Policies,
+		Visibility:                      plan.Visibility.ValueString(),
+		VisibleRoles:                    append([]string{}, plan.VisibleRoles...),
+		VisibleTenants:                  append([]string{}, plan.VisibleTenants...),
+		AccessControl:                   plan.AccessControl.ValueString(),
+		AccessControlRoles:              append([]string{}, plan.AccessControlRoles...),
+		SubscriptionAvailability:        strings.ToUpper(plan.SubscriptionAvailability.ValueString()),
+		SubscriptionAvailableTenants:    append([]string{}, plan.SubscriptionAvailableTenants...),
+		EndpointConfig:                  endpointConfig,
+		Operations:                      operations,
+		WebsubSubscriptionConfiguration: websubSubscriptionRequest(plan.WebsubSubscription),
+		SecurityScheme:                  securitySchemeRequest(plan.Security),
+		CorsConfiguration:               corsConfigurationRequest(plan.CorsConfiguration),
+		AuthorizationHeader:             plan.AuthorizationHeader.ValueString(),
+		Audiences:                       append([]string{}, plan.Audiences...),
+		KeyManagers:                     plan.KeyManagers,
+		Scopes:                          apiScopesRequest(plan.Scopes),
+		BusinessInformation:             businessInformationRequest(plan.BusinessInformation),
+		Tags:                            append([]string{}, plan.Tags...),
+		Categories:                      append([]string{}, plan.Categories...),
+		AdditionalProperties:            additionalPropertiesRequest(plan.AdditionalProperties),
+	}
+	var api *apim.APICreateResp
+	var err error
+	switch {
+	case !plan.SourceApiID.IsNull():
+		api, err = r.copyAPI(ctx, &plan, reqBody)
+	case !plan.OpenAPI.IsNull():
+		// Operations are derived from the definition.
+		reqBody.Operations = nil
+		api, err = r.client.ImportOpenAPI(ctx, plan.OpenAPI.ValueString(), reqBody)
+	case !plan.GraphQLSchema.IsNull():
+		// Operations are derived from the schema.
+		api, err = r.client.ImportGraphQLSchema(ctx, plan.GraphQLSchema.ValueString(), reqBody)
+	case !plan.WSDL.IsNull():
+		implementationType := "SOAP"
+		if !plan.Type.IsUnknown() {
+			implementationType = plan.Type.ValueString()
+		}
+		// Operations are derived from the WSDL.
+		api, err = r.client.ImportWSDL(ctx, plan.WSDL.ValueString(), implementationType, reqBody)
+	case !plan.AsyncAPI.IsNull():
+		// Operations are derived from the channels of the definition.
+		api, err = r.client.ImportAsyncAPI(ctx, plan.AsyncAPI.ValueString(), reqBody)
+	default:
+		api, err = r.client.CreateAPI(ctx, reqBody)
+	}
 	if err != nil {
+		if api != nil {
+			// Keep the created api in state, so that it is tainted instead of orphaned.
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), api.ID)...)
+		}
 		resp.Diagnostics.AddError(
 			"Error creating api",
 			"Could not create api, unexpected error: "+err.Error(),
@@ -278,18 +1084,20 @@ func (r *apiResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	lifecycle, err := apim.ChangeLifeCycleStatus(api.ID, "Publish")
-	if err != nil {
-		resp.Diagnostics.AddWarning(
-			"Error changing api lifecycle status",
-			"Could not change api lifecycle status to PUBLISHED, unexpected error: "+err.Error(),
-		)
+	if plan.GraphQLComplexity != nil {
+		err = r.client.UpdateGraphQLComplexity(ctx, api.ID, graphQLComplexityRequest(plan.GraphQLComplexity))
+		if err != nil {
+			// Keep the imported api in state, so that it is tainted instead of orphaned.
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), api.ID)...)
+			resp.Diagnostics.AddError(
+				"Error creating api",
+				"Could not set GraphQL query complexity of api, unexpected error: "+err.Error(),
+			)
+			return
+		}
 	}
 
-	lifecycleStatus := api.LifeCycleStatus
-	if lifecycle != nil {
-		lifecycleStatus = strings.ToUpper(lifecycle.LifecycleState.State)
-	}
+	r.changeLifecycleState(ctx, req.Config, &plan, api, &resp.Diagnostics)
 
 	apiContext := api.Context
 	if !r.config.ApiContextPrefix.IsUnknown() {
@@ -303,36 +1111,34 @@ func (r *apiResource) Create(ctx context.Context, req resource.CreateRequest, re
 	plan.Context = types.StringValue(apiContext)
 	plan.Version = types.StringValue(api.Version)
 	plan.Provider = types.StringValue(api.Provider)
+	plan.IsDefaultVersion = types.BoolValue(api.IsDefaultVersion)
 	plan.Type = types.StringValue(api.Type)
-	plan.LifeCycleStatus = types.StringValue(lifecycleStatus)
 	plan.HasThumbnail = types.BoolValue(api.HasThumbnail)
 	plan.Policies = api.Policies
-	var planOperations []apiOperationResourceModel
-	for _, operation := range api.Operations {
-		planOperations = append(planOperations, apiOperationResourceModel{
-			// ID:     types.StringValue(operation.ID),
-			Target: types.StringValue(operation.Target),
-			Verb:   types.StringValue(operation.Verb),
-		})
+	plan.Visibility = types.StringValue(api.Visibility)
+	plan.VisibleRoles = append([]string{}, api.VisibleRoles...)
+	plan.VisibleTenants = append([]string{}, api.VisibleTenants...)
+	plan.AccessControl = types.StringValue(api.AccessControl)
+	plan.AccessControlRoles = append([]string{}, api.AccessControlRoles...)
+	plan.SubscriptionAvailability = types.StringValue(strings.ToLower(api.SubscriptionAvailability))
+	plan.SubscriptionAvailableTenants = append([]string{}, api.SubscriptionAvailableTenants...)
+	plan.EndpointConfig = endpointConfigState(plan.EndpointConfig, api.EndpointConfig)
+	plan.EndpointSecurity = endpointSecurityState(plan.EndpointSecurity, api.EndpointConfig)
+	plan.WebsubSubscription = websubSubscriptionState(plan.WebsubSubscription, api.WebsubSubscriptionConfiguration)
+	plan.Security = securitySchemeState(plan.Security, api.SecurityScheme)
+	plan.CorsConfiguration = corsConfigurationState(plan.CorsConfiguration, api.CorsConfiguration)
+	plan.AuthorizationHeader = types.StringValue(api.AuthorizationHeader)
+	plan.Audiences = append([]string{}, api.Audiences...)
+	if api.KeyManagers != nil {
+		// WSO2 API Manager 3.2 does not report the key managers of an api.
+		plan.KeyManagers = append([]string{}, api.KeyManagers...)
 	}
-	var planEndpointConfig *apiEndpointConfigResourceModel
-	if api.EndpointConfig != nil {
-		var planSandboxEndpoints *apiEndpointAdvancedConfigResourceModel
-		if api.EndpointConfig.SandboxEndpoints != nil {
-			planSandboxEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.SandboxEndpoints.URL)}
-		}
-		var planProductionEndpoints *apiEndpointAdvancedConfigResourceModel
-		if api.EndpointConfig.ProductionEndpoints != nil {
-			planProductionEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.ProductionEndpoints.URL)}
-		}
-		planEndpointConfig = &apiEndpointConfigResourceModel{
-			EndpointType:        types.StringValue(api.EndpointConfig.EndpointType),
-			SandboxEndpoints:    planSandboxEndpoints,
-			ProductionEndpoints: planProductionEndpoints,
-		}
-	}
-	plan.EndpointConfig = planEndpointConfig
-	plan.Operations = planOperations
+	plan.BusinessInformation = businessInformationState(plan.BusinessInformation, api.BusinessInformation)
+	plan.Tags = append([]string{}, api.Tags...)
+	plan.Categories = append([]string{}, api.Categories...)
+	plan.AdditionalProperties = additionalPropertiesState(plan.AdditionalProperties, api.AdditionalProperties, api.AdditionalPropertiesMap)
+	plan.Operations = apiOperationsState(plan.Operations, api.Operations)
+	plan.Scopes = apiScopesState(plan.Scopes, api.Scopes)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
 
 	// Set state to fully populated data
@@ -354,7 +1160,13 @@ func (r *apiResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	}
 
 	// Get refreshed api value from WSO2 API Manager
-	api, err := apim.GetAPI(state.ID.ValueString())
+	api, err := r.client.GetAPI(ctx, state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		// The api was deleted outside of Terraform, let Terraform plan to re-create it.
+		tflog.Warn(ctx, "WSO2 API Manager Api not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading WSO2 API Manager Api",
@@ -363,6 +1175,89 @@ func (r *apiResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
+	if !state.OpenAPI.IsNull() && !apim.IsDefinitionURL(state.OpenAPI.ValueString()) {
+		definition, err := r.client.GetOpenAPI(ctx, state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not read OpenAPI definition of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		state.OpenAPI, err = openAPIDefinitionValue(state.OpenAPI, definition)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not parse OpenAPI definition of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if !state.AsyncAPI.IsNull() && !apim.IsDefinitionURL(state.AsyncAPI.ValueString()) {
+		definition, err := r.client.GetAsyncAPI(ctx, state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not read AsyncAPI definition of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		state.AsyncAPI, err = asyncAPIDefinitionValue(state.AsyncAPI, definition)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not parse AsyncAPI definition of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if !state.GraphQLSchema.IsNull() {
+		sdl, err := r.client.GetGraphQLSchema(ctx, state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not read GraphQL schema of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		if apim.NormalizeGraphQLSchema(sdl) != apim.NormalizeGraphQLSchema(state.GraphQLSchema.ValueString()) {
+			state.GraphQLSchema = types.StringValue(sdl)
+		}
+	}
+
+	if !state.WSDL.IsNull() && !apim.IsDefinitionURL(state.WSDL.ValueString()) {
+		content, err := r.client.GetWSDL(ctx, state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not read WSDL definition of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		state.WSDL, err = wsdlDefinitionValue(state.WSDL, content)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not parse WSDL definition of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if state.GraphQLComplexity != nil {
+		complexity, err := r.client.GetGraphQLComplexity(ctx, state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading WSO2 API Manager Api",
+				"Could not read GraphQL query complexity of api ID "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		state.GraphQLComplexity = graphQLComplexityState(state.GraphQLComplexity, complexity)
+	}
+
 	apiContext := api.Context
 	if !r.config.ApiContextPrefix.IsUnknown() {
 		apiContext = strings.Split(api.Context, r.config.ApiContextPrefix.ValueString())[1]
@@ -374,36 +1269,39 @@ func (r *apiResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	state.Context = types.StringValue(apiContext)
 	state.Version = types.StringValue(api.Version)
 	state.Provider = types.StringValue(api.Provider)
+	state.IsDefaultVersion = types.BoolValue(api.IsDefaultVersion)
 	state.Type = types.StringValue(api.Type)
 	state.LifeCycleStatus = types.StringValue(api.LifeCycleStatus)
+	// Keep the lifecycle state being approved, if any.
+	if state.LifecycleState.IsNull() || api.WorkflowStatus != apim.WorkflowStatusCreated {
+		state.LifecycleState = types.StringValue(strings.ToUpper(api.LifeCycleStatus))
+	}
 	state.HasThumbnail = types.BoolValue(api.HasThumbnail)
 	state.Policies = api.Policies
-	var stateEndpointConfig *apiEndpointConfigResourceModel
-	if api.EndpointConfig != nil {
-		var stateSandboxEndpoints *apiEndpointAdvancedConfigResourceModel
-		if api.EndpointConfig.SandboxEndpoints != nil {
-			stateSandboxEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.SandboxEndpoints.URL)}
-		}
-		var stateProductionEndpoints *apiEndpointAdvancedConfigResourceModel
-		if api.EndpointConfig.ProductionEndpoints != nil {
-			stateProductionEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.ProductionEndpoints.URL)}
-		}
-		stateEndpointConfig = &apiEndpointConfigResourceModel{
-			EndpointType:        types.StringValue(api.EndpointConfig.EndpointType),
-			SandboxEndpoints:    stateSandboxEndpoints,
-			ProductionEndpoints: stateProductionEndpoints,
-		}
-	}
-	state.EndpointConfig = stateEndpointConfig
-	var operations []apiOperationResourceModel
-	for _, operation := range api.Operations {
-		operations = append(operations, apiOperationResourceModel{
-			// ID:     types.StringValue(operation.ID),
-			Target: types.StringValue(operation.Target),
-			Verb:   types.StringValue(operation.Verb),
-		})
+	state.Visibility = types.StringValue(api.Visibility)
+	state.VisibleRoles = append([]string{}, api.VisibleRoles...)
+	state.VisibleTenants = append([]string{}, api.VisibleTenants...)
+	state.AccessControl = types.StringValue(api.AccessControl)
+	state.AccessControlRoles = append([]string{}, api.AccessControlRoles...)
+	state.SubscriptionAvailability = types.StringValue(strings.ToLower(api.SubscriptionAvailability))
+	state.SubscriptionAvailableTenants = append([]string{}, api.SubscriptionAvailableTenants...)
+	state.EndpointConfig = endpointConfigState(state.EndpointConfig, api.EndpointConfig)
+	state.EndpointSecurity = endpointSecurityState(state.EndpointSecurity, api.EndpointConfig)
+	state.WebsubSubscription = websubSubscriptionState(state.WebsubSubscription, api.WebsubSubscriptionConfiguration)
+	state.Security = securitySchemeState(state.Security, api.SecurityScheme)
+	state.CorsConfiguration = corsConfigurationState(state.CorsConfiguration, api.CorsConfiguration)
+	state.AuthorizationHeader = types.StringValue(api.AuthorizationHeader)
+	state.Audiences = append([]string{}, api.Audiences...)
+	if api.KeyManagers != nil {
+		// WSO2 API Manager 3.2 does not report the key managers of an api.
+		state.KeyManagers = append([]string{}, api.KeyManagers...)
 	}
-	state.Operations = operations
+	state.BusinessInformation = businessInformationState(state.BusinessInformation, api.BusinessInformation)
+	state.Tags = append([]string{}, api.Tags...)
+	state.Categories = append([]string{}, api.Categories...)
+	state.AdditionalProperties = additionalPropertiesState(state.AdditionalProperties, api.AdditionalProperties, api.AdditionalPropertiesMap)
+	state.Operations = apiOperationsState(state.Operations, api.Operations)
+	state.Scopes = apiScopesState(state.Scopes, api.Scopes)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -422,64 +1320,155 @@ func (r *apiResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	var endpointConfig *apim.APIEndpointConfig
-	if plan.EndpointConfig != nil {
-		var sandboxEndpoints *apim.APIEndpointAdvancedConfig
-		if plan.EndpointConfig.SandboxEndpoints != nil {
-			sandboxEndpoints = &apim.APIEndpointAdvancedConfig{
-				URL: plan.EndpointConfig.SandboxEndpoints.URL.ValueString(),
+	endpointConfig := endpointConfigRequest(&plan)
+
+	operations := apiOperationsRequest(plan.Operations)
+
+	var state apiResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case !plan.OpenAPI.IsNull():
+		if !plan.OpenAPI.Equal(state.OpenAPI) {
+			err := r.client.UpdateOpenAPI(ctx, plan.ID.ValueString(), plan.OpenAPI.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error updating api",
+					"Could not update OpenAPI definition of api, unexpected error: "+err.Error(),
+				)
+				return
 			}
 		}
-		var productionEndpoints *apim.APIEndpointAdvancedConfig
-		if plan.EndpointConfig.ProductionEndpoints != nil {
-			productionEndpoints = &apim.APIEndpointAdvancedConfig{
-				URL: plan.EndpointConfig.ProductionEndpoints.URL.ValueString(),
+		// Keep the operations derived from the definition.
+		current, err := r.client.GetAPI(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating api",
+				"Could not read api operations, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		operations = current.Operations
+	case !plan.GraphQLSchema.IsNull() && !plan.GraphQLSchema.Equal(state.GraphQLSchema):
+		ops, err := r.client.ValidateGraphQLSchema(ctx, plan.GraphQLSchema.ValueString())
+		if err == nil {
+			err = r.client.UpdateGraphQLSchema(ctx, plan.ID.ValueString(), plan.GraphQLSchema.ValueString())
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating api",
+				"Could not update GraphQL schema of api, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		// The operations are derived from the new schema.
+		operations = ops
+	case !plan.GraphQLSchema.IsNull():
+		// Keep the operations derived from the schema.
+		current, err := r.client.GetAPI(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating api",
+				"Could not read api operations, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		operations = current.Operations
+	case !plan.WSDL.IsNull():
+		if !plan.WSDL.Equal(state.WSDL) {
+			err := r.client.UpdateWSDL(ctx, plan.ID.ValueString(), plan.WSDL.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error updating api",
+					"Could not update WSDL definition of api, unexpected error: "+err.Error(),
+				)
+				return
 			}
 		}
-		endpointConfig = &apim.APIEndpointConfig{
-			EndpointType:        plan.EndpointConfig.EndpointType.ValueString(),
-			SandboxEndpoints:    sandboxEndpoints,
-			ProductionEndpoints: productionEndpoints,
+		// Keep the operations derived from the WSDL.
+		current, err := r.client.GetAPI(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating api",
+				"Could not read api operations, unexpected error: "+err.Error(),
+			)
+			return
 		}
+		operations = current.Operations
+	case !plan.AsyncAPI.IsNull():
+		if !plan.AsyncAPI.Equal(state.AsyncAPI) {
+			err := r.client.UpdateAsyncAPI(ctx, plan.ID.ValueString(), plan.AsyncAPI.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error updating api",
+					"Could not update AsyncAPI definition of api, unexpected error: "+err.Error(),
+				)
+				return
+			}
+		}
+		// Keep the operations derived from the channels of the definition.
+		current, err := r.client.GetAPI(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating api",
+				"Could not read api operations, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		operations = current.Operations
 	}
 
-	var operations []apim.APIOperation
-	for _, operation := range plan.Operations {
-		operations = append(operations, apim.APIOperation{
-			// ID:     operation.ID.ValueString(),
-			Target: operation.Target.ValueString(),
-			Verb:   operation.Verb.ValueString(),
-		})
-	}
-
-	// Create new api
-	api, err := apim.UpdateAPI(plan.ID.ValueString(), &apim.APIReqBody{
-		Description:    plan.Description.ValueString(),
-		Type:           plan.Type.ValueString(),
-		Policies:       plan.Policies,
-		EndpointConfig: endpointConfig,
-		Operations:     operations,
+	// Update existing api
+	api, err := r.client.UpdateAPI(ctx, plan.ID.ValueString(), &apim.APIReqBody{
+		Description:                     plan.Description.ValueString(),
+		IsDefaultVersion:                plan.IsDefaultVersion.ValueBool(),
+		Type:                            plan.Type.ValueString(),
+		Policies:                        plan.Policies,
+		Visibility:                      plan.Visibility.ValueString(),
+		VisibleRoles:                    append([]string{}, plan.VisibleRoles...),
+		VisibleTenants:                  append([]string{}, plan.VisibleTenants...),
+		AccessControl:                   plan.AccessControl.ValueString(),
+		AccessControlRoles:              append([]string{}, plan.AccessControlRoles...),
+		SubscriptionAvailability:        strings.ToUpper(plan.SubscriptionAvailability.ValueString()),
+		SubscriptionAvailableTenants:    append([]string{}, plan.SubscriptionAvailableTenants...),
+		EndpointConfig:                  endpointConfig,
+		Operations:                      operations,
+		WebsubSubscriptionConfiguration: websubSubscriptionRequest(plan.WebsubSubscription),
+		SecurityScheme:                  securitySchemeRequest(plan.Security),
+		CorsConfiguration:               corsConfigurationRequest(plan.CorsConfiguration),
+		AuthorizationHeader:             plan.AuthorizationHeader.ValueString(),
+		Audiences:                       append([]string{}, plan.Audiences...),
+		KeyManagers:                     plan.KeyManagers,
+		Scopes:                          apiScopesRequest(plan.Scopes),
+		BusinessInformation:             businessInformationRequest(plan.BusinessInformation),
+		Tags:                            append([]string{}, plan.Tags...),
+		Categories:                      append([]string{}, plan.Categories...),
+		AdditionalProperties:            additionalPropertiesRequest(plan.AdditionalProperties),
 	})
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error creating api",
-			"Could not create api, unexpected error: "+err.Error(),
+			"Error updating api",
+			"Could not update api, unexpected error: "+err.Error(),
 		)
 		return
 	}
 
-	lifecycle, err := apim.ChangeLifeCycleStatus(api.ID, "Publish")
-	if err != nil {
-		resp.Diagnostics.AddWarning(
-			"Error changing api lifecycle status",
-			"Could not change api lifecycle status to PUBLISHED, unexpected error: "+err.Error(),
-		)
+	if plan.GraphQLComplexity != nil || state.GraphQLComplexity != nil {
+		err = r.client.UpdateGraphQLComplexity(ctx, api.ID, graphQLComplexityRequest(plan.GraphQLComplexity))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating api",
+				"Could not update GraphQL query complexity of api, unexpected error: "+err.Error(),
+			)
+			return
+		}
 	}
 
-	lifecycleStatus := api.LifeCycleStatus
-	if lifecycle != nil {
-		lifecycleStatus = strings.ToUpper(lifecycle.LifecycleState.State)
-	}
+	r.changeLifecycleState(ctx, req.Config, &plan, api, &resp.Diagnostics)
 
 	apiContext := api.Context
 	if !r.config.ApiContextPrefix.IsUnknown() {
@@ -493,36 +1482,34 @@ func (r *apiResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	plan.Context = types.StringValue(apiContext)
 	plan.Version = types.StringValue(api.Version)
 	plan.Provider = types.StringValue(api.Provider)
+	plan.IsDefaultVersion = types.BoolValue(api.IsDefaultVersion)
 	plan.Type = types.StringValue(api.Type)
-	plan.LifeCycleStatus = types.StringValue(lifecycleStatus)
 	plan.HasThumbnail = types.BoolValue(api.HasThumbnail)
 	plan.Policies = api.Policies
-	var planOperations []apiOperationResourceModel
-	for _, operation := range api.Operations {
-		planOperations = append(planOperations, apiOperationResourceModel{
-			// ID:     types.StringValue(operation.ID),
-			Target: types.StringValue(operation.Target),
-			Verb:   types.StringValue(operation.Verb),
-		})
+	plan.Visibility = types.StringValue(api.Visibility)
+	plan.VisibleRoles = append([]string{}, api.VisibleRoles...)
+	plan.VisibleTenants = append([]string{}, api.VisibleTenants...)
+	plan.AccessControl = types.StringValue(api.AccessControl)
+	plan.AccessControlRoles = append([]string{}, api.AccessControlRoles...)
+	plan.SubscriptionAvailability = types.StringValue(strings.ToLower(api.SubscriptionAvailability))
+	plan.SubscriptionAvailableTenants = append([]string{}, api.SubscriptionAvailableTenants...)
+	plan.EndpointConfig = endpointConfigState(plan.EndpointConfig, api.EndpointConfig)
+	plan.EndpointSecurity = endpointSecurityState(plan.EndpointSecurity, api.EndpointConfig)
+	plan.WebsubSubscription = websubSubscriptionState(plan.WebsubSubscription, api.WebsubSubscriptionConfiguration)
+	plan.Security = securitySchemeState(plan.Security, api.SecurityScheme)
+	plan.CorsConfiguration = corsConfigurationState(plan.CorsConfiguration, api.CorsConfiguration)
+	plan.AuthorizationHeader = types.StringValue(api.AuthorizationHeader)
+	plan.Audiences = append([]string{}, api.Audiences...)
+	if api.KeyManagers != nil {
+		// WSO2 API Manager 3.2 does not report the key managers of an api.
+		plan.KeyManagers = append([]string{}, api.KeyManagers...)
 	}
-	var planEndpointConfig *apiEndpointConfigResourceModel
-	if api.EndpointConfig != nil {
-		var planSandboxEndpoints *apiEndpointAdvancedConfigResourceModel
-		if api.EndpointConfig.SandboxEndpoints != nil {
-			planSandboxEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.SandboxEndpoints.URL)}
-		}
-		var planProductionEndpoints *apiEndpointAdvancedConfigResourceModel
-		if api.EndpointConfig.ProductionEndpoints != nil {
-			planProductionEndpoints = &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(api.EndpointConfig.ProductionEndpoints.URL)}
-		}
-		planEndpointConfig = &apiEndpointConfigResourceModel{
-			EndpointType:        types.StringValue(api.EndpointConfig.EndpointType),
-			SandboxEndpoints:    planSandboxEndpoints,
-			ProductionEndpoints: planProductionEndpoints,
-		}
-	}
-	plan.EndpointConfig = planEndpointConfig
-	plan.Operations = planOperations
+	plan.BusinessInformation = businessInformationState(plan.BusinessInformation, api.BusinessInformation)
+	plan.Tags = append([]string{}, api.Tags...)
+	plan.Categories = append([]string{}, api.Categories...)
+	plan.AdditionalProperties = additionalPropertiesState(plan.AdditionalProperties, api.AdditionalProperties, api.AdditionalPropertiesMap)
+	plan.Operations = apiOperationsState(plan.Operations, api.Operations)
+	plan.Scopes = apiScopesState(plan.Scopes, api.Scopes)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
 
 	diags = resp.State.Set(ctx, plan)
@@ -542,7 +1529,7 @@ func (r *apiResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	}
 
 	// Delete existing api
-	err := apim.DeleteAPI(state.ID.ValueString())
+	err := r.client.DeleteAPI(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting WSO2 API Manager Api",
@@ -555,3 +1542,340 @@ func (r *apiResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 func (r *apiResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 }
+
+// copyAPI creates the api of the given plan as a new version of its source api, and applies the given api spec
+// and the definition of the plan, if any, to the copy.
+// The operations of the copy are kept unless the plan configures them.
+// Returns the resulting api and any error encountered, the copy is returned along with the error
+// when it was created but could not be updated.
+func (r *apiResource) copyAPI(ctx context.Context, plan *apiResourceModel, reqBody *apim.APIReqBody) (*apim.APICreateResp, error) {
+	api, err := r.client.CopyAPI(ctx, plan.SourceApiID.ValueString(), reqBody.Version, reqBody.IsDefaultVersion)
+	if err != nil {
+		return nil, err
+	}
+	tflog.Info(ctx, "Copied WSO2 API Manager Api", map[string]any{"source_api_id": plan.SourceApiID.ValueString(), "id": api.ID})
+
+	operations := reqBody.Operations
+	switch {
+	case !plan.OpenAPI.IsNull():
+		err = r.client.UpdateOpenAPI(ctx, api.ID, plan.OpenAPI.ValueString())
+	case !plan.GraphQLSchema.IsNull():
+		// The operations are derived from the new schema.
+		operations, err = r.client.ValidateGraphQLSchema(ctx, plan.GraphQLSchema.ValueString())
+		if err == nil {
+			err = r.client.UpdateGraphQLSchema(ctx, api.ID, plan.GraphQLSchema.ValueString())
+		}
+	case !plan.WSDL.IsNull():
+		err = r.client.UpdateWSDL(ctx, api.ID, plan.WSDL.ValueString())
+	case !plan.AsyncAPI.IsNull():
+		err = r.client.UpdateAsyncAPI(ctx, api.ID, plan.AsyncAPI.ValueString())
+	}
+	if err != nil {
+		return api, err
+	}
+
+	if len(operations) == 0 {
+		// Keep the operations of the copy, derived from the definition if any.
+		current, err := r.client.GetAPI(ctx, api.ID)
+		if err != nil {
+			return api, err
+		}
+		operations = current.Operations
+	}
+	updated, err := r.client.UpdateAPI(ctx, api.ID, &apim.APIReqBody{
+		Description:                     reqBody.Description,
+		IsDefaultVersion:                reqBody.IsDefaultVersion,
+		Type:                            reqBody.Type,
+		Policies:                        reqBody.Policies,
+		Visibility:                      reqBody.Visibility,
+		VisibleRoles:                    reqBody.VisibleRoles,
+		VisibleTenants:                  reqBody.VisibleTenants,
+		AccessControl:                   reqBody.AccessControl,
+		AccessControlRoles:              reqBody.AccessControlRoles,
+		SubscriptionAvailability:        reqBody.SubscriptionAvailability,
+		SubscriptionAvailableTenants:    reqBody.SubscriptionAvailableTenants,
+		EndpointConfig:                  reqBody.EndpointConfig,
+		Operations:                      operations,
+		WebsubSubscriptionConfiguration: reqBody.WebsubSubscriptionConfiguration,
+		SecurityScheme:                  reqBody.SecurityScheme,
+		CorsConfiguration:               reqBody.CorsConfiguration,
+		AuthorizationHeader:             reqBody.AuthorizationHeader,
+		Audiences:                       reqBody.Audiences,
+		KeyManagers:                     reqBody.KeyManagers,
+		Scopes:                          reqBody.Scopes,
+		BusinessInformation:             reqBody.BusinessInformation,
+		Tags:                            reqBody.Tags,
+		Categories:                      reqBody.Categories,
+		AdditionalProperties:            reqBody.AdditionalProperties,
+	})
+	if err != nil {
+		return api, err
+	}
+	return updated, nil
+}
+
+// openAPIDefinitionValue returns the prior definition if it is equal to the given definition read from WSO2 API Manager
+// once both are normalized, so that formatting-only changes are not reported as drift.
+// Otherwise returns the normalized definition read from WSO2 API Manager.
+func openAPIDefinitionValue(prior types.String, definition string) (types.String, error) {
+	normalized, err := apim.NormalizeOpenAPI(definition)
+	if err != nil {
+		return prior, err
+	}
+	if priorNormalized, err := apim.NormalizeOpenAPI(prior.ValueString()); err == nil && priorNormalized == normalized {
+		return prior, nil
+	}
+	return types.StringValue(normalized), nil
+}
+
+// changeLifecycleState moves the given api from its current lifecycle state to the lifecycle_state of the given plan,
+// and sets the lifecycle_status of the plan to the resulting state.
+// The lifecycle_state of the plan is kept while a change is pending approval or has failed, so it is retried by the next apply.
+// A failure is an error only when lifecycle_state is set in the given configuration, otherwise it is a warning as publishing
+// by default fails e.g. for an api without a deployed revision, which would otherwise taint a newly created api.
+func (r *apiResource) changeLifecycleState(ctx context.Context, config tfsdk.Config, plan *apiResourceModel, api *apim.APICreateResp, diags *diag.Diagnostics) {
+	current := strings.ToUpper(api.LifeCycleStatus)
+	target := plan.LifecycleState.ValueString()
+	lifecycle := &apim.LifecycleChange{State: current, Pending: api.WorkflowStatus == apim.WorkflowStatusCreated}
+	var err error
+	if !lifecycle.Pending {
+		timeout := time.Duration(plan.LifecycleApprovalTimeout.ValueInt64()) * time.Second
+		lifecycle, err = r.client.ChangeLifecycleState(ctx, api.ID, current, target, lifecycleChecklistRequest(plan.LifecycleChecklist), timeout)
+	}
+	if err != nil {
+		var configured types.String
+		diags.Append(config.GetAttribute(ctx, path.Root("lifecycle_state"), &configured)...)
+		summary := "Error changing api lifecycle state"
+		detail := "Could not change api lifecycle state from " + current + " to " + target + ", unexpected error: " + err.Error()
+		if configured.IsNull() {
+			diags.AddWarning(summary, detail+"\n\nSet lifecycle_state to keep the api in another state, e.g. CREATED until a revision is deployed.")
+		} else {
+			diags.AddError(summary, detail)
+		}
+	}
+	if lifecycle.Pending {
+		diags.AddWarning(
+			"Api lifecycle change pending approval",
+			"The change of the api lifecycle state from "+lifecycle.State+" towards "+target+" is waiting for the approval of a workflow, "+
+				"the remaining lifecycle actions are applied by the next apply once it is approved.",
+		)
+	} else if err == nil {
+		plan.LifecycleState = types.StringValue(lifecycle.State)
+	}
+	plan.LifeCycleStatus = types.StringValue(lifecycle.State)
+}
+
+// lifecycleChecklistRequest returns the lifecycle checklist items of the given plan.
+func lifecycleChecklistRequest(plan *apiLifecycleChecklistResourceModel) map[string]bool {
+	checklist := map[string]bool{}
+	if plan == nil {
+		return checklist
+	}
+	if !plan.DeprecateOldVersions.IsNull() {
+		checklist[apim.ChecklistDeprecateOldVersions] = plan.DeprecateOldVersions.ValueBool()
+	}
+	if !plan.RequiresResubscription.IsNull() {
+		checklist[apim.ChecklistRequiresResubscription] = plan.RequiresResubscription.ValueBool()
+	}
+	return checklist
+}
+
+// asyncAPIDefinitionValue returns the prior definition if it is equal to the given definition read from WSO2 API Manager
+// once both are normalized, so that formatting-only changes are not reported as drift.
+// Otherwise returns the normalized definition read from WSO2 API Manager.
+func asyncAPIDefinitionValue(prior types.String, definition string) (types.String, error) {
+	normalized, err := apim.NormalizeAsyncAPI(definition)
+	if err != nil {
+		return prior, err
+	}
+	if priorNormalized, err := apim.NormalizeAsyncAPI(prior.ValueString()); err == nil && priorNormalized == normalized {
+		return prior, nil
+	}
+	return types.StringValue(normalized), nil
+}
+
+// isAsyncAPIType returns true if the given api type is one of the asyncAPITypes.
+func isAsyncAPIType(apiType string) bool {
+	for _, t := range asyncAPITypes {
+		if t == apiType {
+			return true
+		}
+	}
+	return false
+}
+
+// websubSubscriptionRequest returns the WebSub subscription configuration of the given plan.
+func websubSubscriptionRequest(plan *apiWebsubSubscriptionResourceModel) *apim.APIWebsubSubscriptionConfiguration {
+	if plan == nil {
+		return nil
+	}
+	return &apim.APIWebsubSubscriptionConfiguration{
+		Enable:           plan.Enabled.ValueBool(),
+		Secret:           plan.Secret.ValueString(),
+		SigningAlgorithm: plan.SigningAlgorithm.ValueString(),
+		SignatureHeader:  plan.SignatureHeader.ValueString(),
+	}
+}
+
+// websubSubscriptionState returns the given WebSub subscription configuration read from WSO2 API Manager.
+// A disabled configuration is only reported if it is in the prior state, and the secret is kept from the prior state
+// if WSO2 API Manager does not return it.
+func websubSubscriptionState(prior *apiWebsubSubscriptionResourceModel, config *apim.APIWebsubSubscriptionConfiguration) *apiWebsubSubscriptionResourceModel {
+	if config == nil || (prior == nil && !config.Enable) {
+		return nil
+	}
+	secret := types.StringNull()
+	if prior != nil {
+		secret = prior.Secret
+	}
+	if config.Secret != "" {
+		secret = types.StringValue(config.Secret)
+	}
+	return &apiWebsubSubscriptionResourceModel{
+		Enabled:          types.BoolValue(config.Enable),
+		Secret:           secret,
+		SigningAlgorithm: types.StringValue(config.SigningAlgorithm),
+		SignatureHeader:  types.StringValue(config.SignatureHeader),
+	}
+}
+
+// businessInformationRequest returns the business information of the given plan, empty to clear it if not set.
+func businessInformationRequest(plan *apiBusinessInformationResourceModel) *apim.APIBusinessInformation {
+	if plan == nil {
+		return &apim.APIBusinessInformation{}
+	}
+	return &apim.APIBusinessInformation{
+		BusinessOwner:       plan.BusinessOwner.ValueString(),
+		BusinessOwnerEmail:  plan.BusinessOwnerEmail.ValueString(),
+		TechnicalOwner:      plan.TechnicalOwner.ValueString(),
+		TechnicalOwnerEmail: plan.TechnicalOwnerEmail.ValueString(),
+	}
+}
+
+// businessInformationState returns the business information read from WSO2 API Manager, null if empty and not in the prior state.
+func businessInformationState(prior *apiBusinessInformationResourceModel, info *apim.APIBusinessInformation) *apiBusinessInformationResourceModel {
+	if info == nil {
+		info = &apim.APIBusinessInformation{}
+	}
+	if prior == nil && *info == (apim.APIBusinessInformation{}) {
+		return nil
+	}
+	return &apiBusinessInformationResourceModel{
+		BusinessOwner:       stringState(info.BusinessOwner),
+		BusinessOwnerEmail:  stringState(info.BusinessOwnerEmail),
+		TechnicalOwner:      stringState(info.TechnicalOwner),
+		TechnicalOwnerEmail: stringState(info.TechnicalOwnerEmail),
+	}
+}
+
+// additionalPropertiesRequest returns the custom properties of the given plan sorted by name.
+func additionalPropertiesRequest(plan map[string]apiAdditionalPropertyResourceModel) apim.APIAdditionalProperties {
+	properties := apim.APIAdditionalProperties{}
+	for name, property := range plan {
+		properties = append(properties, apim.APIAdditionalProperty{
+			Name:    name,
+			Value:   property.Value.ValueString(),
+			Display: property.Display.ValueBool(),
+		})
+	}
+	sort.Slice(properties, func(i, j int) bool { return properties[i].Name < properties[j].Name })
+	return properties
+}
+
+// additionalPropertiesState returns the custom properties read from WSO2 API Manager,
+// reported either as a list or, by some WSO2 API Manager 4.x versions, only as a map.
+func additionalPropertiesState(prior map[string]apiAdditionalPropertyResourceModel, list apim.APIAdditionalProperties, byName map[string]apim.APIAdditionalProperty) map[string]apiAdditionalPropertyResourceModel {
+	properties := list
+	if len(properties) == 0 {
+		for name, property := range byName {
+			property.Name = name
+			properties = append(properties, property)
+		}
+	}
+	if len(properties) == 0 {
+		// No custom properties are sent for an empty map, keep it as configured.
+		if prior != nil && len(prior) == 0 {
+			return prior
+		}
+		return nil
+	}
+	state := make(map[string]apiAdditionalPropertyResourceModel, len(properties))
+	for _, property := range properties {
+		state[property.Name] = apiAdditionalPropertyResourceModel{
+			Value:   types.StringValue(property.Value),
+			Display: types.BoolValue(property.Display),
+		}
+	}
+	return state
+}
+
+// wsdlDefinitionValue returns the prior definition if it is equal to the given WSDL content read from WSO2 API Manager
+// once both are normalized, so that formatting-only changes are not reported as drift.
+// Otherwise returns the content read from WSO2 API Manager, base64 encoded if it is a zip archive.
+func wsdlDefinitionValue(prior types.String, content []byte) (types.String, error) {
+	normalized, err := apim.NormalizeWSDL(content)
+	if err != nil {
+		return prior, err
+	}
+	if priorContent, err := apim.DecodeWSDL(prior.ValueString()); err == nil {
+		if priorNormalized, err := apim.NormalizeWSDL(priorContent); err == nil && priorNormalized == normalized {
+			return prior, nil
+		}
+	}
+	if apim.IsWSDLArchive(content) {
+		return types.StringValue(base64.StdEncoding.EncodeToString(content)), nil
+	}
+	return types.StringValue(string(content)), nil
+}
+
+// sortByPrior sorts the given items in the order of the given keys of the prior state,
+// followed by the items whose key is not in the prior state in their original order.
+func sortByPrior[T any](items []T, priorKeys []string, key func(T) string) {
+	index := map[string]int{}
+	for i, k := range priorKeys {
+		if _, ok := index[k]; !ok {
+			index[k] = i
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		pi, iok := index[key(items[i])]
+		pj, jok := index[key(items[j])]
+		if iok && jok {
+			return pi < pj
+		}
+		return iok && !jok
+	})
+}
+
+// graphQLComplexityRequest returns the GraphQL query complexity values of the given plan.
+func graphQLComplexityRequest(plan []apiGraphQLComplexityResourceModel) []apim.GraphQLComplexity {
+	complexity := make([]apim.GraphQLComplexity, 0, len(plan))
+	for _, c := range plan {
+		complexity = append(complexity, apim.GraphQLComplexity{
+			Type:            c.Type.ValueString(),
+			Field:           c.Field.ValueString(),
+			ComplexityValue: c.ComplexityValue.ValueInt64(),
+		})
+	}
+	return complexity
+}
+
+// graphQLComplexityState returns the given GraphQL query complexity values read from WSO2 API Manager
+// in the order of the prior state, followed by the values not in the prior state.
+func graphQLComplexityState(prior []apiGraphQLComplexityResourceModel, complexity []apim.GraphQLComplexity) []apiGraphQLComplexityResourceModel {
+	keys := make([]string, 0, len(prior))
+	for _, c := range prior {
+		keys = append(keys, c.Type.ValueString()+"."+c.Field.ValueString())
+	}
+	complexity = append([]apim.GraphQLComplexity{}, complexity...)
+	sortByPrior(complexity, keys, func(c apim.GraphQLComplexity) string { return c.Type + "." + c.Field })
+	state := make([]apiGraphQLComplexityResourceModel, 0, len(complexity))
+	for _, c := range complexity {
+		state = append(state, apiGraphQLComplexityResourceModel{
+			Type:            types.StringValue(c.Type),
+			Field:           types.StringValue(c.Field),
+			ComplexityValue: types.Int64Value(c.ComplexityValue),
+		})
+	}
+	return state
+}
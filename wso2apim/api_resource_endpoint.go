@@ -0,0 +1,656 @@
+package wso2apim
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// roundRobinAlgorithm is the default algorithm of load balanced endpoints.
+const roundRobinAlgorithm = "org.apache.synapse.endpoints.algorithms.RoundRobin"
+
+type apiEndpointConfigResourceModel struct {
+	EndpointType        types.String                             `tfsdk:"endpoint_type"`
+	SandboxEndpoints    *apiEndpointAdvancedConfigResourceModel  `tfsdk:"sandbox_endpoints"`
+	ProductionEndpoints *apiEndpointAdvancedConfigResourceModel  `tfsdk:"production_endpoints"`
+	SandboxFailovers    []apiEndpointAdvancedConfigResourceModel `tfsdk:"sandbox_failovers"`
+	ProductionFailovers []apiEndpointAdvancedConfigResourceModel `tfsdk:"production_failovers"`
+	LoadBalance         *apiEndpointLoadBalanceResourceModel     `tfsdk:"load_balance"`
+	AWSLambda           *apiEndpointAWSLambdaResourceModel       `tfsdk:"aws_lambda"`
+}
+
+type apiEndpointAdvancedConfigResourceModel struct {
+	URL      types.String                      `tfsdk:"url"`
+	Advanced *apiEndpointSettingsResourceModel `tfsdk:"advanced"`
+}
+
+type apiEndpointSettingsResourceModel struct {
+	ActionDuration     types.Int64   `tfsdk:"action_duration"`
+	ActionSelect       types.String  `tfsdk:"action_select"`
+	RetryTimeout       types.Int64   `tfsdk:"retry_timeout"`
+	RetryDelay         types.Int64   `tfsdk:"retry_delay"`
+	RetryErrorCodes    []string      `tfsdk:"retry_error_codes"`
+	SuspendDuration    types.Int64   `tfsdk:"suspend_duration"`
+	SuspendMaxDuration types.Int64   `tfsdk:"suspend_max_duration"`
+	Factor             types.Float64 `tfsdk:"factor"`
+	SuspendErrorCodes  []string      `tfsdk:"suspend_error_codes"`
+}
+
+type apiEndpointLoadBalanceResourceModel struct {
+	Algorithm           types.String                             `tfsdk:"algorithm"`
+	SessionManagement   types.String                             `tfsdk:"session_management"`
+	SessionTimeout      types.Int64                              `tfsdk:"session_timeout"`
+	SandboxEndpoints    []apiEndpointAdvancedConfigResourceModel `tfsdk:"sandbox_endpoints"`
+	ProductionEndpoints []apiEndpointAdvancedConfigResourceModel `tfsdk:"production_endpoints"`
+}
+
+type apiEndpointAWSLambdaResourceModel struct {
+	AccessMethod    types.String `tfsdk:"access_method"`
+	AccessKey       types.String `tfsdk:"access_key"`
+	SecretKey       types.String `tfsdk:"secret_key"`
+	Region          types.String `tfsdk:"region"`
+	AssumeRole      types.Bool   `tfsdk:"assume_role"`
+	RoleArn         types.String `tfsdk:"role_arn"`
+	RoleSessionName types.String `tfsdk:"role_session_name"`
+	RoleRegion      types.String `tfsdk:"role_region"`
+}
+
+type apiEndpointSecurityResourceModel struct {
+	Production *apiEnvironmentEndpointSecurityResourceModel `tfsdk:"production"`
+	Sandbox    *apiEnvironmentEndpointSecurityResourceModel `tfsdk:"sandbox"`
+}
+
+type apiEnvironmentEndpointSecurityResourceModel struct {
+	Type                 types.String      `tfsdk:"type"`
+	Username             types.String      `tfsdk:"username"`
+	Password             types.String      `tfsdk:"password"`
+	GrantType            types.String      `tfsdk:"grant_type"`
+	TokenURL             types.String      `tfsdk:"token_url"`
+	ClientID             types.String      `tfsdk:"client_id"`
+	ClientSecret         types.String      `tfsdk:"client_secret"`
+	CustomParameters     map[string]string `tfsdk:"custom_parameters"`
+	APIKeyIdentifier     types.String      `tfsdk:"api_key_identifier"`
+	APIKeyIdentifierType types.String      `tfsdk:"api_key_identifier_type"`
+	APIKeyValue          types.String      `tfsdk:"api_key_value"`
+}
+
+// endpointAttribute returns the schema of an endpoint of an environment.
+func endpointAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: description,
+		Optional:    true,
+		Attributes:  endpointObject().Attributes,
+	}
+}
+
+// endpointObject returns the schema of an endpoint, its URL and advanced settings.
+func endpointObject() schema.NestedAttributeObject {
+	return schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				Description: "Endpoint URL.",
+				Optional:    true,
+			},
+			"advanced": schema.SingleNestedAttribute{
+				Description: "Timeout, retry and suspension settings of the endpoint, the defaults of the gateway when not set.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"action_duration": schema.Int64Attribute{
+						Description: "Timeout of the requests to the endpoint in milliseconds.",
+						Optional:    true,
+					},
+					"action_select": schema.StringAttribute{
+						Description: "Action on timeout, `fault` to fail the request and suspend the endpoint, or `discard` to drop the response.",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("fault", "discard"),
+						},
+					},
+					"retry_timeout": schema.Int64Attribute{
+						Description: "Number of retries on timeout before the endpoint is suspended.",
+						Optional:    true,
+					},
+					"retry_delay": schema.Int64Attribute{
+						Description: "Delay between the retries in milliseconds.",
+						Optional:    true,
+					},
+					"retry_error_codes": schema.ListAttribute{
+						Description: "Error codes the requests are retried on before the endpoint is suspended, e.g. `101504` for a connection timeout.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"suspend_duration": schema.Int64Attribute{
+						Description: "Initial duration the endpoint is suspended for in milliseconds.",
+						Optional:    true,
+					},
+					"suspend_max_duration": schema.Int64Attribute{
+						Description: "Maximum duration the endpoint is suspended for in milliseconds.",
+						Optional:    true,
+					},
+					"factor": schema.Float64Attribute{
+						Description: "Factor the suspend duration is multiplied by on each successive failure.",
+						Optional:    true,
+					},
+					"suspend_error_codes": schema.ListAttribute{
+						Description: "Error codes the endpoint is suspended on.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// environmentEndpointSecurityAttribute returns the schema of the credentials of the endpoints of an environment.
+func environmentEndpointSecurityAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: description,
+		Optional:    true,
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Description: "Type of the endpoint security, one of `BASIC`, `DIGEST`, `OAUTH` or `APIKEY`.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("BASIC", "DIGEST", "OAUTH", "APIKEY"),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "Username of `BASIC` and `DIGEST` endpoint security, or of the `PASSWORD` grant of `OAUTH` endpoint security.",
+				Optional:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Password of `BASIC` and `DIGEST` endpoint security, or of the `PASSWORD` grant of `OAUTH` endpoint security.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"grant_type": schema.StringAttribute{
+				Description: "Grant type of `OAUTH` endpoint security, `CLIENT_CREDENTIALS` or `PASSWORD`.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("CLIENT_CREDENTIALS", "PASSWORD"),
+				},
+			},
+			"token_url": schema.StringAttribute{
+				Description: "Token endpoint URL of `OAUTH` endpoint security.",
+				Optional:    true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "Client ID of `OAUTH` endpoint security.",
+				Optional:    true,
+			},
+			"client_secret": schema.StringAttribute{
+				Description: "Client secret of `OAUTH` endpoint security.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"custom_parameters": schema.MapAttribute{
+				Description: "Additional parameters of the token requests of `OAUTH` endpoint security.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"api_key_identifier": schema.StringAttribute{
+				Description: "Name of the header or query parameter carrying the API key of `APIKEY` endpoint security.",
+				Optional:    true,
+			},
+			"api_key_identifier_type": schema.StringAttribute{
+				Description: "Where the API key of `APIKEY` endpoint security is sent, `HEADER` or `QUERY_PARAMETER`.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("HEADER", "QUERY_PARAMETER"),
+				},
+			},
+			"api_key_value": schema.StringAttribute{
+				Description: "API key of `APIKEY` endpoint security.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// validateEndpointConfig validates that the load balanced endpoints, failover endpoints and AWS Lambda credentials
+// are only set for their endpoint type, and that the credentials required by the AWS Lambda access method are set.
+func validateEndpointConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var object types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("endpoint_config"), &object)...)
+	if object.IsNull() || object.IsUnknown() {
+		return
+	}
+	var config apiEndpointConfigResourceModel
+	resp.Diagnostics.Append(object.As(ctx, &config, basetypes.ObjectAsOptions{UnhandledUnknownAsEmpty: true})...)
+	if resp.Diagnostics.HasError() || config.EndpointType.IsUnknown() {
+		return
+	}
+
+	p := path.Root("endpoint_config")
+	endpointType := config.EndpointType.ValueString()
+	for _, a := range []struct {
+		name, endpointType string
+		set                bool
+	}{
+		{"load_balance", apim.EndpointTypeLoadBalance, config.LoadBalance != nil},
+		{"production_failovers", apim.EndpointTypeFailover, config.ProductionFailovers != nil},
+		{"sandbox_failovers", apim.EndpointTypeFailover, config.SandboxFailovers != nil},
+		{"aws_lambda", apim.EndpointTypeAWSLambda, config.AWSLambda != nil},
+	} {
+		if a.set && endpointType != a.endpointType {
+			resp.Diagnostics.AddAttributeError(
+				p.AtName(a.name),
+				"Invalid endpoint configuration",
+				a.name+" can only be set for the "+a.endpointType+" endpoint type, got: "+endpointType,
+			)
+		}
+	}
+	if endpointType == apim.EndpointTypeLoadBalance {
+		if config.LoadBalance == nil {
+			resp.Diagnostics.AddAttributeError(
+				p.AtName("load_balance"),
+				"Missing load balanced endpoints",
+				"load_balance must be set for the load_balance endpoint type.",
+			)
+		}
+		for _, a := range []struct {
+			name string
+			set  bool
+		}{
+			{"production_endpoints", config.ProductionEndpoints != nil},
+			{"sandbox_endpoints", config.SandboxEndpoints != nil},
+		} {
+			if a.set {
+				resp.Diagnostics.AddAttributeError(
+					p.AtName(a.name),
+					"Invalid endpoint configuration",
+					a.name+" cannot be set for the load_balance endpoint type, the endpoints are set in load_balance.",
+				)
+			}
+		}
+	}
+
+	lambda := config.AWSLambda
+	if lambda == nil {
+		return
+	}
+	type attribute struct {
+		name, reason string
+		value        types.String
+	}
+	var required []attribute
+	if lambda.AccessMethod.ValueString() == "stored" {
+		required = append(required,
+			attribute{"access_key", "stored credentials", lambda.AccessKey},
+			attribute{"secret_key", "stored credentials", lambda.SecretKey},
+			attribute{"region", "stored credentials", lambda.Region},
+		)
+	}
+	if lambda.AssumeRole.ValueBool() {
+		required = append(required,
+			attribute{"role_arn", "assuming a role", lambda.RoleArn},
+			attribute{"role_session_name", "assuming a role", lambda.RoleSessionName},
+			attribute{"role_region", "assuming a role", lambda.RoleRegion},
+		)
+	}
+	for _, a := range required {
+		if a.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				p.AtName("aws_lambda").AtName(a.name),
+				"Missing AWS Lambda attribute",
+				a.name+" must be set for "+a.reason+".",
+			)
+		}
+	}
+}
+
+// validateEndpointSecurity validates that the credentials required by the type of the endpoint security at the given path are set.
+func validateEndpointSecurity(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse, p path.Path) {
+	var object types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, p, &object)...)
+	if object.IsNull() || object.IsUnknown() {
+		return
+	}
+	var security apiEnvironmentEndpointSecurityResourceModel
+	resp.Diagnostics.Append(object.As(ctx, &security, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	type attribute struct {
+		name  string
+		value types.String
+	}
+	username, password := attribute{"username", security.Username}, attribute{"password", security.Password}
+	var required []attribute
+	switch security.Type.ValueString() {
+	case "BASIC", "DIGEST":
+		required = []attribute{username, password}
+	case "OAUTH":
+		required = []attribute{
+			{"grant_type", security.GrantType},
+			{"token_url", security.TokenURL},
+			{"client_id", security.ClientID},
+			{"client_secret", security.ClientSecret},
+		}
+		if security.GrantType.ValueString() == "PASSWORD" {
+			required = append(required, username, password)
+		}
+	case "APIKEY":
+		required = []attribute{{"api_key_identifier", security.APIKeyIdentifier}, {"api_key_value", security.APIKeyValue}}
+	}
+	for _, a := range required {
+		if a.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				p.AtName(a.name),
+				"Missing endpoint security attribute",
+				a.name+" must be set for "+security.Type.ValueString()+" endpoint security.",
+			)
+		}
+	}
+}
+
+// endpointConfigRequest returns the endpoint configuration of the given plan.
+func endpointConfigRequest(plan *apiResourceModel) *apim.APIEndpointConfig {
+	if plan.EndpointConfig == nil {
+		return nil
+	}
+	config := &apim.APIEndpointConfig{
+		EndpointType:        plan.EndpointConfig.EndpointType.ValueString(),
+		SandboxEndpoints:    endpointRequest(plan.EndpointConfig.SandboxEndpoints),
+		ProductionEndpoints: endpointRequest(plan.EndpointConfig.ProductionEndpoints),
+		SandboxFailovers:    endpointsRequest(plan.EndpointConfig.SandboxFailovers),
+		ProductionFailovers: endpointsRequest(plan.EndpointConfig.ProductionFailovers),
+		EndpointSecurity:    endpointSecurityRequest(plan.EndpointSecurity),
+	}
+	if config.EndpointType == apim.EndpointTypeInline {
+		config.ImplementationStatus = "prototyped"
+	}
+	if lb := plan.EndpointConfig.LoadBalance; lb != nil {
+		config.AlgoClassName = lb.Algorithm.ValueString()
+		config.AlgoCombo = lb.Algorithm.ValueString()
+		config.SessionManagement = lb.SessionManagement.ValueString()
+		if !lb.SessionTimeout.IsNull() {
+			config.SessionTimeOut = strconv.FormatInt(lb.SessionTimeout.ValueInt64(), 10)
+		}
+		config.SandboxLoadBalanceEndpoints = endpointsRequest(lb.SandboxEndpoints)
+		config.ProductionLoadBalanceEndpoints = endpointsRequest(lb.ProductionEndpoints)
+	}
+	if lambda := plan.EndpointConfig.AWSLambda; lambda != nil {
+		config.AccessMethod = lambda.AccessMethod.ValueString()
+		config.AmznAccessKey = lambda.AccessKey.ValueString()
+		config.AmznSecretKey = lambda.SecretKey.ValueString()
+		config.AmznRegion = lambda.Region.ValueString()
+		config.AssumeRole = lambda.AssumeRole.ValueBool()
+		config.AmznRoleArn = lambda.RoleArn.ValueString()
+		config.AmznRoleSessionName = lambda.RoleSessionName.ValueString()
+		config.AmznRoleRegion = lambda.RoleRegion.ValueString()
+	}
+	return config
+}
+
+// endpointRequest returns the endpoint of the given plan.
+func endpointRequest(plan *apiEndpointAdvancedConfigResourceModel) *apim.APIEndpointAdvancedConfig {
+	if plan == nil {
+		return nil
+	}
+	endpoint := &apim.APIEndpointAdvancedConfig{URL: plan.URL.ValueString()}
+	if settings := plan.Advanced; settings != nil {
+		format := func(v types.Int64) string {
+			if v.IsNull() {
+				return ""
+			}
+			return strconv.FormatInt(v.ValueInt64(), 10)
+		}
+		endpoint.Config = &apim.EndpointSettings{
+			ActionDuration:     format(settings.ActionDuration),
+			ActionSelect:       settings.ActionSelect.ValueString(),
+			RetryTimeOut:       format(settings.RetryTimeout),
+			RetryDelay:         format(settings.RetryDelay),
+			RetryErrorCodes:    settings.RetryErrorCodes,
+			SuspendDuration:    format(settings.SuspendDuration),
+			SuspendMaxDuration: format(settings.SuspendMaxDuration),
+			SuspendErrorCodes:  settings.SuspendErrorCodes,
+		}
+		if !settings.Factor.IsNull() {
+			endpoint.Config.Factor = strconv.FormatFloat(settings.Factor.ValueFloat64(), 'f', -1, 64)
+		}
+	}
+	return endpoint
+}
+
+// endpointsRequest returns the load balanced or failover endpoints of the given plan.
+func endpointsRequest(plan []apiEndpointAdvancedConfigResourceModel) []apim.APIEndpointAdvancedConfig {
+	var endpoints []apim.APIEndpointAdvancedConfig
+	for i := range plan {
+		endpoints = append(endpoints, *endpointRequest(&plan[i]))
+	}
+	return endpoints
+}
+
+// endpointConfigState returns the endpoint configuration read from WSO2 API Manager.
+// The secret key of AWS Lambda endpoints, not returned by WSO2 API Manager, is kept from the prior state.
+func endpointConfigState(prior *apiEndpointConfigResourceModel, config *apim.APIEndpointConfig) *apiEndpointConfigResourceModel {
+	if config == nil {
+		return nil
+	}
+	if prior == nil {
+		prior = &apiEndpointConfigResourceModel{}
+	}
+	state := &apiEndpointConfigResourceModel{
+		EndpointType:        types.StringValue(config.EndpointType),
+		SandboxEndpoints:    endpointState(prior.SandboxEndpoints, config.SandboxEndpoints),
+		ProductionEndpoints: endpointState(prior.ProductionEndpoints, config.ProductionEndpoints),
+		SandboxFailovers:    endpointsState(prior.SandboxFailovers, config.SandboxFailovers),
+		ProductionFailovers: endpointsState(prior.ProductionFailovers, config.ProductionFailovers),
+	}
+	if config.EndpointType == apim.EndpointTypeLoadBalance {
+		priorLoadBalance := prior.LoadBalance
+		if priorLoadBalance == nil {
+			priorLoadBalance = &apiEndpointLoadBalanceResourceModel{}
+		}
+		state.LoadBalance = &apiEndpointLoadBalanceResourceModel{
+			Algorithm:           types.StringValue(config.AlgoClassName),
+			SessionManagement:   types.StringValue(config.SessionManagement),
+			SessionTimeout:      int64State(config.SessionTimeOut),
+			SandboxEndpoints:    endpointsState(priorLoadBalance.SandboxEndpoints, config.SandboxLoadBalanceEndpoints),
+			ProductionEndpoints: endpointsState(priorLoadBalance.ProductionEndpoints, config.ProductionLoadBalanceEndpoints),
+		}
+		if config.AlgoClassName == "" {
+			state.LoadBalance.Algorithm = types.StringValue(roundRobinAlgorithm)
+		}
+		if config.SessionManagement == "" {
+			state.LoadBalance.SessionManagement = types.StringValue("none")
+		}
+	}
+	if config.EndpointType == apim.EndpointTypeAWSLambda && config.AccessMethod != "" {
+		secretKey := types.StringNull()
+		if prior.AWSLambda != nil {
+			secretKey = prior.AWSLambda.SecretKey
+		}
+		state.AWSLambda = &apiEndpointAWSLambdaResourceModel{
+			AccessMethod:    types.StringValue(config.AccessMethod),
+			AccessKey:       stringState(config.AmznAccessKey),
+			SecretKey:       secretKey,
+			Region:          stringState(config.AmznRegion),
+			AssumeRole:      types.BoolValue(config.AssumeRole),
+			RoleArn:         stringState(config.AmznRoleArn),
+			RoleSessionName: stringState(config.AmznRoleSessionName),
+			RoleRegion:      stringState(config.AmznRoleRegion),
+		}
+	}
+	return state
+}
+
+// endpointState returns the endpoint read from WSO2 API Manager.
+func endpointState(prior *apiEndpointAdvancedConfigResourceModel, endpoint *apim.APIEndpointAdvancedConfig) *apiEndpointAdvancedConfigResourceModel {
+	if endpoint == nil {
+		return nil
+	}
+	if prior == nil {
+		prior = &apiEndpointAdvancedConfigResourceModel{}
+	}
+	state := &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue(endpoint.URL)}
+	if settings := endpoint.Config; settings != nil && (prior.Advanced != nil || !reflect.DeepEqual(*settings, apim.EndpointSettings{})) {
+		priorSettings := prior.Advanced
+		if priorSettings == nil {
+			priorSettings = &apiEndpointSettingsResourceModel{}
+		}
+		state.Advanced = &apiEndpointSettingsResourceModel{
+			ActionDuration:     int64State(settings.ActionDuration),
+			ActionSelect:       stringState(settings.ActionSelect),
+			RetryTimeout:       int64State(settings.RetryTimeOut),
+			RetryDelay:         int64State(settings.RetryDelay),
+			RetryErrorCodes:    errorCodesState(priorSettings.RetryErrorCodes, settings.RetryErrorCodes),
+			SuspendDuration:    int64State(settings.SuspendDuration),
+			SuspendMaxDuration: int64State(settings.SuspendMaxDuration),
+			Factor:             types.Float64Null(),
+			SuspendErrorCodes:  errorCodesState(priorSettings.SuspendErrorCodes, settings.SuspendErrorCodes),
+		}
+		if factor, err := strconv.ParseFloat(settings.Factor, 64); err == nil {
+			state.Advanced.Factor = types.Float64Value(factor)
+		}
+	}
+	return state
+}
+
+// endpointsState returns the load balanced or failover endpoints read from WSO2 API Manager.
+func endpointsState(prior []apiEndpointAdvancedConfigResourceModel, endpoints []apim.APIEndpointAdvancedConfig) []apiEndpointAdvancedConfigResourceModel {
+	if len(endpoints) == 0 {
+		// An empty list is not sent to WSO2 API Manager, keep it as configured.
+		if prior != nil && len(prior) == 0 {
+			return prior
+		}
+		return nil
+	}
+	state := make([]apiEndpointAdvancedConfigResourceModel, len(endpoints))
+	for i := range endpoints {
+		var priorEndpoint *apiEndpointAdvancedConfigResourceModel
+		if i < len(prior) {
+			priorEndpoint = &prior[i]
+		}
+		state[i] = *endpointState(priorEndpoint, &endpoints[i])
+	}
+	return state
+}
+
+// errorCodesState returns the error codes read from WSO2 API Manager, keeping an empty list of the prior state.
+func errorCodesState(prior []string, codes []string) []string {
+	if len(codes) == 0 && prior != nil && len(prior) == 0 {
+		return prior
+	}
+	return codes
+}
+
+// stringState returns the given string value, null if empty.
+func stringState(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// int64State returns the given integer value, null if empty or not an integer.
+func int64State(value string) types.Int64 {
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(v)
+}
+
+// endpointSecurityRequest returns the endpoint security of the given plan.
+func endpointSecurityRequest(plan *apiEndpointSecurityResourceModel) *apim.APIEndpointSecurityConfig {
+	if plan == nil {
+		return nil
+	}
+	environment := func(plan *apiEnvironmentEndpointSecurityResourceModel) *apim.EnvironmentEndpointSecurity {
+		if plan == nil {
+			return nil
+		}
+		security := &apim.EnvironmentEndpointSecurity{
+			Enabled:              true,
+			Type:                 plan.Type.ValueString(),
+			Username:             plan.Username.ValueString(),
+			Password:             plan.Password.ValueString(),
+			GrantType:            plan.GrantType.ValueString(),
+			TokenURL:             plan.TokenURL.ValueString(),
+			ClientID:             plan.ClientID.ValueString(),
+			ClientSecret:         plan.ClientSecret.ValueString(),
+			APIKeyIdentifier:     plan.APIKeyIdentifier.ValueString(),
+			APIKeyIdentifierType: plan.APIKeyIdentifierType.ValueString(),
+			APIKeyValue:          plan.APIKeyValue.ValueString(),
+		}
+		if len(plan.CustomParameters) > 0 {
+			security.CustomParameters = plan.CustomParameters
+		}
+		return security
+	}
+	return &apim.APIEndpointSecurityConfig{
+		Production: environment(plan.Production),
+		Sandbox:    environment(plan.Sandbox),
+	}
+}
+
+// endpointSecurityState returns the endpoint security of the given endpoint configuration read from WSO2 API Manager.
+// The secrets and custom parameters, masked or not returned by WSO2 API Manager, are kept from the prior state.
+func endpointSecurityState(prior *apiEndpointSecurityResourceModel, config *apim.APIEndpointConfig) *apiEndpointSecurityResourceModel {
+	if config == nil || config.EndpointSecurity == nil {
+		return nil
+	}
+	if prior == nil {
+		prior = &apiEndpointSecurityResourceModel{}
+	}
+	environment := func(prior *apiEnvironmentEndpointSecurityResourceModel, security *apim.EnvironmentEndpointSecurity) *apiEnvironmentEndpointSecurityResourceModel {
+		if security == nil || !security.Enabled {
+			return nil
+		}
+		if prior == nil {
+			prior = &apiEnvironmentEndpointSecurityResourceModel{}
+		}
+		// The values not returned by WSO2 API Manager are kept from the prior state.
+		optional := func(prior types.String, value string) types.String {
+			if value != "" {
+				return types.StringValue(value)
+			}
+			if prior.IsUnknown() {
+				return types.StringNull()
+			}
+			return prior
+		}
+		return &apiEnvironmentEndpointSecurityResourceModel{
+			Type:                 types.StringValue(security.Type),
+			Username:             optional(prior.Username, security.Username),
+			Password:             prior.Password,
+			GrantType:            optional(prior.GrantType, security.GrantType),
+			TokenURL:             optional(prior.TokenURL, security.TokenURL),
+			ClientID:             optional(prior.ClientID, security.ClientID),
+			ClientSecret:         prior.ClientSecret,
+			CustomParameters:     prior.CustomParameters,
+			APIKeyIdentifier:     optional(prior.APIKeyIdentifier, security.APIKeyIdentifier),
+			APIKeyIdentifierType: optional(prior.APIKeyIdentifierType, security.APIKeyIdentifierType),
+			APIKeyValue:          prior.APIKeyValue,
+		}
+	}
+	state := &apiEndpointSecurityResourceModel{
+		Production: environment(prior.Production, config.EndpointSecurity.Production),
+		Sandbox:    environment(prior.Sandbox, config.EndpointSecurity.Sandbox),
+	}
+	if state.Production == nil && state.Sandbox == nil {
+		return nil
+	}
+	return state
+}
@@ -0,0 +1,314 @@
+package wso2apim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEndpointConfigRequest(t *testing.T) {
+	cases := []struct {
+		name string
+		plan *apiResourceModel
+		want *apim.APIEndpointConfig
+	}{
+		{
+			name: "not set",
+			plan: &apiResourceModel{},
+			want: nil,
+		},
+		{
+			name: "http endpoints",
+			plan: &apiResourceModel{EndpointConfig: &apiEndpointConfigResourceModel{
+				EndpointType:        types.StringValue("http"),
+				ProductionEndpoints: &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue("https://prod.example.com")},
+				SandboxEndpoints: &apiEndpointAdvancedConfigResourceModel{
+					URL: types.StringValue("https://sandbox.example.com"),
+					Advanced: &apiEndpointSettingsResourceModel{
+						ActionDuration:  types.Int64Value(30000),
+						ActionSelect:    types.StringValue("fault"),
+						RetryTimeout:    types.Int64Null(),
+						RetryDelay:      types.Int64Value(0),
+						RetryErrorCodes: []string{"101503"},
+						Factor:          types.Float64Value(1.5),
+					},
+				},
+			}},
+			want: &apim.APIEndpointConfig{
+				EndpointType:        "http",
+				ProductionEndpoints: &apim.APIEndpointAdvancedConfig{URL: "https://prod.example.com"},
+				SandboxEndpoints: &apim.APIEndpointAdvancedConfig{
+					URL: "https://sandbox.example.com",
+					Config: &apim.EndpointSettings{
+						ActionDuration:  "30000",
+						ActionSelect:    "fault",
+						RetryDelay:      "0",
+						RetryErrorCodes: []string{"101503"},
+						Factor:          "1.5",
+					},
+				},
+			},
+		},
+		{
+			name: "prototyped",
+			plan: &apiResourceModel{EndpointConfig: &apiEndpointConfigResourceModel{EndpointType: types.StringValue(apim.EndpointTypeInline)}},
+			want: &apim.APIEndpointConfig{EndpointType: apim.EndpointTypeInline, ImplementationStatus: "prototyped"},
+		},
+		{
+			name: "load balanced",
+			plan: &apiResourceModel{EndpointConfig: &apiEndpointConfigResourceModel{
+				EndpointType: types.StringValue(apim.EndpointTypeLoadBalance),
+				LoadBalance: &apiEndpointLoadBalanceResourceModel{
+					Algorithm:         types.StringValue(roundRobinAlgorithm),
+					SessionManagement: types.StringValue("transport"),
+					SessionTimeout:    types.Int64Value(60),
+					ProductionEndpoints: []apiEndpointAdvancedConfigResourceModel{
+						{URL: types.StringValue("https://prod1.example.com")},
+						{URL: types.StringValue("https://prod2.example.com")},
+					},
+				},
+			}},
+			want: &apim.APIEndpointConfig{
+				EndpointType:      apim.EndpointTypeLoadBalance,
+				AlgoClassName:     roundRobinAlgorithm,
+				AlgoCombo:         roundRobinAlgorithm,
+				SessionManagement: "transport",
+				SessionTimeOut:    "60",
+				ProductionLoadBalanceEndpoints: []apim.APIEndpointAdvancedConfig{
+					{URL: "https://prod1.example.com"},
+					{URL: "https://prod2.example.com"},
+				},
+			},
+		},
+		{
+			name: "aws lambda",
+			plan: &apiResourceModel{EndpointConfig: &apiEndpointConfigResourceModel{
+				EndpointType: types.StringValue(apim.EndpointTypeAWSLambda),
+				AWSLambda: &apiEndpointAWSLambdaResourceModel{
+					AccessMethod: types.StringValue("stored"),
+					AccessKey:    types.StringValue("key"),
+					SecretKey:    types.StringValue("secret"),
+					Region:       types.StringValue("eu-west-1"),
+					AssumeRole:   types.BoolValue(false),
+				},
+			}},
+			want: &apim.APIEndpointConfig{
+				EndpointType:  apim.EndpointTypeAWSLambda,
+				AccessMethod:  "stored",
+				AmznAccessKey: "key",
+				AmznSecretKey: "secret",
+				AmznRegion:    "eu-west-1",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := endpointConfigRequest(c.plan); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestEndpointConfigState(t *testing.T) {
+	cases := []struct {
+		name   string
+		prior  *apiEndpointConfigResourceModel
+		config *apim.APIEndpointConfig
+		want   *apiEndpointConfigResourceModel
+	}{
+		{
+			name:   "not set",
+			config: nil,
+			want:   nil,
+		},
+		{
+			name: "http endpoints",
+			config: &apim.APIEndpointConfig{
+				EndpointType:        "http",
+				ProductionEndpoints: &apim.APIEndpointAdvancedConfig{URL: "https://prod.example.com", Config: &apim.EndpointSettings{}},
+				SandboxEndpoints: &apim.APIEndpointAdvancedConfig{
+					URL:    "https://sandbox.example.com",
+					Config: &apim.EndpointSettings{ActionDuration: "30000", ActionSelect: "fault", Factor: "1.5"},
+				},
+			},
+			want: &apiEndpointConfigResourceModel{
+				EndpointType:        types.StringValue("http"),
+				ProductionEndpoints: &apiEndpointAdvancedConfigResourceModel{URL: types.StringValue("https://prod.example.com")},
+				SandboxEndpoints: &apiEndpointAdvancedConfigResourceModel{
+					URL: types.StringValue("https://sandbox.example.com"),
+					Advanced: &apiEndpointSettingsResourceModel{
+						ActionDuration:     types.Int64Value(30000),
+						ActionSelect:       types.StringValue("fault"),
+						RetryTimeout:       types.Int64Null(),
+						RetryDelay:         types.Int64Null(),
+						SuspendDuration:    types.Int64Null(),
+						SuspendMaxDuration: types.Int64Null(),
+						Factor:             types.Float64Value(1.5),
+					},
+				},
+			},
+		},
+		{
+			name: "empty failovers kept from the prior state",
+			prior: &apiEndpointConfigResourceModel{
+				ProductionFailovers: []apiEndpointAdvancedConfigResourceModel{},
+			},
+			config: &apim.APIEndpointConfig{EndpointType: apim.EndpointTypeFailover},
+			want: &apiEndpointConfigResourceModel{
+				EndpointType:        types.StringValue(apim.EndpointTypeFailover),
+				ProductionFailovers: []apiEndpointAdvancedConfigResourceModel{},
+			},
+		},
+		{
+			name:   "load balanced defaults",
+			config: &apim.APIEndpointConfig{EndpointType: apim.EndpointTypeLoadBalance},
+			want: &apiEndpointConfigResourceModel{
+				EndpointType: types.StringValue(apim.EndpointTypeLoadBalance),
+				LoadBalance: &apiEndpointLoadBalanceResourceModel{
+					Algorithm:         types.StringValue(roundRobinAlgorithm),
+					SessionManagement: types.StringValue("none"),
+					SessionTimeout:    types.Int64Null(),
+				},
+			},
+		},
+		{
+			name: "aws lambda secret key kept from the prior state",
+			prior: &apiEndpointConfigResourceModel{
+				AWSLambda: &apiEndpointAWSLambdaResourceModel{SecretKey: types.StringValue("secret")},
+			},
+			config: &apim.APIEndpointConfig{
+				EndpointType:  apim.EndpointTypeAWSLambda,
+				AccessMethod:  "stored",
+				AmznAccessKey: "key",
+			},
+			want: &apiEndpointConfigResourceModel{
+				EndpointType: types.StringValue(apim.EndpointTypeAWSLambda),
+				AWSLambda: &apiEndpointAWSLambdaResourceModel{
+					AccessMethod:    types.StringValue("stored"),
+					AccessKey:       types.StringValue("key"),
+					SecretKey:       types.StringValue("secret"),
+					Region:          types.StringNull(),
+					AssumeRole:      types.BoolValue(false),
+					RoleArn:         types.StringNull(),
+					RoleSessionName: types.StringNull(),
+					RoleRegion:      types.StringNull(),
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := endpointConfigState(c.prior, c.config); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestEndpointSecurityRequest(t *testing.T) {
+	cases := []struct {
+		name string
+		plan *apiEndpointSecurityResourceModel
+		want *apim.APIEndpointSecurityConfig
+	}{
+		{
+			name: "not set",
+			plan: nil,
+			want: nil,
+		},
+		{
+			name: "production only",
+			plan: &apiEndpointSecurityResourceModel{
+				Production: &apiEnvironmentEndpointSecurityResourceModel{
+					Type:     types.StringValue("BASIC"),
+					Username: types.StringValue("admin"),
+					Password: types.StringValue("admin"),
+				},
+			},
+			want: &apim.APIEndpointSecurityConfig{
+				Production: &apim.EnvironmentEndpointSecurity{Enabled: true, Type: "BASIC", Username: "admin", Password: "admin"},
+			},
+		},
+		{
+			name: "custom parameters",
+			plan: &apiEndpointSecurityResourceModel{
+				Sandbox: &apiEnvironmentEndpointSecurityResourceModel{
+					Type:             types.StringValue("OAUTH"),
+					GrantType:        types.StringValue("CLIENT_CREDENTIALS"),
+					CustomParameters: map[string]string{"audience": "api"},
+				},
+			},
+			want: &apim.APIEndpointSecurityConfig{
+				Sandbox: &apim.EnvironmentEndpointSecurity{
+					Enabled:          true,
+					Type:             "OAUTH",
+					GrantType:        "CLIENT_CREDENTIALS",
+					CustomParameters: map[string]string{"audience": "api"},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := endpointSecurityRequest(c.plan); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestEndpointSecurityState(t *testing.T) {
+	cases := []struct {
+		name   string
+		prior  *apiEndpointSecurityResourceModel
+		config *apim.APIEndpointConfig
+		want   *apiEndpointSecurityResourceModel
+	}{
+		{
+			name:   "not set",
+			config: &apim.APIEndpointConfig{},
+			want:   nil,
+		},
+		{
+			name: "disabled",
+			config: &apim.APIEndpointConfig{EndpointSecurity: &apim.APIEndpointSecurityConfig{
+				Production: &apim.EnvironmentEndpointSecurity{Type: "BASIC"},
+			}},
+			want: nil,
+		},
+		{
+			name: "secrets kept from the prior state",
+			prior: &apiEndpointSecurityResourceModel{
+				Production: &apiEnvironmentEndpointSecurityResourceModel{
+					Password:         types.StringValue("secret"),
+					ClientSecret:     types.StringNull(),
+					APIKeyValue:      types.StringNull(),
+					CustomParameters: map[string]string{"audience": "api"},
+				},
+			},
+			config: &apim.APIEndpointConfig{EndpointSecurity: &apim.APIEndpointSecurityConfig{
+				Production: &apim.EnvironmentEndpointSecurity{Enabled: true, Type: "BASIC", Username: "admin", Password: "******"},
+			}},
+			want: &apiEndpointSecurityResourceModel{
+				Production: &apiEnvironmentEndpointSecurityResourceModel{
+					Type:             types.StringValue("BASIC"),
+					Username:         types.StringValue("admin"),
+					Password:         types.StringValue("secret"),
+					ClientSecret:     types.StringNull(),
+					CustomParameters: map[string]string{"audience": "api"},
+					APIKeyValue:      types.StringNull(),
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := endpointSecurityState(c.prior, c.config); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
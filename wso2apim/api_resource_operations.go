@@ -0,0 +1,176 @@
+package wso2apim
+
+import (
+	"fmt"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type apiOperationResourceModel struct {
+	// ID     types.String `tfsdk:"id"`
+	Target            types.String                       `tfsdk:"target"`
+	Verb              types.String                       `tfsdk:"verb"`
+	AuthType          types.String                       `tfsdk:"auth_type"`
+	ThrottlingPolicy  types.String                       `tfsdk:"throttling_policy"`
+	Scopes            []string                           `tfsdk:"scopes"`
+	OperationPolicies *apiOperationPoliciesResourceModel `tfsdk:"operation_policies"`
+}
+
+type apiOperationPoliciesResourceModel struct {
+	Request  []apiOperationPolicyResourceModel `tfsdk:"request"`
+	Response []apiOperationPolicyResourceModel `tfsdk:"response"`
+	Fault    []apiOperationPolicyResourceModel `tfsdk:"fault"`
+}
+
+type apiOperationPolicyResourceModel struct {
+	Name       types.String      `tfsdk:"name"`
+	Version    types.String      `tfsdk:"version"`
+	Parameters map[string]string `tfsdk:"parameters"`
+}
+
+// operationPolicyListAttribute returns the schema of the operation policies applied to a flow of an operation.
+func operationPolicyListAttribute(description string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: description,
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Description: "Name of the operation policy.",
+					Required:    true,
+				},
+				"version": schema.StringAttribute{
+					Description: "Version of the operation policy. Defaults to `v1`.",
+					Optional:    true,
+					Computed:    true,
+					Default:     stringdefault.StaticString("v1"),
+				},
+				"parameters": schema.MapAttribute{
+					Description: "Parameters of the operation policy.",
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+// apiOperationsRequest returns the operations of the given plan.
+func apiOperationsRequest(plan []apiOperationResourceModel) []apim.APIOperation {
+	var operations []apim.APIOperation
+	for _, operation := range plan {
+		var policies *apim.APIOperationPolicies
+		if operation.OperationPolicies != nil {
+			policies = &apim.APIOperationPolicies{
+				Request:  operationPoliciesRequest(operation.OperationPolicies.Request),
+				Response: operationPoliciesRequest(operation.OperationPolicies.Response),
+				Fault:    operationPoliciesRequest(operation.OperationPolicies.Fault),
+			}
+		}
+		operations = append(operations, apim.APIOperation{
+			// ID:     operation.ID.ValueString(),
+			Target:            operation.Target.ValueString(),
+			Verb:              operation.Verb.ValueString(),
+			AuthType:          operation.AuthType.ValueString(),
+			ThrottlingPolicy:  operation.ThrottlingPolicy.ValueString(),
+			Scopes:            operation.Scopes,
+			OperationPolicies: policies,
+		})
+	}
+	return operations
+}
+
+// operationPoliciesRequest returns the operation policies of the given plan.
+func operationPoliciesRequest(plan []apiOperationPolicyResourceModel) []apim.OperationPolicy {
+	policies := make([]apim.OperationPolicy, 0, len(plan))
+	for _, policy := range plan {
+		var parameters map[string]any
+		if len(policy.Parameters) > 0 {
+			parameters = make(map[string]any, len(policy.Parameters))
+			for name, value := range policy.Parameters {
+				parameters[name] = value
+			}
+		}
+		policies = append(policies, apim.OperationPolicy{
+			PolicyName:    policy.Name.ValueString(),
+			PolicyVersion: policy.Version.ValueString(),
+			Parameters:    parameters,
+		})
+	}
+	return policies
+}
+
+// apiOperationsState returns the given operations read from WSO2 API Manager. The scopes and operation policies
+// of an operation are null when the operation has none, unless they are set in the prior state.
+func apiOperationsState(prior []apiOperationResourceModel, operations []apim.APIOperation) []apiOperationResourceModel {
+	priorByKey := map[string]apiOperationResourceModel{}
+	for _, operation := range prior {
+		priorByKey[operation.Verb.ValueString()+" "+operation.Target.ValueString()] = operation
+	}
+
+	var state []apiOperationResourceModel
+	for _, operation := range operations {
+		priorOperation := priorByKey[operation.Verb+" "+operation.Target]
+		scopes := priorOperation.Scopes
+		if len(operation.Scopes) > 0 || scopes != nil {
+			scopes = append([]string{}, operation.Scopes...)
+		}
+		policies := priorOperation.OperationPolicies
+		p := operation.OperationPolicies
+		if p == nil {
+			p = &apim.APIOperationPolicies{}
+		}
+		if policies != nil || len(p.Request) > 0 || len(p.Response) > 0 || len(p.Fault) > 0 {
+			var priorPolicies apiOperationPoliciesResourceModel
+			if policies != nil {
+				priorPolicies = *policies
+			}
+			policies = &apiOperationPoliciesResourceModel{
+				Request:  operationPoliciesState(priorPolicies.Request, p.Request),
+				Response: operationPoliciesState(priorPolicies.Response, p.Response),
+				Fault:    operationPoliciesState(priorPolicies.Fault, p.Fault),
+			}
+		}
+		state = append(state, apiOperationResourceModel{
+			// ID:     types.StringValue(operation.ID),
+			Target:            types.StringValue(operation.Target),
+			Verb:              types.StringValue(operation.Verb),
+			AuthType:          types.StringValue(operation.AuthType),
+			ThrottlingPolicy:  types.StringValue(operation.ThrottlingPolicy),
+			Scopes:            scopes,
+			OperationPolicies: policies,
+		})
+	}
+	return state
+}
+
+// operationPoliciesState returns the given operation policies of a flow read from WSO2 API Manager,
+// null when the flow has no policies unless set in the prior state.
+func operationPoliciesState(prior []apiOperationPolicyResourceModel, policies []apim.OperationPolicy) []apiOperationPolicyResourceModel {
+	if len(policies) == 0 && prior == nil {
+		return nil
+	}
+	state := make([]apiOperationPolicyResourceModel, 0, len(policies))
+	for i, policy := range policies {
+		var parameters map[string]string
+		if len(policy.Parameters) > 0 || i < len(prior) && prior[i].Parameters != nil {
+			parameters = make(map[string]string, len(policy.Parameters))
+			for name, value := range policy.Parameters {
+				if s, ok := value.(string); ok {
+					parameters[name] = s
+				} else {
+					parameters[name] = fmt.Sprint(value)
+				}
+			}
+		}
+		state = append(state, apiOperationPolicyResourceModel{
+			Name:       types.StringValue(policy.PolicyName),
+			Version:    types.StringValue(policy.PolicyVersion),
+			Parameters: parameters,
+		})
+	}
+	return state
+}
@@ -0,0 +1,125 @@
+package wso2apim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestApiOperationsRequest(t *testing.T) {
+	plan := []apiOperationResourceModel{
+		{
+			Target:           types.StringValue("/pets"),
+			Verb:             types.StringValue("GET"),
+			AuthType:         types.StringValue("None"),
+			ThrottlingPolicy: types.StringValue("Unlimited"),
+		},
+		{
+			Target:           types.StringValue("/pets"),
+			Verb:             types.StringValue("POST"),
+			AuthType:         types.StringValue("Application"),
+			ThrottlingPolicy: types.StringValue("10KPerMin"),
+			Scopes:           []string{"write"},
+			OperationPolicies: &apiOperationPoliciesResourceModel{
+				Request: []apiOperationPolicyResourceModel{
+					{Name: types.StringValue("addHeader"), Version: types.StringValue("v1"), Parameters: map[string]string{"headerName": "x-source"}},
+				},
+			},
+		},
+	}
+	want := []apim.APIOperation{
+		{Target: "/pets", Verb: "GET", AuthType: "None", ThrottlingPolicy: "Unlimited"},
+		{
+			Target:           "/pets",
+			Verb:             "POST",
+			AuthType:         "Application",
+			ThrottlingPolicy: "10KPerMin",
+			Scopes:           []string{"write"},
+			OperationPolicies: &apim.APIOperationPolicies{
+				Request:  []apim.OperationPolicy{{PolicyName: "addHeader", PolicyVersion: "v1", Parameters: map[string]any{"headerName": "x-source"}}},
+				Response: []apim.OperationPolicy{},
+				Fault:    []apim.OperationPolicy{},
+			},
+		},
+	}
+	if got := apiOperationsRequest(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestApiOperationsState(t *testing.T) {
+	cases := []struct {
+		name       string
+		prior      []apiOperationResourceModel
+		operations []apim.APIOperation
+		want       []apiOperationResourceModel
+	}{
+		{
+			name:       "no scopes nor policies",
+			operations: []apim.APIOperation{{Target: "/pets", Verb: "GET", AuthType: "None", ThrottlingPolicy: "Unlimited"}},
+			want: []apiOperationResourceModel{
+				{Target: types.StringValue("/pets"), Verb: types.StringValue("GET"), AuthType: types.StringValue("None"), ThrottlingPolicy: types.StringValue("Unlimited")},
+			},
+		},
+		{
+			name: "empty scopes and policies kept from the prior state",
+			prior: []apiOperationResourceModel{
+				{
+					Target:            types.StringValue("/pets"),
+					Verb:              types.StringValue("GET"),
+					Scopes:            []string{},
+					OperationPolicies: &apiOperationPoliciesResourceModel{Request: []apiOperationPolicyResourceModel{}},
+				},
+			},
+			operations: []apim.APIOperation{{Target: "/pets", Verb: "GET", AuthType: "None", ThrottlingPolicy: "Unlimited"}},
+			want: []apiOperationResourceModel{
+				{
+					Target:            types.StringValue("/pets"),
+					Verb:              types.StringValue("GET"),
+					AuthType:          types.StringValue("None"),
+					ThrottlingPolicy:  types.StringValue("Unlimited"),
+					Scopes:            []string{},
+					OperationPolicies: &apiOperationPoliciesResourceModel{Request: []apiOperationPolicyResourceModel{}},
+				},
+			},
+		},
+		{
+			name: "policy parameters",
+			operations: []apim.APIOperation{
+				{
+					Target:           "/pets",
+					Verb:             "POST",
+					AuthType:         "Application",
+					ThrottlingPolicy: "Unlimited",
+					Scopes:           []string{"write"},
+					OperationPolicies: &apim.APIOperationPolicies{
+						Response: []apim.OperationPolicy{{PolicyName: "rateLimit", PolicyVersion: "v1", Parameters: map[string]any{"limit": float64(10)}}},
+					},
+				},
+			},
+			want: []apiOperationResourceModel{
+				{
+					Target:           types.StringValue("/pets"),
+					Verb:             types.StringValue("POST"),
+					AuthType:         types.StringValue("Application"),
+					ThrottlingPolicy: types.StringValue("Unlimited"),
+					Scopes:           []string{"write"},
+					OperationPolicies: &apiOperationPoliciesResourceModel{
+						Response: []apiOperationPolicyResourceModel{
+							{Name: types.StringValue("rateLimit"), Version: types.StringValue("v1"), Parameters: map[string]string{"limit": "10"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := apiOperationsState(c.prior, c.operations); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package wso2apim
+
+import (
+	"reflect"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type apiSecurityResourceModel struct {
+	OAuth2                       types.Bool `tfsdk:"oauth2"`
+	APIKey                       types.Bool `tfsdk:"api_key"`
+	BasicAuth                    types.Bool `tfsdk:"basic_auth"`
+	MutualSSL                    types.Bool `tfsdk:"mutualssl"`
+	ApplicationSecurityMandatory types.Bool `tfsdk:"application_security_mandatory"`
+	MutualSSLMandatory           types.Bool `tfsdk:"mutualssl_mandatory"`
+}
+
+type apiCorsConfigurationResourceModel struct {
+	Enabled          types.Bool `tfsdk:"enabled"`
+	AllowOrigins     []string   `tfsdk:"allow_origins"`
+	AllowCredentials types.Bool `tfsdk:"allow_credentials"`
+	AllowHeaders     []string   `tfsdk:"allow_headers"`
+	AllowMethods     []string   `tfsdk:"allow_methods"`
+}
+
+type apiScopeResourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Shared      types.Bool   `tfsdk:"shared"`
+	DisplayName types.String `tfsdk:"display_name"`
+	Description types.String `tfsdk:"description"`
+	Roles       []string     `tfsdk:"roles"`
+}
+
+// securitySchemeRequest returns the security schemes of the given plan, the default `oauth2` scheme if not set.
+func securitySchemeRequest(plan *apiSecurityResourceModel) []string {
+	if plan == nil {
+		return []string{"oauth2", "oauth_basic_auth_api_key_mandatory"}
+	}
+	var schemes []string
+	for _, scheme := range []struct {
+		name    string
+		enabled types.Bool
+	}{
+		{"oauth2", plan.OAuth2},
+		{"api_key", plan.APIKey},
+		{"basic_auth", plan.BasicAuth},
+		{"mutualssl", plan.MutualSSL},
+		{"oauth_basic_auth_api_key_mandatory", plan.ApplicationSecurityMandatory},
+		{"mutualssl_mandatory", plan.MutualSSLMandatory},
+	} {
+		if scheme.enabled.ValueBool() {
+			schemes = append(schemes, scheme.name)
+		}
+	}
+	return schemes
+}
+
+// securitySchemeState returns the given security schemes read from WSO2 API Manager.
+// The default `oauth2` scheme is only reported if it is in the prior state.
+func securitySchemeState(prior *apiSecurityResourceModel, schemes []string) *apiSecurityResourceModel {
+	enabled := map[string]bool{}
+	for _, scheme := range schemes {
+		enabled[scheme] = true
+	}
+	state := &apiSecurityResourceModel{
+		OAuth2:                       types.BoolValue(enabled["oauth2"]),
+		APIKey:                       types.BoolValue(enabled["api_key"]),
+		BasicAuth:                    types.BoolValue(enabled["basic_auth"]),
+		MutualSSL:                    types.BoolValue(enabled["mutualssl"]),
+		ApplicationSecurityMandatory: types.BoolValue(enabled["oauth_basic_auth_api_key_mandatory"]),
+		MutualSSLMandatory:           types.BoolValue(enabled["mutualssl_mandatory"]),
+	}
+	if prior == nil && reflect.DeepEqual(securitySchemeRequest(state), securitySchemeRequest(nil)) {
+		return nil
+	}
+	return state
+}
+
+// corsConfigurationRequest returns the CORS configuration of the given plan, disabled if not set.
+func corsConfigurationRequest(plan *apiCorsConfigurationResourceModel) *apim.APICorsConfiguration {
+	if plan == nil {
+		plan = &apiCorsConfigurationResourceModel{}
+	}
+	return &apim.APICorsConfiguration{
+		CorsConfigurationEnabled:      plan.Enabled.ValueBool(),
+		AccessControlAllowOrigins:     append([]string{}, plan.AllowOrigins...),
+		AccessControlAllowCredentials: plan.AllowCredentials.ValueBool(),
+		AccessControlAllowHeaders:     append([]string{}, plan.AllowHeaders...),
+		AccessControlAllowMethods:     append([]string{}, plan.AllowMethods...),
+	}
+}
+
+// corsConfigurationState returns the given CORS configuration read from WSO2 API Manager.
+// A disabled configuration is only reported if it is in the prior state.
+func corsConfigurationState(prior *apiCorsConfigurationResourceModel, config *apim.APICorsConfiguration) *apiCorsConfigurationResourceModel {
+	if config == nil || (prior == nil && !config.CorsConfigurationEnabled) {
+		return nil
+	}
+	return &apiCorsConfigurationResourceModel{
+		Enabled:          types.BoolValue(config.CorsConfigurationEnabled),
+		AllowOrigins:     append([]string{}, config.AccessControlAllowOrigins...),
+		AllowCredentials: types.BoolValue(config.AccessControlAllowCredentials),
+		AllowHeaders:     append([]string{}, config.AccessControlAllowHeaders...),
+		AllowMethods:     append([]string{}, config.AccessControlAllowMethods...),
+	}
+}
+
+// apiScopesRequest returns the scopes of the given plan, only the names of the shared scopes are sent.
+func apiScopesRequest(plan []apiScopeResourceModel) []apim.APIScope {
+	var scopes []apim.APIScope
+	for _, s := range plan {
+		scope := apim.APIScope{Scope: apim.Scope{Name: s.Name.ValueString()}, Shared: s.Shared.ValueBool()}
+		if !scope.Shared {
+			scope.Scope.DisplayName = s.DisplayName.ValueString()
+			if scope.Scope.DisplayName == "" {
+				scope.Scope.DisplayName = scope.Scope.Name
+			}
+			scope.Scope.Description = s.Description.ValueString()
+			scope.Scope.Bindings = append([]string{}, s.Roles...)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// apiScopesState returns the given scopes read from WSO2 API Manager, in the order of the prior state
+// followed by the scopes not in the prior state. The description of an api-local scope is only reported if it is set
+// or in the prior state, the one of a shared scope is not reported.
+func apiScopesState(prior []apiScopeResourceModel, scopes []apim.APIScope) []apiScopeResourceModel {
+	priorByName := map[string]apiScopeResourceModel{}
+	priorNames := make([]string, 0, len(prior))
+	for _, s := range prior {
+		priorByName[s.Name.ValueString()] = s
+		priorNames = append(priorNames, s.Name.ValueString())
+	}
+	scopes = append([]apim.APIScope{}, scopes...)
+	sortByPrior(scopes, priorNames, func(s apim.APIScope) string { return s.Scope.Name })
+
+	var state []apiScopeResourceModel
+	for _, s := range scopes {
+		description := types.StringNull()
+		if !s.Shared {
+			description = priorByName[s.Scope.Name].Description
+			if s.Scope.Description != "" || !description.IsNull() {
+				description = types.StringValue(s.Scope.Description)
+			}
+		}
+		state = append(state, apiScopeResourceModel{
+			Name:        types.StringValue(s.Scope.Name),
+			Shared:      types.BoolValue(s.Shared),
+			DisplayName: types.StringValue(s.Scope.DisplayName),
+			Description: description,
+			Roles:       append([]string{}, s.Scope.Bindings...),
+		})
+	}
+	return state
+}
@@ -0,0 +1,229 @@
+package wso2apim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSecuritySchemeRequest(t *testing.T) {
+	cases := []struct {
+		name string
+		plan *apiSecurityResourceModel
+		want []string
+	}{
+		{
+			name: "not set",
+			plan: nil,
+			want: []string{"oauth2", "oauth_basic_auth_api_key_mandatory"},
+		},
+		{
+			name: "api key and mutual ssl",
+			plan: &apiSecurityResourceModel{
+				OAuth2:                       types.BoolValue(false),
+				APIKey:                       types.BoolValue(true),
+				MutualSSL:                    types.BoolValue(true),
+				ApplicationSecurityMandatory: types.BoolValue(true),
+				MutualSSLMandatory:           types.BoolValue(false),
+			},
+			want: []string{"api_key", "mutualssl", "oauth_basic_auth_api_key_mandatory"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := securitySchemeRequest(c.plan); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestSecuritySchemeState(t *testing.T) {
+	cases := []struct {
+		name    string
+		prior   *apiSecurityResourceModel
+		schemes []string
+		want    *apiSecurityResourceModel
+	}{
+		{
+			name:    "default not in the prior state",
+			schemes: []string{"oauth2", "oauth_basic_auth_api_key_mandatory"},
+			want:    nil,
+		},
+		{
+			name:    "default in the prior state",
+			prior:   &apiSecurityResourceModel{},
+			schemes: []string{"oauth2", "oauth_basic_auth_api_key_mandatory"},
+			want: &apiSecurityResourceModel{
+				OAuth2:                       types.BoolValue(true),
+				APIKey:                       types.BoolValue(false),
+				BasicAuth:                    types.BoolValue(false),
+				MutualSSL:                    types.BoolValue(false),
+				ApplicationSecurityMandatory: types.BoolValue(true),
+				MutualSSLMandatory:           types.BoolValue(false),
+			},
+		},
+		{
+			name:    "basic auth",
+			schemes: []string{"basic_auth"},
+			want: &apiSecurityResourceModel{
+				OAuth2:                       types.BoolValue(false),
+				APIKey:                       types.BoolValue(false),
+				BasicAuth:                    types.BoolValue(true),
+				MutualSSL:                    types.BoolValue(false),
+				ApplicationSecurityMandatory: types.BoolValue(false),
+				MutualSSLMandatory:           types.BoolValue(false),
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := securitySchemeState(c.prior, c.schemes); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestCorsConfigurationRequest(t *testing.T) {
+	cases := []struct {
+		name string
+		plan *apiCorsConfigurationResourceModel
+		want *apim.APICorsConfiguration
+	}{
+		{
+			name: "not set",
+			plan: nil,
+			want: &apim.APICorsConfiguration{
+				AccessControlAllowOrigins: []string{},
+				AccessControlAllowHeaders: []string{},
+				AccessControlAllowMethods: []string{},
+			},
+		},
+		{
+			name: "enabled",
+			plan: &apiCorsConfigurationResourceModel{
+				Enabled:          types.BoolValue(true),
+				AllowOrigins:     []string{"*"},
+				AllowCredentials: types.BoolValue(true),
+				AllowHeaders:     []string{"authorization"},
+				AllowMethods:     []string{"GET", "POST"},
+			},
+			want: &apim.APICorsConfiguration{
+				CorsConfigurationEnabled:      true,
+				AccessControlAllowOrigins:     []string{"*"},
+				AccessControlAllowCredentials: true,
+				AccessControlAllowHeaders:     []string{"authorization"},
+				AccessControlAllowMethods:     []string{"GET", "POST"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := corsConfigurationRequest(c.plan); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestCorsConfigurationState(t *testing.T) {
+	disabled := &apim.APICorsConfiguration{AccessControlAllowOrigins: []string{"*"}}
+	cases := []struct {
+		name   string
+		prior  *apiCorsConfigurationResourceModel
+		config *apim.APICorsConfiguration
+		want   *apiCorsConfigurationResourceModel
+	}{
+		{
+			name:   "disabled not in the prior state",
+			config: disabled,
+			want:   nil,
+		},
+		{
+			name:   "disabled in the prior state",
+			prior:  &apiCorsConfigurationResourceModel{},
+			config: disabled,
+			want: &apiCorsConfigurationResourceModel{
+				Enabled:          types.BoolValue(false),
+				AllowOrigins:     []string{"*"},
+				AllowCredentials: types.BoolValue(false),
+				AllowHeaders:     []string{},
+				AllowMethods:     []string{},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := corsConfigurationState(c.prior, c.config); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestApiScopesRequest(t *testing.T) {
+	plan := []apiScopeResourceModel{
+		{
+			Name:        types.StringValue("read"),
+			Shared:      types.BoolValue(false),
+			DisplayName: types.StringNull(),
+			Description: types.StringValue("Read access"),
+			Roles:       []string{"reader"},
+		},
+		{
+			Name:        types.StringValue("admin"),
+			Shared:      types.BoolValue(true),
+			DisplayName: types.StringValue("ignored"),
+			Roles:       []string{"ignored"},
+		},
+	}
+	want := []apim.APIScope{
+		{Scope: apim.Scope{Name: "read", DisplayName: "read", Description: "Read access", Bindings: []string{"reader"}}},
+		{Scope: apim.Scope{Name: "admin"}, Shared: true},
+	}
+	if got := apiScopesRequest(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestApiScopesState(t *testing.T) {
+	cases := []struct {
+		name   string
+		prior  []apiScopeResourceModel
+		scopes []apim.APIScope
+		want   []apiScopeResourceModel
+	}{
+		{
+			name: "ordered by the prior state",
+			prior: []apiScopeResourceModel{
+				{Name: types.StringValue("write"), Description: types.StringValue("")},
+				{Name: types.StringValue("read"), Description: types.StringNull()},
+			},
+			scopes: []apim.APIScope{
+				{Scope: apim.Scope{Name: "admin", DisplayName: "admin", Description: "ignored"}, Shared: true},
+				{Scope: apim.Scope{Name: "read", DisplayName: "read"}},
+				{Scope: apim.Scope{Name: "write", DisplayName: "write"}},
+			},
+			want: []apiScopeResourceModel{
+				{Name: types.StringValue("write"), Shared: types.BoolValue(false), DisplayName: types.StringValue("write"), Description: types.StringValue(""), Roles: []string{}},
+				{Name: types.StringValue("read"), Shared: types.BoolValue(false), DisplayName: types.StringValue("read"), Description: types.StringNull(), Roles: []string{}},
+				{Name: types.StringValue("admin"), Shared: types.BoolValue(true), DisplayName: types.StringValue("admin"), Description: types.StringNull(), Roles: []string{}},
+			},
+		},
+		{
+			name:   "none",
+			scopes: nil,
+			want:   nil,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := apiScopesState(c.prior, c.scopes); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
@@ -1,8 +1,21 @@
 package wso2apim
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -31,8 +44,7 @@ resource "wso2apim_api" "test" {
 					resource.TestCheckResourceAttr("wso2apim_api.test", "context", "/bar3"),
 					resource.TestCheckResourceAttr("wso2apim_api.test", "version", "v1"),
 					resource.TestCheckResourceAttr("wso2apim_api.test", "policies.0", "Unlimited"),
-					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.0.target", "/graphql"),
-					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.0.verb", "POST"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{"target": "/graphql", "verb": "POST"}),
 					resource.TestCheckResourceAttrSet("wso2apim_api.test", "id"),
 					resource.TestCheckResourceAttrSet("wso2apim_api.test", "last_updated"),
 				),
@@ -64,11 +76,1182 @@ resource "wso2apim_api" "test" {
 					resource.TestCheckResourceAttr("wso2apim_api.test", "context", "/bar3"),
 					resource.TestCheckResourceAttr("wso2apim_api.test", "version", "v1"),
 					resource.TestCheckResourceAttr("wso2apim_api.test", "policies.#", "0"),
-					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.0.target", "/graphql"),
-					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.0.verb", "POST"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{"target": "/graphql", "verb": "POST"}),
 				),
 			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
+
+func TestAccApiResourceOpenAPI(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "pets-api"
+	context  = "/pets"
+	version  = "v1"
+	policies = ["Unlimited"]
+	openapi_definition = <<-EOT
+		openapi: 3.0.1
+		info:
+		  title: Pets
+		  version: v1
+		paths:
+		  /pets:
+		    get:
+		      responses:
+		        200:
+		          description: OK
+	EOT
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{"target": "/pets", "verb": "GET"}),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "pets-api"
+	context  = "/pets"
+	version  = "v1"
+	policies = ["Unlimited"]
+	openapi_definition = jsonencode({
+		openapi = "3.0.1"
+		info    = { title = "Pets", version = "v1" }
+		paths = {
+			"/pets" = {
+				get  = { responses = { "200" = { description = "OK" } } }
+				post = { responses = { "201" = { description = "Created" } } }
+			}
+		}
+	})
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.#", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceGraphQL(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "starwars-api"
+	context  = "/starwars"
+	version  = "v1"
+	type     = "HTTP"
+	policies = ["Unlimited"]
+	graphql_schema = <<-EOT
+		type Query {
+		  hero: String
+		}
+	EOT
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid api type"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "starwars-api"
+	context  = "/starwars"
+	version  = "v1"
+	policies = ["Unlimited"]
+	graphql_schema = <<-EOT
+		type Query {
+		  hero: String
+		}
+	EOT
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "type", "GRAPHQL"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{"target": "hero", "verb": "QUERY"}),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "starwars-api"
+	context  = "/starwars"
+	version  = "v1"
+	policies = ["Unlimited"]
+	graphql_schema = <<-EOT
+		type Query {
+		  hero: String
+		  droid: String
+		}
+	EOT
+	graphql_complexity = [{
+		type             = "Query"
+		field            = "droid"
+		complexity_value = 2
+	}]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.#", "2"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "graphql_complexity.0.complexity_value", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceWSDL(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "calculator-api"
+	context  = "/calculator"
+	version  = "v1"
+	type     = "HTTP"
+	policies = ["Unlimited"]
+	wsdl_definition = "http://www.dneonline.com/calculator.asmx?wsdl"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid api type"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "calculator-api"
+	context  = "/calculator"
+	version  = "v1"
+	type     = "SOAPTOREST"
+	policies = ["Unlimited"]
+	wsdl_definition = "http://www.dneonline.com/calculator.asmx?wsdl"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "type", "SOAPTOREST"),
+					resource.TestCheckResourceAttrSet("wso2apim_api.test", "operations.#"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceAsyncAPI(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "notifications-api"
+	context  = "/notifications"
+	version  = "v1"
+	type     = "WS"
+	policies = ["Unlimited"]
+	operations = [{
+		target = "/notifications"
+		verb   = "GET"
+	}]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid operation verb"),
+			},
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "notifications-api"
+	context  = "/notifications"
+	version  = "v1"
+	policies = ["Unlimited"]
+	asyncapi_definition = <<-EOT
+		asyncapi: 2.0.0
+		info:
+		  title: notifications-api
+		  version: v1
+		channels:
+		  /notifications:
+		    subscribe: {}
+	EOT
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Missing api type"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "notifications-api"
+	context  = "/notifications"
+	version  = "v1"
+	type     = "WS"
+	policies = ["Unlimited"]
+	endpoint_config = {
+		endpoint_type = "ws"
+		production_endpoints = {
+			url = "ws://echo.websocket.org:80"
+		}
+	}
+	asyncapi_definition = <<-EOT
+		asyncapi: 2.0.0
+		info:
+		  title: notifications-api
+		  version: v1
+		channels:
+		  /notifications:
+		    subscribe: {}
+	EOT
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "type", "WS"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{"target": "/notifications", "verb": "SUBSCRIBE"}),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceWebSub(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "orders-hub"
+	context  = "/orders-hub"
+	version  = "v1"
+	type     = "WEBSUB"
+	policies = ["Unlimited"]
+	operations = [{
+		target = "orders"
+		verb   = "SUBSCRIBE"
+	}]
+	websub_subscription = {
+		enabled           = true
+		secret            = "s3cret"
+		signing_algorithm = "SHA256"
+		signature_header  = "x-hub-signature"
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "type", "WEBSUB"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "websub_subscription.enabled", "true"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "websub_subscription.signing_algorithm", "SHA256"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceLifecycle(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name            = "lifecycle-api"
+	context         = "/lifecycle"
+	version         = "v1"
+	policies        = ["Unlimited"]
+	lifecycle_state = "CREATED"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "lifecycle_state", "CREATED"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "lifecycle_status", "CREATED"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name            = "lifecycle-api"
+	context         = "/lifecycle"
+	version         = "v1"
+	policies        = ["Unlimited"]
+	lifecycle_state = "DEPRECATED"
+	lifecycle_checklist = {
+		requires_resubscription = true
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "lifecycle_state", "DEPRECATED"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "lifecycle_status", "DEPRECATED"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceNewVersion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "v1" {
+	name     = "versioned-api"
+	context  = "/versioned"
+	version  = "v1"
+	policies = ["Unlimited"]
+	operations = [{
+		target = "/items"
+		verb   = "GET"
+	}]
+}
+
+resource "wso2apim_api" "v2" {
+	name               = "versioned-api"
+	context            = "/versioned"
+	version            = "v2"
+	source_api_id      = wso2apim_api.v1.id
+	is_default_version = true
+	policies           = ["Unlimited"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.v2", "version", "v2"),
+					resource.TestCheckResourceAttr("wso2apim_api.v2", "is_default_version", "true"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.v2", "operations.*", map[string]string{"target": "/items", "verb": "GET"}),
+					resource.TestCheckResourceAttr("wso2apim_api.v1", "is_default_version", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceScopes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "scoped-api"
+	context = "/scoped"
+	version = "v1"
+	scopes = [{
+		name        = "items:read"
+		shared      = true
+		description = "Read the items"
+	}]
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid shared scope"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_scope" "read" {
+	name = "items:read"
+}
+
+resource "wso2apim_api" "test" {
+	name     = "scoped-api"
+	context  = "/scoped"
+	version  = "v1"
+	policies = ["Unlimited"]
+	scopes = [{
+		name   = wso2apim_scope.read.name
+		shared = true
+	}, {
+		name  = "items:write"
+		roles = ["admin"]
+	}]
+	operations = [{
+		target = "/items"
+		verb   = "GET"
+		scopes = [wso2apim_scope.read.name]
+	}, {
+		target = "/items"
+		verb   = "POST"
+		scopes = ["items:write"]
+	}]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "scopes.#", "2"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "scopes.0.shared", "true"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "scopes.1.display_name", "items:write"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "scopes.1.roles.0", "admin"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{"verb": "GET", "scopes.0": "items:read"}),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{"verb": "POST", "scopes.0": "items:write"}),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceOperations(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "operations-api"
+	context = "/operations"
+	version = "v1"
+	operations = [{
+		target = "/items"
+		verb   = "GET"
+	}, {
+		target = "/items"
+		verb   = "GET"
+	}]
+}
+`,
+				ExpectError: regexp.MustCompile("Duplicate operation"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "operations-api"
+	context  = "/operations"
+	version  = "v1"
+	policies = ["Unlimited"]
+	operations = [{
+		target    = "/health"
+		verb      = "GET"
+		auth_type = "None"
+	}, {
+		target            = "/items"
+		verb              = "POST"
+		throttling_policy = "10KPerMin"
+		operation_policies = {
+			request = [{
+				name = "addHeader"
+				parameters = {
+					headerName  = "X-Source"
+					headerValue = "terraform"
+				}
+			}]
+		}
+	}]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "operations.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{
+						"target":            "/health",
+						"auth_type":         "None",
+						"throttling_policy": "Unlimited",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("wso2apim_api.test", "operations.*", map[string]string{
+						"target":                               "/items",
+						"auth_type":                            "Application & Application User",
+						"throttling_policy":                    "10KPerMin",
+						"operation_policies.request.0.name":    "addHeader",
+						"operation_policies.request.0.version": "v1",
+						"operation_policies.request.0.parameters.headerName": "X-Source",
+					}),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "wso2apim_api.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Reordering the operations is not a change
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name     = "operations-api"
+	context  = "/operations"
+	version  = "v1"
+	policies = ["Unlimited"]
+	operations = [{
+		target            = "/items"
+		verb              = "POST"
+		throttling_policy = "10KPerMin"
+		operation_policies = {
+			request = [{
+				name = "addHeader"
+				parameters = {
+					headerName  = "X-Source"
+					headerValue = "terraform"
+				}
+			}]
+		}
+	}, {
+		target    = "/health"
+		verb      = "GET"
+		auth_type = "None"
+	}]
+}
+`,
+				PlanOnly: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceVisibility(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name       = "internal-api"
+	context    = "/internal"
+	version    = "v1"
+	visibility = "RESTRICTED"
+}
+`,
+				ExpectError: regexp.MustCompile("visible_roles must be set when visibility is RESTRICTED"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name                 = "internal-api"
+	context              = "/internal"
+	version              = "v1"
+	visibility           = "RESTRICTED"
+	visible_roles        = ["admin"]
+	access_control       = "RESTRICTED"
+	access_control_roles = ["admin"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "visibility", "RESTRICTED"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "visible_roles.0", "admin"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "access_control", "RESTRICTED"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "access_control_roles.0", "admin"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "subscription_availability", "current_tenant"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "wso2apim_api.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name       = "internal-api"
+	context    = "/internal"
+	version    = "v1"
+	visibility = "PRIVATE"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "visibility", "PRIVATE"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "visible_roles.#", "0"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "access_control", "NONE"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "access_control_roles.#", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceSecurity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "secured-api"
+	context = "/secured"
+	version = "v1"
+	security = {
+		oauth2 = false
+	}
+}
+`,
+				ExpectError: regexp.MustCompile("Missing security scheme"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name                 = "secured-api"
+	context              = "/secured"
+	version              = "v1"
+	authorization_header = "X-Authorization"
+	security = {
+		api_key = true
+	}
+	cors_configuration = {
+		allow_origins = ["https://app.example.com"]
+		allow_headers = ["X-Authorization", "Content-Type"]
+		allow_methods = ["GET", "POST", "OPTIONS"]
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "authorization_header", "X-Authorization"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "security.oauth2", "true"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "security.api_key", "true"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "security.application_security_mandatory", "true"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "cors_configuration.enabled", "true"),
+					resource.TestCheckTypeSetElemAttr("wso2apim_api.test", "cors_configuration.allow_origins.*", "https://app.example.com"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "cors_configuration.allow_methods.#", "3"),
+					resource.TestCheckTypeSetElemAttr("wso2apim_api.test", "key_managers.*", "all"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "wso2apim_api.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name                 = "secured-api"
+	context              = "/secured"
+	version              = "v1"
+	authorization_header = "X-Authorization"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("wso2apim_api.test", "security"),
+					resource.TestCheckNoResourceAttr("wso2apim_api.test", "cors_configuration"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceEndpointSecurity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "backend-api"
+	context = "/backend"
+	version = "v1"
+	endpoint_config = {
+		endpoint_type = "http"
+		production_endpoints = {
+			url = "https://backend.example.com"
+		}
+	}
+	endpoint_security = {
+		production = {
+			type     = "BASIC"
+			username = "gateway"
+		}
+	}
+}
+`,
+				ExpectError: regexp.MustCompile("password must be set for BASIC endpoint security"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "backend-api"
+	context = "/backend"
+	version = "v1"
+	endpoint_config = {
+		endpoint_type = "http"
+		production_endpoints = {
+			url = "https://backend.example.com"
+		}
+		sandbox_endpoints = {
+			url = "https://sandbox.backend.example.com"
+		}
+	}
+	endpoint_security = {
+		production = {
+			type     = "BASIC"
+			username = "gateway"
+			password = "secret"
+		}
+		sandbox = {
+			type               = "APIKEY"
+			api_key_identifier = "X-API-Key"
+			api_key_value      = "sandbox-key"
+		}
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_security.production.type", "BASIC"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_security.production.username", "gateway"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_security.production.password", "secret"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_security.sandbox.api_key_identifier", "X-API-Key"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_security.sandbox.api_key_identifier_type", "HEADER"),
+				),
+			},
+			// Refreshing the masked secrets is not a change
+			{
+				RefreshState: true,
+			},
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "backend-api"
+	context = "/backend"
+	version = "v1"
+	endpoint_config = {
+		endpoint_type = "http"
+		production_endpoints = {
+			url = "https://backend.example.com"
+		}
+		sandbox_endpoints = {
+			url = "https://sandbox.backend.example.com"
+		}
+	}
+	endpoint_security = {
+		production = {
+			type     = "BASIC"
+			username = "gateway"
+			password = "secret"
+		}
+		sandbox = {
+			type               = "APIKEY"
+			api_key_identifier = "X-API-Key"
+			api_key_value      = "sandbox-key"
+		}
+	}
+}
+`,
+				PlanOnly: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceEndpointTypes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "failover-api"
+	context = "/failover"
+	version = "v1"
+	endpoint_config = {
+		endpoint_type = "http"
+		production_endpoints = {
+			url = "https://active.example.com"
+		}
+		production_failovers = [{
+			url = "https://standby.example.com"
+		}]
+	}
+}
+`,
+				ExpectError: regexp.MustCompile("production_failovers can only be set for the failover endpoint type"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "failover-api"
+	context = "/failover"
+	version = "v1"
+	endpoint_config = {
+		endpoint_type = "failover"
+		production_endpoints = {
+			url = "https://active.example.com"
+			advanced = {
+				action_duration   = 30000
+				action_select     = "fault"
+				retry_error_codes = ["101504"]
+				suspend_duration  = 5000
+				factor            = 2
+			}
+		}
+		production_failovers = [{
+			url = "https://standby.example.com"
+		}]
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.endpoint_type", "failover"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.production_endpoints.advanced.action_duration", "30000"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.production_endpoints.advanced.retry_error_codes.0", "101504"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.production_failovers.#", "1"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.production_failovers.0.url", "https://standby.example.com"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "failover-api"
+	context = "/failover"
+	version = "v1"
+	endpoint_config = {
+		endpoint_type = "load_balance"
+		load_balance = {
+			session_management = "transport"
+			session_timeout    = 60000
+			production_endpoints = [{
+				url = "https://backend-1.example.com"
+				}, {
+				url = "https://backend-2.example.com"
+			}]
+		}
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.endpoint_type", "load_balance"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.load_balance.algorithm", "org.apache.synapse.endpoints.algorithms.RoundRobin"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "endpoint_config.load_balance.production_endpoints.#", "2"),
+					resource.TestCheckNoResourceAttr("wso2apim_api.test", "endpoint_config.production_endpoints"),
+				),
+			},
+			{
+				RefreshState: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccApiResourceCatalogue(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "catalogue-api"
+	context = "/catalogue"
+	version = "v1"
+	tags    = ["payments", "internal"]
+	business_information = {
+		business_owner        = "Payments"
+		technical_owner       = "Payments Platform"
+		technical_owner_email = "payments-oncall@example.com"
+	}
+	additional_properties = {
+		team = {
+			value   = "payments"
+			display = true
+		}
+		cost_center = {
+			value = "1234"
+		}
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "tags.#", "2"),
+					resource.TestCheckTypeSetElemAttr("wso2apim_api.test", "tags.*", "payments"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "categories.#", "0"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "business_information.technical_owner_email", "payments-oncall@example.com"),
+					resource.TestCheckNoResourceAttr("wso2apim_api.test", "business_information.business_owner_email"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "additional_properties.team.display", "true"),
+					resource.TestCheckResourceAttr("wso2apim_api.test", "additional_properties.cost_center.display", "false"),
+				),
+			},
+			{
+				RefreshState: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_api" "test" {
+	name    = "catalogue-api"
+	context = "/catalogue"
+	version = "v1"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_api.test", "tags.#", "0"),
+					resource.TestCheckNoResourceAttr("wso2apim_api.test", "business_information"),
+					resource.TestCheckNoResourceAttr("wso2apim_api.test", "additional_properties"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestLifecycleChecklistRequest(t *testing.T) {
+	cases := []struct {
+		name string
+		plan *apiLifecycleChecklistResourceModel
+		want map[string]bool
+	}{
+		{
+			name: "not set",
+			plan: nil,
+			want: map[string]bool{},
+		},
+		{
+			name: "unset items are not sent",
+			plan: &apiLifecycleChecklistResourceModel{
+				DeprecateOldVersions:   types.BoolValue(false),
+				RequiresResubscription: types.BoolNull(),
+			},
+			want: map[string]bool{apim.ChecklistDeprecateOldVersions: false},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := lifecycleChecklistRequest(c.plan); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestBusinessInformationState(t *testing.T) {
+	cases := []struct {
+		name  string
+		prior *apiBusinessInformationResourceModel
+		info  *apim.APIBusinessInformation
+		want  *apiBusinessInformationResourceModel
+	}{
+		{
+			name: "empty not in the prior state",
+			info: &apim.APIBusinessInformation{},
+			want: nil,
+		},
+		{
+			name:  "empty in the prior state",
+			prior: &apiBusinessInformationResourceModel{},
+			info:  nil,
+			want: &apiBusinessInformationResourceModel{
+				BusinessOwner:       types.StringNull(),
+				BusinessOwnerEmail:  types.StringNull(),
+				TechnicalOwner:      types.StringNull(),
+				TechnicalOwnerEmail: types.StringNull(),
+			},
+		},
+		{
+			name: "owners",
+			info: &apim.APIBusinessInformation{BusinessOwner: "Jane", TechnicalOwnerEmail: "ops@example.com"},
+			want: &apiBusinessInformationResourceModel{
+				BusinessOwner:       types.StringValue("Jane"),
+				BusinessOwnerEmail:  types.StringNull(),
+				TechnicalOwner:      types.StringNull(),
+				TechnicalOwnerEmail: types.StringValue("ops@example.com"),
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := businessInformationState(c.prior, c.info); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestAdditionalPropertiesRequest(t *testing.T) {
+	plan := map[string]apiAdditionalPropertyResourceModel{
+		"team":   {Value: types.StringValue("payments"), Display: types.BoolValue(true)},
+		"domain": {Value: types.StringValue("finance"), Display: types.BoolValue(false)},
+	}
+	want := apim.APIAdditionalProperties{
+		{Name: "domain", Value: "finance"},
+		{Name: "team", Value: "payments", Display: true},
+	}
+	if got := additionalPropertiesRequest(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := additionalPropertiesRequest(nil); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty list to clear the properties, got %+v", got)
+	}
+}
+
+func TestAdditionalPropertiesState(t *testing.T) {
+	cases := []struct {
+		name   string
+		prior  map[string]apiAdditionalPropertyResourceModel
+		list   apim.APIAdditionalProperties
+		byName map[string]apim.APIAdditionalProperty
+		want   map[string]apiAdditionalPropertyResourceModel
+	}{
+		{
+			name: "list",
+			list: apim.APIAdditionalProperties{{Name: "team", Value: "payments", Display: true}},
+			want: map[string]apiAdditionalPropertyResourceModel{
+				"team": {Value: types.StringValue("payments"), Display: types.BoolValue(true)},
+			},
+		},
+		{
+			name:   "map only",
+			byName: map[string]apim.APIAdditionalProperty{"team": {Value: "payments"}},
+			want: map[string]apiAdditionalPropertyResourceModel{
+				"team": {Value: types.StringValue("payments"), Display: types.BoolValue(false)},
+			},
+		},
+		{
+			name:  "empty kept from the prior state",
+			prior: map[string]apiAdditionalPropertyResourceModel{},
+			want:  map[string]apiAdditionalPropertyResourceModel{},
+		},
+		{
+			name: "none",
+			want: nil,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := additionalPropertiesState(c.prior, c.list, c.byName); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestApiResourceReadNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth2/token" {
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"code": 404, "message": "Not Found", "description": "Requested API with id 'deleted' not found"}`)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	r := NewApiResource().(*apiResource)
+	r.Configure(ctx, fwresource.ConfigureRequest{ProviderData: testProviderData(t, server.URL)}, &fwresource.ConfigureResponse{})
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: testObjectValue(objectType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "deleted"),
+	})}
+
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("expected the api deleted outside of Terraform to be removed from the state")
+	}
+}
+
+func TestApiResourceCreateCopyFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/oauth2/token":
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+		case strings.HasSuffix(r.URL.Path, "/copy-api"):
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": "copy", "name": "versioned-api", "context": "/versioned", "version": "v2"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"code": 400, "message": "Bad Request", "description": "Invalid api"}`)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	r := NewApiResource().(*apiResource)
+	r.Configure(ctx, fwresource.ConfigureRequest{ProviderData: testProviderData(t, server.URL)}, &fwresource.ConfigureResponse{})
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	raw := testObjectValue(objectType, map[string]tftypes.Value{
+		"name":          tftypes.NewValue(tftypes.String, "versioned-api"),
+		"context":       tftypes.NewValue(tftypes.String, "/versioned"),
+		"version":       tftypes.NewValue(tftypes.String, "v2"),
+		"source_api_id": tftypes.NewValue(tftypes.String, "source"),
+	})
+
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	r.Create(ctx, fwresource.CreateRequest{
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: raw},
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw},
+	}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error when the copy cannot be updated")
+	}
+	var id types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if id.ValueString() != "copy" {
+		t.Errorf("expected the copy to be kept in state, got id %q", id.ValueString())
+	}
+}
+
+func TestGraphQLComplexityState(t *testing.T) {
+	complexity := []apim.GraphQLComplexity{
+		{Type: "Query", Field: "droid", ComplexityValue: 2},
+		{Type: "Query", Field: "hero", ComplexityValue: 1},
+		{Type: "Query", Field: "human", ComplexityValue: 3},
+	}
+	prior := []apiGraphQLComplexityResourceModel{
+		{Type: types.StringValue("Query"), Field: types.StringValue("hero")},
+		{Type: types.StringValue("Query"), Field: types.StringValue("droid")},
+	}
+	want := []apiGraphQLComplexityResourceModel{
+		{Type: types.StringValue("Query"), Field: types.StringValue("hero"), ComplexityValue: types.Int64Value(1)},
+		{Type: types.StringValue("Query"), Field: types.StringValue("droid"), ComplexityValue: types.Int64Value(2)},
+		{Type: types.StringValue("Query"), Field: types.StringValue("human"), ComplexityValue: types.Int64Value(3)},
+	}
+	if got := graphQLComplexityState(prior, complexity); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if complexity[0].Field != "droid" {
+		t.Errorf("expected the values read from WSO2 API Manager to be left in their order, got %+v", complexity)
+	}
+}
@@ -0,0 +1,265 @@
+package wso2apim
+
+import (
+	"context"
+	"strings"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &apiRevisionResource{}
+	_ resource.ResourceWithImportState = &apiRevisionResource{}
+	_ resource.ResourceWithConfigure   = &apiRevisionResource{}
+)
+
+// NewApiRevisionResource is a helper function to simplify the provider implementation.
+func NewApiRevisionResource() resource.Resource {
+	return &apiRevisionResource{}
+}
+
+// apiRevisionResource is the resource implementation.
+type apiRevisionResource struct {
+	client *apim.Client
+}
+
+// apiRevisionResourceModel maps the resource schema data.
+type apiRevisionResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	ApiID          types.String `tfsdk:"api_id"`
+	Description    types.String `tfsdk:"description"`
+	Triggers       types.Map    `tfsdk:"triggers"`
+	PruneRevisions types.Bool   `tfsdk:"prune_revisions"`
+	DisplayName    types.String `tfsdk:"display_name"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *apiRevisionResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
+// Metadata returns the resource type name.
+func (r *apiRevisionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_revision"
+}
+
+// Schema defines the schema for the resource.
+func (r *apiRevisionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a revision of a WSO2 API Manager Api, a snapshot of the api which can be deployed to gateway environments. " +
+			"WSO2 API Manager keeps at most 5 revisions of an api, see `prune_revisions`. " +
+			"On WSO2 API Manager 3.2, which has no revisions, the revision stands for the api itself.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Revision ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"api_id": schema.StringAttribute{
+				Description: "API ID.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the revision.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary values which create a new revision when changed, e.g. the `last_updated` of the api.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"prune_revisions": schema.BoolAttribute{
+				Description: "Whether the oldest revisions of the api which are not deployed are deleted when the api already has the maximum of 5 revisions, " +
+					"including the revisions not managed by Terraform. Otherwise the revision cannot be created until older revisions are deleted. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"display_name": schema.StringAttribute{
+				Description: "Display name of the revision.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create a new resource
+func (r *apiRevisionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan apiRevisionResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	revision := &apim.APIRevision{ID: apim.CurrentRevision, DisplayName: apim.CurrentRevision}
+	if r.client.SupportsRevisions() {
+		if plan.PruneRevisions.ValueBool() {
+			deleted, err := r.client.PruneRevisions(ctx, plan.ApiID.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error creating api revision",
+					"Could not delete the old revisions of the api, unexpected error: "+err.Error(),
+				)
+				return
+			}
+			for _, d := range deleted {
+				tflog.Info(ctx, "Deleted old WSO2 API Manager Api revision", map[string]any{"api_id": plan.ApiID.ValueString(), "id": d.ID})
+			}
+		}
+
+		var err error
+		revision, err = r.client.CreateRevision(ctx, plan.ApiID.ValueString(), plan.Description.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating api revision",
+				"Could not create api revision, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Map response body to schema and populate Computed attribute values
+	plan.ID = types.StringValue(revision.ID)
+	plan.DisplayName = types.StringValue(revision.DisplayName)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r *apiRevisionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state apiRevisionResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !r.client.SupportsRevisions() {
+		return
+	}
+
+	// Get refreshed revision value from WSO2 API Manager
+	revision, err := r.client.GetRevision(ctx, state.ApiID.ValueString(), state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		// The revision was deleted outside of Terraform, let Terraform plan to re-create it.
+		tflog.Warn(ctx, "WSO2 API Manager Api revision not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading WSO2 API Manager Api revision",
+			"Could not read api revision ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	if revision.Description != "" || !state.Description.IsNull() {
+		state.Description = types.StringValue(revision.Description)
+	}
+	state.DisplayName = types.StringValue(revision.DisplayName)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update only sets prune_revisions, all the other configurable attributes require a new revision.
+func (r *apiRevisionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan apiRevisionResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *apiRevisionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state apiRevisionResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !r.client.SupportsRevisions() {
+		return
+	}
+
+	// Undeploy the revision before deleting it
+	revision, err := r.client.GetRevision(ctx, state.ApiID.ValueString(), state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		return
+	}
+	if err == nil && len(revision.DeploymentInfo) > 0 {
+		err = r.client.UndeployRevision(ctx, state.ApiID.ValueString(), state.ID.ValueString(), revision.DeploymentInfo)
+	}
+	if err == nil {
+		err = r.client.DeleteRevision(ctx, state.ApiID.ValueString(), state.ID.ValueString())
+	}
+	if err != nil && !apim.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting WSO2 API Manager Api revision",
+			"Could not delete api revision, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *apiRevisionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, ",")
+
+	if len(parts) < 2 {
+		resp.Diagnostics.AddError(
+			"Error importing item",
+			"Could not import item, unexpected error (ID should be in the format <api_id>,<id>): "+req.ID,
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("api_id"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("prune_revisions"), false)...)
+}
@@ -0,0 +1,83 @@
+package wso2apim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestApiRevisionResourceCreate(t *testing.T) {
+	cases := []struct {
+		name           string
+		pruneRevisions bool
+		wantDeleted    []string
+	}{
+		{
+			name:           "revisions of other resources are kept",
+			pruneRevisions: false,
+			wantDeleted:    nil,
+		},
+		{
+			name:           "oldest revision not deployed is pruned",
+			pruneRevisions: true,
+			wantDeleted:    []string{"r2"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var deleted []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/oauth2/token":
+					fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/api/revisions"):
+					fmt.Fprint(w, `{"count": 5, "list": [{"id": "r1", "deploymentInfo": [{"name": "Default"}]}, {"id": "r2"}, {"id": "r3"}, {"id": "r4"}, {"id": "r5"}]}`)
+				case r.Method == http.MethodDelete:
+					deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+					fmt.Fprint(w, `{}`)
+				case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/api/revisions"):
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprint(w, `{"id": "r6", "displayName": "Revision 6"}`)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			r := NewApiRevisionResource().(*apiRevisionResource)
+			r.Configure(ctx, fwresource.ConfigureRequest{ProviderData: testProviderData(t, server.URL)}, &fwresource.ConfigureResponse{})
+
+			var schemaResp fwresource.SchemaResponse
+			r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+			objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: testObjectValue(objectType, map[string]tftypes.Value{
+				"api_id":          tftypes.NewValue(tftypes.String, "api"),
+				"prune_revisions": tftypes.NewValue(tftypes.Bool, c.pruneRevisions),
+			})}
+
+			resp := fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+			r.Create(ctx, fwresource.CreateRequest{Plan: plan}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+			if !reflect.DeepEqual(deleted, c.wantDeleted) {
+				t.Errorf("expected %+v, got %+v", c.wantDeleted, deleted)
+			}
+			var state apiRevisionResourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+			if state.ID.ValueString() != "r6" {
+				t.Errorf("expected %+v, got %+v", "r6", state.ID.ValueString())
+			}
+		})
+	}
+}
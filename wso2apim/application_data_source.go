@@ -12,7 +12,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource = &applicationDataSource{}
+	_ datasource.DataSource              = &applicationDataSource{}
+	_ datasource.DataSourceWithConfigure = &applicationDataSource{}
 )
 
 // NewApplicationDataSource is a helper function to simplify the provider implementation.
@@ -22,6 +23,7 @@ func NewApplicationDataSource() datasource.DataSource {
 
 // applicationDataSource is the data source implementation.
 type applicationDataSource struct {
+	client *apim.Client
 }
 
 // applicationDataSourceModel maps the data source schema data.
@@ -37,6 +39,15 @@ type applicationDataSourceModel struct {
 	TokenType         types.String `tfsdk:"token_type"`
 }
 
+// Configure adds the provider configured client to the data source.
+func (d *applicationDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
 // Metadata returns the data source type name.
 func (d *applicationDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_application"
@@ -97,7 +108,7 @@ func (d *applicationDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	application, err := apim.GetApplication(state.ID.ValueString())
+	application, err := d.client.GetApplication(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading WSO2 API Manager Application",
@@ -19,12 +19,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
 	_ resource.Resource                = &applicationKeyMappingResource{}
 	_ resource.ResourceWithImportState = &applicationKeyMappingResource{}
+	_ resource.ResourceWithConfigure   = &applicationKeyMappingResource{}
 )
 
 // NewApplicationKeyMappingResource is a helper function to simplify the provider implementation.
@@ -34,6 +36,7 @@ func NewApplicationKeyMappingResource() resource.Resource {
 
 // applicationKeyMappingResource is the resource implementation.
 type applicationKeyMappingResource struct {
+	client *apim.Client
 }
 
 // applicationKeyMappingResourceModel maps the resource schema data.
@@ -52,6 +55,15 @@ type applicationKeyMappingResourceModel struct {
 	LastUpdated  types.String `tfsdk:"last_updated"`
 }
 
+// Configure adds the provider configured client to the resource.
+func (r *applicationKeyMappingResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
 // Metadata returns the resource type name.
 func (r *applicationKeyMappingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_application_key_mapping"
@@ -157,7 +169,7 @@ func (r *applicationKeyMappingResource) Create(ctx context.Context, req resource
 	}
 
 	// Create new applicationKeyMapping
-	applicationKeyMapping, err := apim.GenerateKeys(plan.ApplicationID.ValueString(), &apim.ApplicationKeyGenerateRequest{
+	applicationKeyMapping, err := r.client.GenerateKeys(ctx, plan.ApplicationID.ValueString(), &apim.ApplicationKeyGenerateRequest{
 		KeyType:                 plan.KeyType.ValueString(),
 		KeyManager:              plan.KeyManager.ValueString(),
 		GrantTypesToBeSupported: plan.SupportedGrantTypes,
@@ -205,7 +217,13 @@ func (r *applicationKeyMappingResource) Read(ctx context.Context, req resource.R
 	}
 
 	// Get refreshed applicationKeyMapping value from WSO2 API Manager
-	applicationKeyMapping, err := apim.GetApplicationKeys(state.ApplicationID.ValueString(), state.ID.ValueString())
+	applicationKeyMapping, err := r.client.GetApplicationKeys(ctx, state.ApplicationID.ValueString(), state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		// The application keys was deleted outside of Terraform, let Terraform plan to re-create it.
+		tflog.Warn(ctx, "WSO2 API Manager Application Keys not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading WSO2 API Manager Application Keys",
@@ -242,7 +260,7 @@ func (r *applicationKeyMappingResource) Update(ctx context.Context, req resource
 		return
 	}
 
-	mapping, err := apim.UpdateApplicationKeys(plan.ApplicationID.ValueString(), plan.ID.ValueString(), &apim.ApplicationKeyGenerateRequest{
+	mapping, err := r.client.UpdateApplicationKeys(ctx, plan.ApplicationID.ValueString(), plan.ID.ValueString(), &apim.ApplicationKeyGenerateRequest{
 		GrantTypesToBeSupported: plan.SupportedGrantTypes,
 		// CallbackURL:             plan.CallbackURL.ValueString(),
 	})
@@ -254,7 +272,7 @@ func (r *applicationKeyMappingResource) Update(ctx context.Context, req resource
 		return
 	}
 
-	// keys, err := apim.RegenerateKeys(plan.ApplicationID.ValueString(), plan.ID.ValueString())
+	// keys, err := r.client.RegenerateKeys(ctx, plan.ApplicationID.ValueString(), plan.ID.ValueString())
 	// if err != nil {
 	// 	resp.Diagnostics.AddError(
 	// 		"Error regenerating application keys",
@@ -289,7 +307,7 @@ func (r *applicationKeyMappingResource) Delete(ctx context.Context, req resource
 	}
 
 	// Delete existing applicationKeyMapping
-	err := apim.CleanupKeys(state.ApplicationID.ValueString(), state.ID.ValueString())
+	err := r.client.CleanupKeys(ctx, state.ApplicationID.ValueString(), state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Cleaning up WSO2 API Manager Application Keys",
@@ -14,12 +14,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
 	_ resource.Resource                = &applicationResource{}
 	_ resource.ResourceWithImportState = &applicationResource{}
+	_ resource.ResourceWithConfigure   = &applicationResource{}
 )
 
 // NewApplicationResource is a helper function to simplify the provider implementation.
@@ -29,6 +31,7 @@ func NewApplicationResource() resource.Resource {
 
 // applicationResource is the resource implementation.
 type applicationResource struct {
+	client *apim.Client
 }
 
 // applicationResourceModel maps the resource schema data.
@@ -45,6 +48,15 @@ type applicationResourceModel struct {
 	LastUpdated       types.String `tfsdk:"last_updated"`
 }
 
+// Configure adds the provider configured client to the resource.
+func (r *applicationResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
 // Metadata returns the resource type name.
 func (r *applicationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_application"
@@ -128,7 +140,7 @@ func (r *applicationResource) Create(ctx context.Context, req resource.CreateReq
 	}
 
 	// Create new application
-	application, err := apim.CreateApplication(&apim.ApplicationCreateReq{
+	application, err := r.client.CreateApplication(ctx, &apim.ApplicationCreateReq{
 		Name:             plan.Name.ValueString(),
 		TokenType:        plan.TokenType.ValueString(),
 		ThrottlingPolicy: plan.ThrottlingPolicy.ValueString(),
@@ -178,7 +190,13 @@ func (r *applicationResource) Read(ctx context.Context, req resource.ReadRequest
 	}
 
 	// Get refreshed application value from WSO2 API Manager
-	application, err := apim.GetApplication(state.ID.ValueString())
+	application, err := r.client.GetApplication(ctx, state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		// The application was deleted outside of Terraform, let Terraform plan to re-create it.
+		tflog.Warn(ctx, "WSO2 API Manager Application not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading WSO2 API Manager Application",
@@ -226,7 +244,7 @@ func (r *applicationResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	// Create new application
-	application, err := apim.UpdateApplication(plan.ID.ValueString(), &apim.ApplicationCreateReq{
+	application, err := r.client.UpdateApplication(ctx, plan.ID.ValueString(), &apim.ApplicationCreateReq{
 		Name:             plan.Name.ValueString(),
 		TokenType:        plan.TokenType.ValueString(),
 		ThrottlingPolicy: plan.ThrottlingPolicy.ValueString(),
@@ -274,7 +292,7 @@ func (r *applicationResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 
 	// Delete existing application
-	err := apim.DeleteApplication(state.ID.ValueString())
+	err := r.client.DeleteApplication(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting WSO2 API Manager Application",
@@ -11,7 +11,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource = &keyManagerDataSource{}
+	_ datasource.DataSource              = &keyManagerDataSource{}
+	_ datasource.DataSourceWithConfigure = &keyManagerDataSource{}
 )
 
 // NewKeyManagerDataSource is a helper function to simplify the provider implementation.
@@ -21,6 +22,7 @@ func NewKeyManagerDataSource() datasource.DataSource {
 
 // keyManagerDataSource is the data source implementation.
 type keyManagerDataSource struct {
+	client *apim.Client
 }
 
 // keyManagerDataSourceModel maps the data source schema data.
@@ -36,6 +38,15 @@ type keyManagerDataSourceModel struct {
 	RevokeEndpoint      types.String `tfsdk:"revoke_endpoint"`
 }
 
+// Configure adds the provider configured client to the data source.
+func (d *keyManagerDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
 // Metadata returns the data source type name.
 func (d *keyManagerDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_key_manager"
@@ -99,7 +110,7 @@ func (d *keyManagerDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	var keyManager *apim.KeyManagerSearchInfo
 
 	if !state.ID.IsUnknown() && !state.ID.IsNull() {
-		km, err := apim.GetKeyManager(state.ID.ValueString())
+		km, err := d.client.GetKeyManager(ctx, state.ID.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Reading WSO2 API Manager KeyManager",
@@ -109,7 +120,7 @@ func (d *keyManagerDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		}
 		keyManager = km
 	} else if !state.Name.IsUnknown() && !state.Name.IsNull() {
-		km, err := apim.SearchKeyManager(state.Name.ValueString())
+		km, err := d.client.SearchKeyManager(ctx, state.Name.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Reading WSO2 API Manager KeyManager",
@@ -3,19 +3,29 @@ package wso2apim
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/floydspace/terraform-provider-wso2apim/client"
 	"github.com/floydspace/terraform-provider-wso2apim/token"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/wso2/openservicebroker-apim/pkg/client"
-	apimCfg "github.com/wso2/openservicebroker-apim/pkg/config"
+	"github.com/pkg/errors"
 )
 
 // Ensure the implementation satisfies the expected interfaces
@@ -39,10 +49,36 @@ type wso2apimProvider struct {
 
 // wso2apimProviderModel maps provider schema data to a Go type.
 type wso2apimProviderModel struct {
-	Host             types.String `tfsdk:"host"`
-	Username         types.String `tfsdk:"username"`
-	Password         types.String `tfsdk:"password"`
-	ApiContextPrefix types.String `tfsdk:"api_context_prefix"`
+	Host               types.String       `tfsdk:"host"`
+	Username           types.String       `tfsdk:"username"`
+	Password           types.String       `tfsdk:"password"`
+	ApiContextPrefix   types.String       `tfsdk:"api_context_prefix"`
+	ApimVersion        types.String       `tfsdk:"apim_version"`
+	InsecureSkipVerify types.Bool         `tfsdk:"insecure_skip_verify"`
+	CACertFile         types.String       `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String       `tfsdk:"ca_cert_pem"`
+	ClientCert         types.String       `tfsdk:"client_cert"`
+	ClientKey          types.String       `tfsdk:"client_key"`
+	RequestTimeout     types.Int64        `tfsdk:"request_timeout"`
+	MaxRetries         types.Int64        `tfsdk:"max_retries"`
+	MinBackoff         types.Int64        `tfsdk:"min_backoff"`
+	MaxBackoff         types.Int64        `tfsdk:"max_backoff"`
+	TokenRefreshSkew   types.Int64        `tfsdk:"token_refresh_skew"`
+	Auth               *wso2apimAuthModel `tfsdk:"auth"`
+}
+
+// wso2apimAuthModel maps the provider auth block to a Go type.
+type wso2apimAuthModel struct {
+	GrantType    types.String `tfsdk:"grant_type"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+}
+
+// wso2apimProviderData is handed to resources and data sources through ResourceData and DataSourceData.
+// Every provider instance (including aliases) builds its own client.
+type wso2apimProviderData struct {
+	Client *apim.Client
+	Config *wso2apimProviderModel
 }
 
 // Metadata returns the provider type name.
@@ -73,6 +109,108 @@ func (p *wso2apimProvider) Schema(_ context.Context, _ provider.SchemaRequest, r
 				Description: "WSO2 API Manager API Context Prefix.",
 				Optional:    true,
 			},
+			"apim_version": schema.StringAttribute{
+				Description: "WSO2 API Manager version, selects the REST API contexts used by the provider. " +
+					"One of `" + strings.Join(apim.SupportedVersions(), "`, `") + "`. " +
+					"`4.3` shares the REST API of `4.2` and is handled, and detected, as `4.2`. " +
+					"When not set, the version is detected by probing the Publisher REST API of the host. " +
+					"May also be provided via the WSO2_APIM_VERSION environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(apim.SupportedVersions()...),
+				},
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip the TLS verification of the WSO2 API Manager certificate. Defaults to `false`. May also be provided via the WSO2_APIM_INSECURE_SKIP_VERIFY environment variable.",
+				Optional:    true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM encoded CA certificate bundle used to verify the WSO2 API Manager certificate. May also be provided via the WSO2_APIM_CA_CERT_FILE environment variable.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_pem")),
+				},
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM encoded CA certificate bundle used to verify the WSO2 API Manager certificate. May also be provided via the WSO2_APIM_CA_CERT_PEM environment variable.",
+				Optional:    true,
+			},
+			"client_cert": schema.StringAttribute{
+				Description: "PEM encoded client certificate used for mutual TLS. May also be provided via the WSO2_APIM_CLIENT_CERT environment variable.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				Description: "PEM encoded client private key used for mutual TLS. May also be provided via the WSO2_APIM_CLIENT_KEY environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"request_timeout": schema.Int64Attribute{
+				Description: "Timeout of a single request to WSO2 API Manager in seconds. Defaults to `30`. May also be provided via the WSO2_APIM_REQUEST_TIMEOUT environment variable.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_retries": schema.Int64Attribute{
+				Description: "Maximum number of retries of a request failed with a 429 or 5XX response. Defaults to `3`. May also be provided via the WSO2_APIM_MAX_RETRIES environment variable.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"min_backoff": schema.Int64Attribute{
+				Description: "Minimum time to wait between two retries in seconds. Defaults to `1`. May also be provided via the WSO2_APIM_MIN_BACKOFF environment variable.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"max_backoff": schema.Int64Attribute{
+				Description: "Maximum time to wait between two retries in seconds. Defaults to `60`. May also be provided via the WSO2_APIM_MAX_BACKOFF environment variable.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"token_refresh_skew": schema.Int64Attribute{
+				Description: "Time before the expiry of the access token at which it is refreshed, in seconds. Defaults to `30`. May also be provided via the WSO2_APIM_TOKEN_REFRESH_SKEW environment variable.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"auth": schema.SingleNestedBlock{
+				Description: "OAuth2 grant used to obtain the access token.",
+				Attributes: map[string]schema.Attribute{
+					"grant_type": schema.StringAttribute{
+						Description: "OAuth2 grant type, one of `password` or `client_credentials`. " +
+							"The `password` grant registers a client dynamically using the username and password, " +
+							"the `client_credentials` grant uses the pre-provisioned client_id and client_secret. " +
+							"Defaults to `password`. May also be provided via the WSO2_APIM_GRANT_TYPE environment variable.",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(token.GrantPassword, token.GrantClientCredentials),
+						},
+					},
+					"client_id": schema.StringAttribute{
+						Description: "OAuth2 client ID used by the `client_credentials` grant. May also be provided via the WSO2_APIM_CLIENT_ID environment variable.",
+						Optional:    true,
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "OAuth2 client secret used by the `client_credentials` grant. May also be provided via the WSO2_APIM_CLIENT_SECRET environment variable.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -119,6 +257,56 @@ func (p *wso2apimProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
+	// The version, TLS, timeout and retry settings select how the client is built, so they must be known too.
+	for _, setting := range []struct {
+		attribute string
+		env       string
+		value     attr.Value
+	}{
+		{"apim_version", "WSO2_APIM_VERSION", config.ApimVersion},
+		{"insecure_skip_verify", "WSO2_APIM_INSECURE_SKIP_VERIFY", config.InsecureSkipVerify},
+		{"ca_cert_file", "WSO2_APIM_CA_CERT_FILE", config.CACertFile},
+		{"ca_cert_pem", "WSO2_APIM_CA_CERT_PEM", config.CACertPEM},
+		{"client_cert", "WSO2_APIM_CLIENT_CERT", config.ClientCert},
+		{"client_key", "WSO2_APIM_CLIENT_KEY", config.ClientKey},
+		{"request_timeout", "WSO2_APIM_REQUEST_TIMEOUT", config.RequestTimeout},
+		{"max_retries", "WSO2_APIM_MAX_RETRIES", config.MaxRetries},
+		{"min_backoff", "WSO2_APIM_MIN_BACKOFF", config.MinBackoff},
+		{"max_backoff", "WSO2_APIM_MAX_BACKOFF", config.MaxBackoff},
+		{"token_refresh_skew", "WSO2_APIM_TOKEN_REFRESH_SKEW", config.TokenRefreshSkew},
+	} {
+		if setting.value.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(setting.attribute),
+				"Unknown WSO2 API Manager "+setting.attribute,
+				"The provider cannot create the WSO2 API Manager client as there is an unknown configuration value for the "+setting.attribute+" setting. "+
+					"Either target apply the source of the value first, set the value statically in the configuration, or use the "+setting.env+" environment variable.",
+			)
+		}
+	}
+
+	// The auth settings select the token manager, so they must be known too.
+	if config.Auth != nil {
+		for _, setting := range []struct {
+			attribute string
+			env       string
+			value     attr.Value
+		}{
+			{"grant_type", "WSO2_APIM_GRANT_TYPE", config.Auth.GrantType},
+			{"client_id", "WSO2_APIM_CLIENT_ID", config.Auth.ClientID},
+			{"client_secret", "WSO2_APIM_CLIENT_SECRET", config.Auth.ClientSecret},
+		} {
+			if setting.value.IsUnknown() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("auth").AtName(setting.attribute),
+					"Unknown WSO2 API Manager "+setting.attribute,
+					"The provider cannot create the WSO2 API Manager client as there is an unknown configuration value for the "+setting.attribute+" setting. "+
+						"Either target apply the source of the value first, set the value statically in the configuration, or use the "+setting.env+" environment variable.",
+				)
+			}
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -155,23 +343,70 @@ func (p *wso2apimProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
-	if username == "" {
+	if u, err := url.Parse(host); host != "" && (err != nil || u.Scheme == "" || u.Host == "") {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("username"),
-			"Missing WSO2 API Manager Username",
-			"The provider cannot create the WSO2 API Manager client as there is a missing or empty value for the WSO2 API Manager Username. "+
-				"Set the username value in the configuration or use the WSO2_APIM_USERNAME environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("host"),
+			"Invalid WSO2 API Manager Host",
+			"The provider cannot create the WSO2 API Manager client as the host is not a valid URL. "+
+				"Set the host value to a URL with a scheme such as https://localhost:9443.",
 		)
 	}
 
-	if password == "" {
+	auth := config.Auth
+	if auth == nil {
+		auth = &wso2apimAuthModel{}
+	}
+	grantType := stringValue(auth.GrantType, "WSO2_APIM_GRANT_TYPE")
+	clientID := stringValue(auth.ClientID, "WSO2_APIM_CLIENT_ID")
+	clientSecret := stringValue(auth.ClientSecret, "WSO2_APIM_CLIENT_SECRET")
+
+	switch grantType {
+	case "", token.GrantPassword:
+		grantType = token.GrantPassword
+		if username == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("username"),
+				"Missing WSO2 API Manager Username",
+				"The provider cannot create the WSO2 API Manager client as there is a missing or empty value for the WSO2 API Manager Username. "+
+					"Set the username value in the configuration or use the WSO2_APIM_USERNAME environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+
+		if password == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("password"),
+				"Missing WSO2 API Manager Password",
+				"The provider cannot create the WSO2 API Manager client as there is a missing or empty value for the WSO2 API Manager Password. "+
+					"Set the password value in the configuration or use the WSO2_APIM_PASSWORD environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+	case token.GrantClientCredentials:
+		if clientID == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("auth").AtName("client_id"),
+				"Missing WSO2 API Manager Client ID",
+				"The provider cannot create the WSO2 API Manager client as there is a missing or empty value for the OAuth2 client ID required by the client_credentials grant. "+
+					"Set the client_id value in the auth block or use the WSO2_APIM_CLIENT_ID environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+
+		if clientSecret == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("auth").AtName("client_secret"),
+				"Missing WSO2 API Manager Client Secret",
+				"The provider cannot create the WSO2 API Manager client as there is a missing or empty value for the OAuth2 client secret required by the client_credentials grant. "+
+					"Set the client_secret value in the auth block or use the WSO2_APIM_CLIENT_SECRET environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+	default:
 		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Missing WSO2 API Manager Password",
-			"The provider cannot create the WSO2 API Manager client as there is a missing or empty value for the WSO2 API Manager Password. "+
-				"Set the password value in the configuration or use the WSO2_APIM_PASSWORD environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("auth").AtName("grant_type"),
+			"Invalid WSO2_APIM_GRANT_TYPE Environment Variable",
+			"The WSO2_APIM_GRANT_TYPE environment variable must be one of password or client_credentials, got: "+grantType,
 		)
 	}
 
@@ -181,68 +416,260 @@ func (p *wso2apimProvider) Configure(ctx context.Context, req provider.Configure
 
 	ctx = tflog.SetField(ctx, "wso2apim_host", host)
 	ctx = tflog.SetField(ctx, "wso2apim_username", username)
+	ctx = tflog.SetField(ctx, "wso2apim_grant_type", grantType)
 	ctx = tflog.SetField(ctx, "wso2apim_password", password)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "wso2apim_password")
 
 	tflog.Debug(ctx, "Creating WSO2 API Manager client")
 
-	apimConf := apim.APIM{
-		Username:                         username,
-		Password:                         password,
-		TokenEndpoint:                    host + "/oauth2",
-		DynamicClientEndpoint:            host,
-		DynamicClientRegistrationContext: "/client-registration/v0.17/register",
-		PublisherEndpoint:                host,
-		PublisherAPIContext:              "/api/am/publisher/v1/apis",
-		StoreEndpoint:                    host,
-		StoreApplicationContext:          "/api/am/store/v1/applications",
-		StoreKeyManagerContext:           "/api/am/store/v1/key-managers",
-		StoreSubscriptionContext:         "/api/am/store/v1/subscriptions",
-		StoreMultipleSubscriptionContext: "/api/am/store/v1/subscriptions/multiple",
-	}
-
-	client.Configure(&apimCfg.Client{
-		InsecureCon: true,
-		MinBackOff:  1,
-		MaxBackOff:  60,
-		Timeout:     30,
-		MaxRetries:  3,
-	})
+	clientConfig := p.clientConfig(config, &resp.Diagnostics)
+	refreshSkew := int64Value(config.TokenRefreshSkew, "token_refresh_skew", "WSO2_APIM_TOKEN_REFRESH_SKEW", int64(token.DefaultRefreshSkew/time.Second), 0, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpClient, err := client.New(clientConfig)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid WSO2 API Manager TLS Configuration",
+			"The provider cannot create the WSO2 API Manager client as the TLS configuration is invalid. "+
+				"Check the ca_cert_file, ca_cert_pem, client_cert and client_key values.\n\n"+
+				"Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	profile := p.versionProfile(ctx, config, host, httpClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = tflog.SetField(ctx, "wso2apim_version", profile.Version)
+	apimConf := profile.Config(host, username, password)
 
 	// Initialize Token manager.
-	tManager := &token.PasswordRefreshTokenGrantManager{
-		TokenEndpoint:                    apimConf.TokenEndpoint,
-		DynamicClientEndpoint:            apimConf.DynamicClientEndpoint,
-		DynamicClientRegistrationContext: apimConf.DynamicClientRegistrationContext,
-		UserName:                         apimConf.Username,
-		Password:                         apimConf.Password,
-	}
-	tManager.Init([]string{
+	var tManager token.Manager
+	switch grantType {
+	case token.GrantClientCredentials:
+		tManager = &token.ClientCredentialsGrantManager{
+			TokenEndpoint: apimConf.TokenEndpoint,
+			ClientID:      clientID,
+			ClientSecret:  clientSecret,
+			HTTPClient:    httpClient,
+			RefreshSkew:   time.Duration(refreshSkew) * time.Second,
+		}
+	default:
+		tManager = &token.PasswordRefreshTokenGrantManager{
+			TokenEndpoint:                    apimConf.TokenEndpoint,
+			DynamicClientEndpoint:            apimConf.DynamicClientEndpoint,
+			DynamicClientRegistrationContext: apimConf.DynamicClientRegistrationContext,
+			UserName:                         apimConf.Username,
+			Password:                         apimConf.Password,
+			HTTPClient:                       httpClient,
+			RefreshSkew:                      time.Duration(refreshSkew) * time.Second,
+		}
+	}
+	// Create a new WSO2 client using the configuration values
+	apimClient, err := apim.New(tManager, httpClient, apimConf)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("host"),
+			"Invalid WSO2 API Manager Host",
+			"The provider cannot create the WSO2 API Manager client as the REST API endpoints cannot be constructed from the host. "+
+				"Ensure the host value is a valid URL such as https://localhost:9443.\n\n"+
+				"Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	err = tManager.Init(ctx, []string{
 		token.ScopeSubscribe,
 		token.ScopeAPIView,
 		token.ScopeAPICreate,
 		token.ScopeAppPublish,
 		token.ScopeAPIDelete,
 		token.ScopeAppManage,
+		token.ScopeSharedScopeManage,
 	})
+	if err != nil {
+		addTokenInitError(&resp.Diagnostics, grantType, err)
+		return
+	}
+
+	providerData := &wso2apimProviderData{
+		Client: apimClient,
+		Config: &config,
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 
-	defer func() {
-		if r := recover(); r != nil {
-			resp.Diagnostics.AddError(
-				"Unable to Create WSO2 API Manager Client",
-				"An unexpected error occurred when creating the WSO2 API Manager client. "+
-					"If the error is not clear, please contact the provider developers.\n\n"+
-					"Client Error: "+fmt.Sprintf("%v", r),
+	tflog.Info(ctx, "Configured WSO2 API Manager client", map[string]any{"success": true})
+}
+
+// addTokenInitError adds the diagnostic of the given token manager initialization error.
+// Rejected credentials are reported on the credential attributes of the grant, any other failure on the host.
+func addTokenInitError(diags *diag.Diagnostics, grantType string, err error) {
+	var invokeErr *client.InvokeError
+	if !errors.As(err, &invokeErr) {
+		diags.AddAttributeError(
+			path.Root("host"),
+			"Unable to Reach WSO2 API Manager",
+			"The provider cannot obtain an access token as the WSO2 API Manager token endpoint is unreachable. "+
+				"Ensure the host value is correct and the server is running.\n\n"+
+				"Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	switch invokeErr.StatusCode {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		if grantType == token.GrantClientCredentials {
+			diags.AddAttributeError(
+				path.Root("auth").AtName("client_secret"),
+				"Invalid WSO2 API Manager Client Credentials",
+				"The provider cannot obtain an access token as WSO2 API Manager rejected the client_id and client_secret.\n\n"+
+					"Client Error: "+err.Error(),
 			)
+			return
 		}
-	}()
+		diags.AddAttributeError(
+			path.Root("password"),
+			"Invalid WSO2 API Manager Credentials",
+			"The provider cannot obtain an access token as WSO2 API Manager rejected the username and password.\n\n"+
+				"Client Error: "+err.Error(),
+		)
+	default:
+		diags.AddAttributeError(
+			path.Root("host"),
+			"Unable to Obtain WSO2 API Manager Access Token",
+			"The provider cannot obtain an access token as the WSO2 API Manager token endpoint returned an unexpected response. "+
+				"Ensure the host and apim_version values are correct.\n\n"+
+				"Client Error: "+err.Error(),
+		)
+	}
+}
 
-	// Create a new WSO2 client using the configuration values
-	apim.Init(tManager, apimConf)
+// clientConfig returns the HTTP client settings from the given configuration.
+// Values not set in the configuration default to environment variables, then to built-in defaults.
+func (p *wso2apimProvider) clientConfig(config wso2apimProviderModel, diags *diag.Diagnostics) *client.Config {
+	insecure := boolValue(config.InsecureSkipVerify, "insecure_skip_verify", "WSO2_APIM_INSECURE_SKIP_VERIFY", false, diags)
+	timeout := int64Value(config.RequestTimeout, "request_timeout", "WSO2_APIM_REQUEST_TIMEOUT", 30, 1, diags)
+	maxRetries := int64Value(config.MaxRetries, "max_retries", "WSO2_APIM_MAX_RETRIES", 3, 0, diags)
+	minBackoff := int64Value(config.MinBackoff, "min_backoff", "WSO2_APIM_MIN_BACKOFF", 1, 0, diags)
+	maxBackoff := int64Value(config.MaxBackoff, "max_backoff", "WSO2_APIM_MAX_BACKOFF", 60, 0, diags)
+
+	caCertPEM := stringValue(config.CACertPEM, "WSO2_APIM_CA_CERT_PEM")
+	if caCertFile := stringValue(config.CACertFile, "WSO2_APIM_CA_CERT_FILE"); caCertFile != "" {
+		b, err := os.ReadFile(caCertFile)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Unable to Read WSO2 API Manager CA Certificate File",
+				"The provider cannot read the CA certificate file "+caCertFile+": "+err.Error(),
+			)
+		}
+		caCertPEM = string(b)
+	}
 
-	resp.ResourceData = &config
+	if minBackoff > maxBackoff {
+		diags.AddAttributeError(
+			path.Root("min_backoff"),
+			"Invalid WSO2 API Manager Retry Backoff",
+			fmt.Sprintf("The min_backoff value (%d) must not be greater than the max_backoff value (%d).", minBackoff, maxBackoff),
+		)
+	}
 
-	tflog.Info(ctx, "Configured WSO2 API Manager client", map[string]any{"success": true})
+	return &client.Config{
+		InsecureCon:   insecure,
+		CACertPEM:     caCertPEM,
+		ClientCertPEM: stringValue(config.ClientCert, "WSO2_APIM_CLIENT_CERT"),
+		ClientKeyPEM:  stringValue(config.ClientKey, "WSO2_APIM_CLIENT_KEY"),
+		Timeout:       int(timeout),
+		MaxRetries:    int(maxRetries),
+		MinBackOff:    int(minBackoff),
+		MaxBackOff:    int(maxBackoff),
+	}
+}
+
+// versionProfile returns the API-M version profile from the given configuration.
+// Value not set in the configuration defaults to the WSO2_APIM_VERSION environment variable,
+// then to the version detected from the given host.
+func (p *wso2apimProvider) versionProfile(ctx context.Context, config wso2apimProviderModel, host string, httpClient *client.Client, diags *diag.Diagnostics) apim.VersionProfile {
+	if version := stringValue(config.ApimVersion, "WSO2_APIM_VERSION"); version != "" {
+		profile, err := apim.GetVersionProfile(version)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("apim_version"),
+				"Invalid WSO2 API Manager Version",
+				"The provider cannot create the WSO2 API Manager client as the version is not supported: "+err.Error(),
+			)
+		}
+		return profile
+	}
+
+	tflog.Debug(ctx, "Detecting WSO2 API Manager version")
+	profile, err := apim.DetectVersionProfile(ctx, httpClient, host)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("apim_version"),
+			"Unable to Detect WSO2 API Manager Version",
+			"The provider cannot detect the WSO2 API Manager version of the host. "+
+				"Set the apim_version value in the configuration or use the WSO2_APIM_VERSION environment variable.\n\n"+
+				"Client Error: "+err.Error(),
+		)
+	}
+	return profile
+}
+
+// stringValue returns the configured value, or the value of the given environment variable if not configured.
+func stringValue(v types.String, env string) string {
+	if !v.IsNull() {
+		return v.ValueString()
+	}
+	return os.Getenv(env)
+}
+
+// boolValue returns the configured value, or the value of the given environment variable if not configured,
+// or the given default value if neither is set.
+func boolValue(v types.Bool, attr, env string, def bool, diags *diag.Diagnostics) bool {
+	if !v.IsNull() {
+		return v.ValueBool()
+	}
+	s := os.Getenv(env)
+	if s == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attr),
+			"Invalid "+env+" Environment Variable",
+			"The "+env+" environment variable must be a boolean, got: "+s,
+		)
+	}
+	return b
+}
+
+// int64Value returns the configured value, or the value of the given environment variable if not configured,
+// or the given default value if neither is set.
+// The environment variable must be at least the given minimum, as the configured value is by the attribute validators.
+func int64Value(v types.Int64, attr, env string, def, min int64, diags *diag.Diagnostics) int64 {
+	if !v.IsNull() {
+		return v.ValueInt64()
+	}
+	s := os.Getenv(env)
+	if s == "" {
+		return def
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || i < min {
+		diags.AddAttributeError(
+			path.Root(attr),
+			"Invalid "+env+" Environment Variable",
+			fmt.Sprintf("The %s environment variable must be an integer of at least %d, got: %s", env, min, s),
+		)
+	}
+	return i
 }
 
 // DataSources defines the data sources implemented in the provider.
@@ -259,6 +686,10 @@ func (p *wso2apimProvider) DataSources(_ context.Context) []func() datasource.Da
 func (p *wso2apimProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewApiResource,
+		NewApiRevisionResource,
+		NewApiDeploymentResource,
+		NewApiProductResource,
+		NewScopeResource,
 		NewApplicationResource,
 		NewApplicationKeyMappingResource,
 		NewSubscriptionResource,
@@ -1,8 +1,22 @@
 package wso2apim
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/floydspace/terraform-provider-wso2apim/token"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 const (
@@ -15,6 +29,8 @@ provider "wso2apim" {
 	host	 = "https://localhost:9443"
 	username = "NPA"
 	password = "123456"
+
+	insecure_skip_verify = true
 }
 `
 )
@@ -28,3 +44,155 @@ var (
 		"wso2apim": providerserver.NewProtocol6WithError(New("test")()),
 	}
 )
+
+// testProviderConfig returns the provider configuration with the given attribute values, the other attributes being null.
+func testProviderConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+	var schemaResp provider.SchemaResponse
+	New("test")().Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	return tfsdk.Config{Schema: schemaResp.Schema, Raw: testObjectValue(objectType, values)}
+}
+
+// testObjectValue returns an object of the given type with the given attribute values, the other attributes being null.
+func testObjectValue(objectType tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+	return tftypes.NewValue(objectType, attributes)
+}
+
+// testClientCredentialsAuth returns the auth block of the client_credentials grant.
+func testClientCredentialsAuth() tftypes.Value {
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"grant_type":    tftypes.String,
+		"client_id":     tftypes.String,
+		"client_secret": tftypes.String,
+	}}, map[string]tftypes.Value{
+		"grant_type":    tftypes.NewValue(tftypes.String, "client_credentials"),
+		"client_id":     tftypes.NewValue(tftypes.String, "client"),
+		"client_secret": tftypes.NewValue(tftypes.String, "secret"),
+	})
+}
+
+// testProviderData configures the provider against the given host with the client_credentials grant
+// and returns the data handed to the resources.
+func testProviderData(t *testing.T, host string) any {
+	t.Helper()
+	req := provider.ConfigureRequest{Config: testProviderConfig(t, map[string]tftypes.Value{
+		"host":         tftypes.NewValue(tftypes.String, host),
+		"apim_version": tftypes.NewValue(tftypes.String, "4.2"),
+		"auth":         testClientCredentialsAuth(),
+	})}
+	var resp provider.ConfigureResponse
+	New("test")().Configure(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	return resp.ResourceData
+}
+
+func TestConfigureTokenScopes(t *testing.T) {
+	var scopes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2/token" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		scopes = strings.Fields(r.PostForm.Get("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+	}))
+	defer server.Close()
+
+	testProviderData(t, server.URL)
+
+	for _, scope := range []string{
+		token.ScopeSubscribe,
+		token.ScopeAPIView,
+		token.ScopeAPICreate,
+		token.ScopeAppPublish,
+		token.ScopeAPIDelete,
+		token.ScopeAppManage,
+		token.ScopeSharedScopeManage,
+	} {
+		if !slices.Contains(scopes, scope) {
+			t.Errorf("expected the token request to send the %s scope, got: %v", scope, scopes)
+		}
+	}
+}
+
+func TestConfigureClientSettings(t *testing.T) {
+	cases := []struct {
+		name   string
+		values map[string]tftypes.Value
+		env    map[string]string
+		path   path.Path
+	}{
+		{
+			name:   "environment variable below the minimum",
+			values: map[string]tftypes.Value{},
+			env:    map[string]string{"WSO2_APIM_REQUEST_TIMEOUT": "0"},
+			path:   path.Root("request_timeout"),
+		},
+		{
+			name:   "negative environment variable",
+			values: map[string]tftypes.Value{},
+			env:    map[string]string{"WSO2_APIM_MAX_RETRIES": "-1"},
+			path:   path.Root("max_retries"),
+		},
+		{
+			name:   "unknown timeout",
+			values: map[string]tftypes.Value{"request_timeout": tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)},
+			path:   path.Root("request_timeout"),
+		},
+		{
+			name:   "unknown tls setting",
+			values: map[string]tftypes.Value{"ca_cert_pem": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+			path:   path.Root("ca_cert_pem"),
+		},
+		{
+			name: "unknown auth setting",
+			values: map[string]tftypes.Value{"auth": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"grant_type":    tftypes.String,
+				"client_id":     tftypes.String,
+				"client_secret": tftypes.String,
+			}}, map[string]tftypes.Value{
+				"grant_type":    tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"client_id":     tftypes.NewValue(tftypes.String, "client"),
+				"client_secret": tftypes.NewValue(tftypes.String, "secret"),
+			})},
+			path: path.Root("auth").AtName("grant_type"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for name, value := range c.env {
+				t.Setenv(name, value)
+			}
+			c.values["host"] = tftypes.NewValue(tftypes.String, "https://localhost:9443")
+			c.values["username"] = tftypes.NewValue(tftypes.String, "admin")
+			c.values["password"] = tftypes.NewValue(tftypes.String, "admin")
+			c.values["apim_version"] = tftypes.NewValue(tftypes.String, "4.2")
+
+			var resp provider.ConfigureResponse
+			New("test")().Configure(context.Background(), provider.ConfigureRequest{Config: testProviderConfig(t, c.values)}, &resp)
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error")
+			}
+			for _, d := range resp.Diagnostics.Errors() {
+				if withPath, ok := d.(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(c.path) {
+					t.Errorf("expected the error on %s, got: %s: %s", c.path, d.Summary(), d.Detail())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package wso2apim
+
+import (
+	"context"
+	"time"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &scopeResource{}
+	_ resource.ResourceWithImportState = &scopeResource{}
+	_ resource.ResourceWithConfigure   = &scopeResource{}
+)
+
+// NewScopeResource is a helper function to simplify the provider implementation.
+func NewScopeResource() resource.Resource {
+	return &scopeResource{}
+}
+
+// scopeResource is the resource implementation.
+type scopeResource struct {
+	client *apim.Client
+}
+
+// scopeResourceModel maps the resource schema data.
+type scopeResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	DisplayName types.String `tfsdk:"display_name"`
+	Description types.String `tfsdk:"description"`
+	Roles       []string     `tfsdk:"roles"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *scopeResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
+// Metadata returns the resource type name.
+func (r *scopeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_scope"
+}
+
+// Schema defines the schema for the resource.
+func (r *scopeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a WSO2 API Manager shared Scope, an OAuth scope which can be used by several apis.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Scope ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the scope, requested by the applications.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Description: "Display name of the scope. Defaults to the name of the scope.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the scope.",
+				Optional:    true,
+			},
+			"roles": schema.ListAttribute{
+				Description: "Roles allowed to request the scope, any user can request a scope without roles.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Last updated timestamp.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Create a new resource
+func (r *scopeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan scopeResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new scope
+	scope, err := r.client.CreateScope(ctx, scopeRequest(&plan))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating scope",
+			"Could not create scope, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to schema and populate Computed attribute values
+	scopeState(&plan, scope)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r *scopeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state scopeResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get refreshed scope value from WSO2 API Manager
+	scope, err := r.client.GetScope(ctx, state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		// The scope was deleted outside of Terraform, let Terraform plan to re-create it.
+		tflog.Warn(ctx, "WSO2 API Manager Scope not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading WSO2 API Manager Scope",
+			"Could not read scope ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	scopeState(&state, scope)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *scopeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan scopeResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update existing scope
+	scope, err := r.client.UpdateScope(ctx, plan.ID.ValueString(), scopeRequest(&plan))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating scope",
+			"Could not update scope, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update resource state with updated items and timestamp
+	scopeState(&plan, scope)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC3339))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *scopeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state scopeResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete existing scope
+	err := r.client.DeleteScope(ctx, state.ID.ValueString())
+	if err != nil && !apim.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting WSO2 API Manager Scope",
+			"Could not delete scope, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *scopeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// scopeRequest returns the scope spec of the given plan, the display name defaulting to the name.
+func scopeRequest(plan *scopeResourceModel) *apim.Scope {
+	displayName := plan.DisplayName.ValueString()
+	if displayName == "" {
+		displayName = plan.Name.ValueString()
+	}
+	return &apim.Scope{
+		Name:        plan.Name.ValueString(),
+		DisplayName: displayName,
+		Description: plan.Description.ValueString(),
+		Bindings:    append([]string{}, plan.Roles...),
+	}
+}
+
+// scopeState sets the attributes of the given model to the given scope read from WSO2 API Manager.
+func scopeState(model *scopeResourceModel, scope *apim.Scope) {
+	model.ID = types.StringValue(scope.ID)
+	model.Name = types.StringValue(scope.Name)
+	model.DisplayName = types.StringValue(scope.DisplayName)
+	if scope.Description != "" || !model.Description.IsNull() {
+		model.Description = types.StringValue(scope.Description)
+	}
+	model.Roles = append([]string{}, scope.Bindings...)
+}
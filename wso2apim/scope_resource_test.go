@@ -0,0 +1,53 @@
+package wso2apim
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccScopeResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_scope" "test" {
+	name  = "items:read"
+	roles = ["admin"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("wso2apim_scope.test", "id"),
+					resource.TestCheckResourceAttr("wso2apim_scope.test", "display_name", "items:read"),
+					resource.TestCheckResourceAttr("wso2apim_scope.test", "roles.0", "admin"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "wso2apim_scope.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "wso2apim_scope" "test" {
+	name         = "items:read"
+	display_name = "Read items"
+	description  = "Read the items"
+	roles        = ["admin", "Internal/subscriber"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("wso2apim_scope.test", "display_name", "Read items"),
+					resource.TestCheckResourceAttr("wso2apim_scope.test", "description", "Read the items"),
+					resource.TestCheckResourceAttr("wso2apim_scope.test", "roles.#", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -11,7 +11,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource = &subscriptionDataSource{}
+	_ datasource.DataSource              = &subscriptionDataSource{}
+	_ datasource.DataSourceWithConfigure = &subscriptionDataSource{}
 )
 
 // NewSubscriptionDataSource is a helper function to simplify the provider implementation.
@@ -21,6 +22,7 @@ func NewSubscriptionDataSource() datasource.DataSource {
 
 // subscriptionDataSource is the data source implementation.
 type subscriptionDataSource struct {
+	client *apim.Client
 }
 
 // subscriptionDataSourceModel maps the data source schema data.
@@ -33,6 +35,15 @@ type subscriptionDataSourceModel struct {
 	Status                    types.String `tfsdk:"status"`
 }
 
+// Configure adds the provider configured client to the data source.
+func (d *subscriptionDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
 // Metadata returns the data source type name.
 func (d *subscriptionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_subscription"
@@ -80,7 +91,7 @@ func (d *subscriptionDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
-	subscription, err := apim.GetSubscription(state.ID.ValueString())
+	subscription, err := d.client.GetSubscription(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading WSO2 API Manager Subscription",
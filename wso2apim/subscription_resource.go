@@ -5,18 +5,22 @@ import (
 	"time"
 
 	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
 	_ resource.Resource                = &subscriptionResource{}
 	_ resource.ResourceWithImportState = &subscriptionResource{}
+	_ resource.ResourceWithConfigure   = &subscriptionResource{}
 )
 
 // NewSubscriptionResource is a helper function to simplify the provider implementation.
@@ -26,6 +30,7 @@ func NewSubscriptionResource() resource.Resource {
 
 // subscriptionResource is the resource implementation.
 type subscriptionResource struct {
+	client *apim.Client
 }
 
 // subscriptionResourceModel maps the resource schema data.
@@ -33,12 +38,22 @@ type subscriptionResourceModel struct {
 	ID                        types.String `tfsdk:"id"`
 	ApplicationID             types.String `tfsdk:"application_id"`
 	ApiID                     types.String `tfsdk:"api_id"`
+	ApiProductID              types.String `tfsdk:"api_product_id"`
 	ThrottlingPolicy          types.String `tfsdk:"throttling_policy"`
 	RequestedThrottlingPolicy types.String `tfsdk:"requested_throttling_policy"`
 	Status                    types.String `tfsdk:"status"`
 	LastUpdated               types.String `tfsdk:"last_updated"`
 }
 
+// Configure adds the provider configured client to the resource.
+func (r *subscriptionResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*wso2apimProviderData).Client
+}
+
 // Metadata returns the resource type name.
 func (r *subscriptionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_subscription"
@@ -61,8 +76,26 @@ func (r *subscriptionResource) Schema(_ context.Context, _ resource.SchemaReques
 				Required:    true,
 			},
 			"api_id": schema.StringAttribute{
-				Description: "API ID.",
+				Description: "API ID. Exactly one of `api_id` and `api_product_id` must be set.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("api_product_id")),
+				},
+			},
+			"api_product_id": schema.StringAttribute{
+				Description: "API Product ID, subscribing the application to an api product instead of an api. " +
+					"An imported subscription is read into `api_product_id` when WSO2 API Manager reports it is for an api product, " +
+					"which WSO2 API Manager versions not reporting the type of the subscribed api do not.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("api_id")),
+				},
 			},
 			"throttling_policy": schema.StringAttribute{
 				Description: "Throttling policy.",
@@ -96,9 +129,9 @@ func (r *subscriptionResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	// Create new subscription
-	subscription, err := apim.CreateSubscription(&apim.SubscriptionReq{
+	subscription, err := r.client.CreateSubscription(ctx, &apim.SubscriptionReq{
 		ApplicationID:             plan.ApplicationID.ValueString(),
-		ApiID:                     plan.ApiID.ValueString(),
+		ApiID:                     subscribedApiID(&plan),
 		ThrottlingPolicy:          plan.ThrottlingPolicy.ValueString(),
 		RequestedThrottlingPolicy: plan.RequestedThrottlingPolicy.ValueString(),
 	})
@@ -113,7 +146,7 @@ func (r *subscriptionResource) Create(ctx context.Context, req resource.CreateRe
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(subscription.SubscriptionID)
 	plan.ApplicationID = types.StringValue(subscription.ApplicationID)
-	plan.ApiID = types.StringValue(subscription.ApiID)
+	setSubscribedApiID(&plan, subscription.ApiID, subscription.ApiInfo)
 	plan.ThrottlingPolicy = types.StringValue(subscription.ThrottlingPolicy)
 	plan.RequestedThrottlingPolicy = types.StringValue(subscription.RequestedThrottlingPolicy)
 	plan.Status = types.StringValue(subscription.Status)
@@ -138,7 +171,13 @@ func (r *subscriptionResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	// Get refreshed subscription value from WSO2 API Manager
-	subscription, err := apim.GetSubscription(state.ID.ValueString())
+	subscription, err := r.client.GetSubscription(ctx, state.ID.ValueString())
+	if apim.IsNotFound(err) {
+		// The subscription was deleted outside of Terraform, let Terraform plan to re-create it.
+		tflog.Warn(ctx, "WSO2 API Manager Subscription not found, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading WSO2 API Manager Subscription",
@@ -149,7 +188,7 @@ func (r *subscriptionResource) Read(ctx context.Context, req resource.ReadReques
 
 	// Overwrite items with refreshed state
 	state.ApplicationID = types.StringValue(subscription.ApplicationID)
-	state.ApiID = types.StringValue(subscription.ApiID)
+	setSubscribedApiID(&state, subscription.ApiID, subscription.ApiInfo)
 	state.ThrottlingPolicy = types.StringValue(subscription.ThrottlingPolicy)
 	state.RequestedThrottlingPolicy = types.StringValue(subscription.RequestedThrottlingPolicy)
 	state.Status = types.StringValue(subscription.Status)
@@ -172,9 +211,9 @@ func (r *subscriptionResource) Update(ctx context.Context, req resource.UpdateRe
 	}
 
 	// Create new subscription
-	subscription, err := apim.UpdateSubscription(plan.ID.ValueString(), &apim.SubscriptionReq{
+	subscription, err := r.client.UpdateSubscription(ctx, plan.ID.ValueString(), &apim.SubscriptionReq{
 		ApplicationID:             plan.ApplicationID.ValueString(),
-		ApiID:                     plan.ApiID.ValueString(),
+		ApiID:                     subscribedApiID(&plan),
 		ThrottlingPolicy:          plan.ThrottlingPolicy.ValueString(),
 		RequestedThrottlingPolicy: plan.RequestedThrottlingPolicy.ValueString(),
 	})
@@ -189,7 +228,7 @@ func (r *subscriptionResource) Update(ctx context.Context, req resource.UpdateRe
 	// Update resource state with updated items and timestamp
 	plan.ID = types.StringValue(subscription.SubscriptionID)
 	plan.ApplicationID = types.StringValue(subscription.ApplicationID)
-	plan.ApiID = types.StringValue(subscription.ApiID)
+	setSubscribedApiID(&plan, subscription.ApiID, subscription.ApiInfo)
 	plan.ThrottlingPolicy = types.StringValue(subscription.ThrottlingPolicy)
 	plan.RequestedThrottlingPolicy = types.StringValue(subscription.RequestedThrottlingPolicy)
 	plan.Status = types.StringValue(subscription.Status)
@@ -212,7 +251,7 @@ func (r *subscriptionResource) Delete(ctx context.Context, req resource.DeleteRe
 	}
 
 	// Delete existing subscription
-	err := apim.UnSubscribe(state.ID.ValueString())
+	err := r.client.UnSubscribe(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting WSO2 API Manager Subscription",
@@ -225,3 +264,26 @@ func (r *subscriptionResource) Delete(ctx context.Context, req resource.DeleteRe
 func (r *subscriptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 }
+
+// subscribedApiID returns the ID of the api or the api product the given subscription is for.
+// The Developer Portal subscribes to api products as to apis.
+func subscribedApiID(model *subscriptionResourceModel) string {
+	if !model.ApiProductID.IsNull() {
+		return model.ApiProductID.ValueString()
+	}
+	return model.ApiID.ValueString()
+}
+
+// setSubscribedApiID sets the api_product_id of the given subscription to the given ID read from WSO2 API Manager
+// if the subscription is for an api product, and its api_id otherwise.
+// The subscription is for an api product if the given api info has the api product type, or if the api info has no type
+// and the prior api_product_id is set, e.g. on WSO2 API Manager versions not reporting the type.
+func setSubscribedApiID(model *subscriptionResourceModel, apiID string, info apim.SubscriptionRespApiInfo) {
+	if info.Type == apim.APIProductType || (info.Type == "" && !model.ApiProductID.IsNull()) {
+		model.ApiID = types.StringNull()
+		model.ApiProductID = types.StringValue(apiID)
+		return
+	}
+	model.ApiID = types.StringValue(apiID)
+	model.ApiProductID = types.StringNull()
+}
@@ -0,0 +1,46 @@
+package wso2apim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/floydspace/terraform-provider-wso2apim/apim"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSetSubscribedApiID(t *testing.T) {
+	cases := []struct {
+		name  string
+		prior subscriptionResourceModel
+		info  apim.SubscriptionRespApiInfo
+		want  subscriptionResourceModel
+	}{
+		{
+			name:  "imported api subscription",
+			prior: subscriptionResourceModel{ApiID: types.StringNull(), ApiProductID: types.StringNull()},
+			info:  apim.SubscriptionRespApiInfo{Type: "HTTP"},
+			want:  subscriptionResourceModel{ApiID: types.StringValue("1"), ApiProductID: types.StringNull()},
+		},
+		{
+			name:  "imported api product subscription",
+			prior: subscriptionResourceModel{ApiID: types.StringNull(), ApiProductID: types.StringNull()},
+			info:  apim.SubscriptionRespApiInfo{Type: apim.APIProductType},
+			want:  subscriptionResourceModel{ApiID: types.StringNull(), ApiProductID: types.StringValue("1")},
+		},
+		{
+			name:  "api product subscription without type",
+			prior: subscriptionResourceModel{ApiID: types.StringNull(), ApiProductID: types.StringValue("1")},
+			info:  apim.SubscriptionRespApiInfo{},
+			want:  subscriptionResourceModel{ApiID: types.StringNull(), ApiProductID: types.StringValue("1")},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.prior
+			setSubscribedApiID(&got, "1", c.info)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}